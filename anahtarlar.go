@@ -0,0 +1,359 @@
+package lokum
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"sort"
+)
+
+type timeKey struct {
+	sec  int64
+	nsec int
+}
+
+func hashKey(o Object) (interface{}, bool) {
+	switch o := o.(type) {
+	case *String:
+		return o.Value, true
+	case *Int:
+		return o.Value, true
+	case *Char:
+		return o.Value, true
+	case *Bool:
+		return o.value, true
+	case *Float:
+		if math.IsNaN(o.Value) {
+			return nil, false
+		}
+		return o.Value, true
+	case *Time:
+		return timeKey{sec: o.Value.Unix(), nsec: o.Value.Nanosecond()}, true
+	}
+	return nil, false
+}
+
+func IsHashable(o Object) bool {
+	switch o := o.(type) {
+	case *String, *Int, *Char, *Bool, *Time:
+		return true
+	case *Float:
+		return !math.IsNaN(o.Value)
+	}
+	return false
+}
+
+func errNotHashable(o Object) error {
+	return fmt.Errorf("%w: %s", ErrNotHashable, o.TypeName())
+}
+
+type OrderedMap struct {
+	strs    map[string]int
+	ints    map[int64]int
+	others  map[interface{}]int
+	keys    []Object
+	values  []Object
+	size    int
+	deleted int
+}
+
+func NewOrderedMap(capacity int) *OrderedMap {
+	return &OrderedMap{
+		strs:   make(map[string]int, capacity),
+		keys:   make([]Object, 0, capacity),
+		values: make([]Object, 0, capacity),
+	}
+}
+
+func (m *OrderedMap) Len() int {
+	if m == nil {
+		return 0
+	}
+	return m.size
+}
+
+func (m *OrderedMap) find(key Object) (int, bool) {
+	if m == nil {
+		return 0, false
+	}
+	switch key := key.(type) {
+	case *String:
+		idx, ok := m.strs[key.Value]
+		return idx, ok
+	case *Int:
+		idx, ok := m.ints[key.Value]
+		return idx, ok
+	}
+	k, ok := hashKey(key)
+	if !ok || m.others == nil {
+		return 0, false
+	}
+	idx, ok := m.others[k]
+	return idx, ok
+}
+
+func (m *OrderedMap) Get(key Object) (Object, bool) {
+	idx, ok := m.find(key)
+	if !ok {
+		return nil, false
+	}
+	return m.values[idx], true
+}
+
+func (m *OrderedMap) GetString(key string) (Object, bool) {
+	if m == nil {
+		return nil, false
+	}
+	idx, ok := m.strs[key]
+	if !ok {
+		return nil, false
+	}
+	return m.values[idx], true
+}
+
+func (m *OrderedMap) SetString(key string, value Object) {
+	_ = m.Set(&String{Value: key}, value)
+}
+
+func (m *OrderedMap) Has(key Object) bool {
+	_, ok := m.find(key)
+	return ok
+}
+
+func (m *OrderedMap) Set(key, value Object) error {
+	switch k := key.(type) {
+	case *String:
+		if idx, ok := m.strs[k.Value]; ok {
+			m.values[idx] = value
+			return nil
+		}
+		if m.strs == nil {
+			m.strs = make(map[string]int)
+		}
+		m.strs[k.Value] = len(m.keys)
+		m.append(key, value)
+		return nil
+	case *Int:
+		if idx, ok := m.ints[k.Value]; ok {
+			m.values[idx] = value
+			return nil
+		}
+		if m.ints == nil {
+			m.ints = make(map[int64]int)
+		}
+		m.ints[k.Value] = len(m.keys)
+		m.append(key, value)
+		return nil
+	}
+	k, ok := hashKey(key)
+	if !ok {
+		return errNotHashable(key)
+	}
+	if idx, ok := m.others[k]; ok {
+		m.values[idx] = value
+		return nil
+	}
+	if m.others == nil {
+		m.others = make(map[interface{}]int)
+	}
+	m.others[k] = len(m.keys)
+	m.append(key, value)
+	return nil
+}
+
+func (m *OrderedMap) append(key, value Object) {
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, value)
+	m.size++
+}
+
+func (m *OrderedMap) Delete(key Object) bool {
+	idx, ok := m.find(key)
+	if !ok {
+		return false
+	}
+	switch k := key.(type) {
+	case *String:
+		delete(m.strs, k.Value)
+	case *Int:
+		delete(m.ints, k.Value)
+	default:
+		hk, _ := hashKey(key)
+		delete(m.others, hk)
+	}
+	m.keys[idx] = nil
+	m.values[idx] = nil
+	m.size--
+	m.deleted++
+	if m.deleted > 32 && m.deleted > m.size {
+		m.compact()
+	}
+	return true
+}
+
+func (m *OrderedMap) compact() {
+	keys := make([]Object, 0, m.size)
+	values := make([]Object, 0, m.size)
+	for i, k := range m.keys {
+		if k == nil {
+			continue
+		}
+		switch k := k.(type) {
+		case *String:
+			m.strs[k.Value] = len(keys)
+		case *Int:
+			m.ints[k.Value] = len(keys)
+		default:
+			hk, _ := hashKey(k)
+			m.others[hk] = len(keys)
+		}
+		keys = append(keys, k)
+		values = append(values, m.values[i])
+	}
+	m.keys = keys
+	m.values = values
+	m.deleted = 0
+}
+
+func (m *OrderedMap) Keys() []Object {
+	if m == nil {
+		return nil
+	}
+	keys := make([]Object, 0, m.size)
+	for _, k := range m.keys {
+		if k != nil {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (m *OrderedMap) Range(fn func(key, value Object) bool) {
+	if m == nil {
+		return
+	}
+	for i, k := range m.keys {
+		if k == nil {
+			continue
+		}
+		if !fn(k, m.values[i]) {
+			return
+		}
+	}
+}
+
+func (m *OrderedMap) Copy() *OrderedMap {
+	c := NewOrderedMap(m.Len())
+	m.Range(func(key, value Object) bool {
+		_ = c.Set(key, value)
+		return true
+	})
+	return c
+}
+
+func (m *OrderedMap) GobEncode() ([]byte, error) {
+	keys := m.Keys()
+	values := make([]Object, 0, len(keys))
+	m.Range(func(_, value Object) bool {
+		values = append(values, value)
+		return true
+	})
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(keys); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *OrderedMap) GobDecode(b []byte) error {
+	var keys, values []Object
+	dec := gob.NewDecoder(bytes.NewReader(b))
+	if err := dec.Decode(&keys); err != nil {
+		return err
+	}
+	if err := dec.Decode(&values); err != nil {
+		return err
+	}
+	if len(keys) != len(values) {
+		return fmt.Errorf("bozuk harita verisi: %d anahtar, %d değer", len(keys), len(values))
+	}
+	*m = *NewOrderedMap(len(keys))
+	for i, k := range keys {
+		if err := m.Set(k, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orderedMapFromStrings(kv map[string]Object) *OrderedMap {
+	keys := make([]string, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	m := NewOrderedMap(len(keys))
+	for _, k := range keys {
+		m.SetString(k, kv[k])
+	}
+	return m
+}
+
+func keyRank(o Object) int {
+	switch o.(type) {
+	case *Bool:
+		return 0
+	case *Int, *Float, *Char:
+		return 1
+	case *String:
+		return 2
+	case *Time:
+		return 3
+	}
+	return 4
+}
+
+func lessKey(a, b Object) bool {
+	ra, rb := keyRank(a), keyRank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	switch a := a.(type) {
+	case *Bool:
+		return !a.value && b.(*Bool).value
+	case *String:
+		return a.Value < b.(*String).Value
+	case *Time:
+		return a.Value.Before(b.(*Time).Value)
+	case *Int, *Float, *Char:
+		fa, fb := numericKey(a), numericKey(b)
+		if fa != fb {
+			return fa < fb
+		}
+		return a.TypeName() < b.TypeName()
+	}
+	return a.String() < b.String()
+}
+
+func numericKey(o Object) float64 {
+	switch o := o.(type) {
+	case *Int:
+		return float64(o.Value)
+	case *Float:
+		return o.Value
+	case *Char:
+		return float64(o.Value)
+	}
+	return 0
+}
+
+func sortKeys(keys []Object) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+}
@@ -0,0 +1,158 @@
+package lokum
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onrirr/lokum/token"
+)
+
+func TestOrderedMapKeys(t *testing.T) {
+	m := NewOrderedMap(0)
+	keys := []Object{
+		&String{Value: "1"},
+		&Int{Value: 1},
+		&Float{Value: 1},
+		&Char{Value: '1'},
+		TrueValue,
+	}
+	for i, k := range keys {
+		if err := m.Set(k, &Int{Value: int64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.Len() != len(keys) {
+		t.Fatalf("uzunluk %d, beklenen %d", m.Len(), len(keys))
+	}
+	for i, k := range keys {
+		v, ok := m.Get(k)
+		if !ok || v.(*Int).Value != int64(i) {
+			t.Fatalf("%s: %v", k, v)
+		}
+	}
+	if !m.Delete(&Int{Value: 1}) || m.Has(&Int{Value: 1}) {
+		t.Fatal("int anahtar silinmedi")
+	}
+	if !m.Has(&Float{Value: 1}) {
+		t.Fatal("float anahtar kayboldu")
+	}
+	if IsHashable(&Float{Value: nan()}) || IsHashable(&Array{}) {
+		t.Fatal("hashlenemeyen değer kabul edildi")
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a, _ := NewSet(&Int{Value: 1}, &Int{Value: 2}, &Int{Value: 3})
+	b, _ := NewSet(&Int{Value: 2}, &Int{Value: 3}, &Int{Value: 4})
+	tests := []struct {
+		op   token.Token
+		rhs  Object
+		want string
+	}{
+		{token.Or, b, "küme(1, 2, 3, 4)"},
+		{token.And, b, "küme(2, 3)"},
+		{token.Sub, b, "küme(1)"},
+		{token.Xor, b, "küme(1, 4)"},
+		{token.Or, &ImmutableSet{Value: b.Value}, "küme(1, 2, 3, 4)"},
+	}
+	for _, tt := range tests {
+		res, err := a.BinaryOp(tt.op, tt.rhs)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.op, err)
+		}
+		if _, ok := res.(*Set); !ok || res.String() != tt.want {
+			t.Fatalf("%s: %s (%s), beklenen %s", tt.op, res, res.TypeName(), tt.want)
+		}
+	}
+	if a.String() != "küme(1, 2, 3)" || b.String() != "küme(2, 3, 4)" {
+		t.Fatalf("işlenenler değişti: %s, %s", a, b)
+	}
+	if _, err := a.BinaryOp(token.Add, b); err != ErrInvalidOperator {
+		t.Fatalf("küme + küme: %v", err)
+	}
+	if _, err := a.BinaryOp(token.Or, &Array{}); err != ErrInvalidOperator {
+		t.Fatalf("küme | liste: %v", err)
+	}
+}
+
+func TestSetScript(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`k := küme(3, 1, 2, 1)`, "küme(3, 1, 2)"},
+		{`k := küme()`, "küme()"},
+		{`a := küme(1, 2, 3); b := küme(2, 3, 4); k := [a | b, a & b, a - b, a ^ b]`,
+			"[küme(1, 2, 3, 4), küme(2, 3), küme(1), küme(1, 4)]"},
+		{`k := []; tekrarla x in küme("c", "a", "b") { k = ekle(k, x) }`,
+			`["c", "a", "b"]`},
+		{`s := küme(1, 2); s.ekle(3, "a"); k := [s.sil(1), s.sil(9), s]`,
+			`[true, false, küme(2, 3, "a")]`},
+		{`k := [küme(1, 2) == küme(2, 1), küme(1) == küme(1, 2)]`, "[true, false]"},
+		{`k := [uzunluk(küme(1, 2, 2)), küme() ? 1 : 2]`, "[2, 2]"},
+		{`s := sabit(küme(1, 2)); k := [s | küme(3), s.içerir(1), s.içerir(5)]`,
+			"[küme(1, 2, 3), true, false]"},
+		{`s := sabit(küme(1)); k := kopyala(s); k.ekle(2)`, "küme(1, 2)"},
+	}
+	for _, tt := range tests {
+		c, err := NewScript([]byte(tt.src)).Run()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		if got := c.Get("k").Object().String(); got != tt.want {
+			t.Fatalf("%s: %s, beklenen %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		err string
+	}{
+		{`k := sabit(küme(1, 2)); k.ekle(3)`, "küme metodu bulunamadı: ekle"},
+		{`k := sabit(küme(1, 2)); k.sil(1)`, "küme metodu bulunamadı: sil"},
+		{`k := küme([1])`, "anahtar olarak kullanılamaz: array"},
+		{`k := küme(1) | [1]`, "geçersiz operasyon: set | array"},
+	}
+	for _, tt := range tests {
+		_, err := NewScript([]byte(tt.src)).Run()
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatalf("%s: hata %v, beklenen %q", tt.src, err, tt.err)
+		}
+	}
+}
+
+func nan() float64 {
+	zero := 0.0
+	return zero / zero
+}
+
+func BenchmarkMapIndexGetString(b *testing.B) {
+	m := &Map{Value: NewOrderedMap(0)}
+	k := &String{Value: "anahtar"}
+	_ = m.IndexSet(k, k)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.IndexGet(k)
+	}
+}
+
+func BenchmarkMapIndexGetInt(b *testing.B) {
+	m := &Map{Value: NewOrderedMap(0)}
+	k := &Int{Value: 12345}
+	_ = m.IndexSet(k, k)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = m.IndexGet(k)
+	}
+}
+
+func BenchmarkMapIndexSetInt(b *testing.B) {
+	m := &Map{Value: NewOrderedMap(0)}
+	k := &Int{Value: 12345}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = m.IndexSet(k, k)
+	}
+}
@@ -0,0 +1,519 @@
+package lokum
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+type DebugInfo struct {
+	Name string
+	Vars []VarInfo
+	Free []string
+}
+
+type VarInfo struct {
+	Name   string
+	Index  int
+	Global bool
+	Start  parser.Pos
+	End    parser.Pos
+}
+
+func (i VarInfo) visible(pos parser.Pos) bool {
+	if i.Start == parser.NoPos && i.End == parser.NoPos {
+		return true
+	}
+	return pos >= i.Start && (i.End == parser.NoPos || pos < i.End)
+}
+
+type DebugAction int
+
+const (
+	DebugContinue DebugAction = iota
+	DebugStepIn
+	DebugStepOver
+	DebugStepOut
+	DebugStop
+)
+
+type StopReason int
+
+const (
+	StopEntry StopReason = iota
+	StopBreakpoint
+	StopStep
+	StopPause
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case StopEntry:
+		return "giriş"
+	case StopBreakpoint:
+		return "kesme noktası"
+	case StopStep:
+		return "adım"
+	case StopPause:
+		return "duraklatma"
+	}
+	return "bilinmeyen"
+}
+
+type Breakpoint struct {
+	ID        int
+	File      string
+	Line      int
+	Requested int
+	Verified  bool
+}
+
+type DebugHandler func(s *DebugState) DebugAction
+
+type Debugger struct {
+	bytecode    *Bytecode
+	handler     DebugHandler
+	lock        sync.Mutex
+	breakpoints []*Breakpoint
+	hits        map[location]*Breakpoint
+	lines       map[string][]int
+	nextID      int
+	action      DebugAction
+	depth       int
+	started     bool
+	pause       int32
+	vms         []debugVM
+	last        []location
+}
+
+type debugVM struct {
+	vm   *VM
+	base int
+	skip int
+}
+
+type location struct {
+	file string
+	line int
+}
+
+func NewDebugger(bytecode *Bytecode, handler DebugHandler) *Debugger {
+	return &Debugger{
+		bytecode: bytecode,
+		handler:  handler,
+		hits:     make(map[location]*Breakpoint),
+		action:   DebugStepIn,
+	}
+}
+
+func (d *Debugger) SetStopOnEntry(stop bool) {
+	if stop {
+		d.action = DebugStepIn
+	} else {
+		d.action = DebugContinue
+	}
+}
+
+func (d *Debugger) Pause() {
+	atomic.StoreInt32(&d.pause, 1)
+}
+
+func (d *Debugger) AddBreakpoint(file string, line int) *Breakpoint {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.addBreakpoint(file, line)
+}
+
+func (d *Debugger) SetBreakpoints(file string, lines []int) []*Breakpoint {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	name := d.resolveFile(file)
+	kept := d.breakpoints[:0]
+	for _, bp := range d.breakpoints {
+		if bp.File != name {
+			kept = append(kept, bp)
+		}
+	}
+	d.breakpoints = kept
+	bps := make([]*Breakpoint, 0, len(lines))
+	for _, line := range lines {
+		bps = append(bps, d.addBreakpoint(file, line))
+	}
+	d.updateHits()
+	return bps
+}
+
+func (d *Debugger) RemoveBreakpoint(id int) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for i, bp := range d.breakpoints {
+		if bp.ID == id {
+			d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+			d.updateHits()
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Debugger) Breakpoints() []*Breakpoint {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return append([]*Breakpoint{}, d.breakpoints...)
+}
+
+func (d *Debugger) addBreakpoint(file string, line int) *Breakpoint {
+	d.nextID++
+	bp := &Breakpoint{
+		ID:        d.nextID,
+		File:      d.resolveFile(file),
+		Line:      line,
+		Requested: line,
+	}
+	lines := d.sourceLines()[bp.File]
+	i := sort.SearchInts(lines, line)
+	if i < len(lines) {
+		bp.Line = lines[i]
+		bp.Verified = true
+	}
+	d.breakpoints = append(d.breakpoints, bp)
+	d.updateHits()
+	return bp
+}
+
+func (d *Debugger) updateHits() {
+	d.hits = make(map[location]*Breakpoint, len(d.breakpoints))
+	for _, bp := range d.breakpoints {
+		if bp.Verified {
+			d.hits[location{bp.File, bp.Line}] = bp
+		}
+	}
+}
+
+func (d *Debugger) resolveFile(file string) string {
+	lines := d.sourceLines()
+	if _, ok := lines[file]; ok {
+		return file
+	}
+	for name := range lines {
+		if filepath.Base(name) == filepath.Base(file) {
+			return name
+		}
+	}
+	return file
+}
+
+func (d *Debugger) sourceLines() map[string][]int {
+	if d.lines != nil {
+		return d.lines
+	}
+	seen := make(map[location]bool)
+	d.lines = make(map[string][]int)
+	add := func(fn *CompiledFunction) {
+		for _, p := range fn.SourceMap {
+			pos := d.bytecode.FileSet.Position(p)
+			loc := location{pos.Filename, pos.Line}
+			if pos.IsValid() && !seen[loc] {
+				seen[loc] = true
+				d.lines[loc.file] = append(d.lines[loc.file], loc.line)
+			}
+		}
+	}
+	add(d.bytecode.MainFunction)
+	for _, c := range d.bytecode.Constants {
+		if fn, ok := c.(*CompiledFunction); ok {
+			add(fn)
+		}
+	}
+	for _, lines := range d.lines {
+		sort.Ints(lines)
+	}
+	return d.lines
+}
+
+func (d *Debugger) enter(v *VM, nested bool) {
+	e := debugVM{vm: v}
+	if n := len(d.vms); n > 0 {
+		top := d.vms[n-1]
+		e.base = top.base + top.vm.framesIndex
+	}
+	if nested {
+		e.skip = 1
+	}
+	d.vms = append(d.vms, e)
+	v.ticks, v.tickBase = 0, 0
+}
+
+func (d *Debugger) leave() {
+	d.vms = d.vms[:len(d.vms)-1]
+}
+
+func (d *Debugger) step(v *VM) {
+	top := d.vms[len(d.vms)-1]
+	if top.vm != v || v.framesIndex-1 < top.skip {
+		return
+	}
+	ip := v.ip + 1
+	if ip >= len(v.curInsts) || v.curInsts[ip] == parser.OpSuspend {
+		return
+	}
+	pos := v.fileSet.Position(v.curFrame.fn.SourcePos(ip))
+	if !pos.IsValid() {
+		return
+	}
+
+	depth := top.base + v.framesIndex
+	for len(d.last) <= depth {
+		d.last = append(d.last, location{})
+	}
+	// çağıranın satırına dönülürken o satırın kalanında durulur
+	stepOut := len(d.last) > depth+1 && d.action == DebugStepOut && depth < d.depth
+	d.last = d.last[:depth+1]
+	if stepOut {
+		d.last[depth] = location{}
+	}
+	loc := location{pos.Filename, pos.Line}
+	if d.last[depth] == loc {
+		return
+	}
+	d.last[depth] = loc
+
+	state := &DebugState{Pos: pos, d: d}
+	d.lock.Lock()
+	bp := d.hits[loc]
+	d.lock.Unlock()
+	switch {
+	case atomic.CompareAndSwapInt32(&d.pause, 1, 0):
+		state.Reason = StopPause
+	case bp != nil && !stepOut:
+		state.Reason = StopBreakpoint
+		state.Breakpoint = bp
+	case d.action == DebugStepIn,
+		d.action == DebugStepOver && depth <= d.depth,
+		d.action == DebugStepOut && depth < d.depth:
+		state.Reason = StopStep
+		if !d.started {
+			state.Reason = StopEntry
+		}
+	default:
+		return
+	}
+	d.started = true
+	d.depth = depth
+	d.action = d.handler(state)
+	if d.action == DebugStop {
+		d.vms[0].vm.Abort()
+	}
+}
+
+type DebugState struct {
+	Reason     StopReason
+	Pos        parser.SourceFilePos
+	Breakpoint *Breakpoint
+	d          *Debugger
+	frames     []*DebugFrame
+}
+
+type DebugFrame struct {
+	Name   string
+	Pos    parser.SourceFilePos
+	vm     *VM
+	frame  *frame
+	srcPos parser.Pos
+	main   bool
+}
+
+func (s *DebugState) Frames() []*DebugFrame {
+	if s.frames != nil {
+		return s.frames
+	}
+	for i := len(s.d.vms) - 1; i >= 0; i-- {
+		e := s.d.vms[i]
+		v := e.vm
+		for j := v.framesIndex - 1; j >= e.skip; j-- {
+			f := &v.frames[j]
+			ip := f.ip
+			if j == v.framesIndex-1 {
+				ip = v.ip
+				if i == len(s.d.vms)-1 {
+					ip++
+				}
+			}
+			srcPos := f.fn.SourcePos(ip)
+			frame := &DebugFrame{
+				Name:   "fn",
+				Pos:    v.fileSet.Position(srcPos),
+				vm:     v,
+				frame:  f,
+				srcPos: srcPos,
+				main:   i == 0 && j == 0,
+			}
+			if f.fn.Debug != nil && f.fn.Debug.Name != "" {
+				frame.Name = f.fn.Debug.Name
+			}
+			if frame.main && f.fn == s.d.bytecode.MainFunction {
+				frame.Name = "(ana)"
+			}
+			s.frames = append(s.frames, frame)
+		}
+	}
+	return s.frames
+}
+
+func (s *DebugState) Globals() []*Variable {
+	frames := s.Frames()
+	if len(frames) == 0 {
+		return s.globals(nil)
+	}
+	return s.globals(frames[len(frames)-1])
+}
+
+func (s *DebugState) globals(f *DebugFrame) []*Variable {
+	main := s.d.bytecode.MainFunction
+	if main.Debug == nil || len(s.d.vms) == 0 {
+		return nil
+	}
+	v := s.d.vms[0].vm
+	var vars []*Variable
+	for _, info := range main.Debug.Vars {
+		if !info.Global || info.Index >= len(v.globals) {
+			continue
+		}
+		if info.End != parser.NoPos &&
+			(f == nil || !f.main || !info.visible(f.srcPos)) {
+			continue
+		}
+		if value := v.global(info.Index); value != nil {
+			vars = append(vars, &Variable{name: info.Name, value: value})
+		}
+	}
+	return dedupe(vars)
+}
+
+func (f *DebugFrame) Locals() []*Variable {
+	debug := f.frame.fn.Debug
+	if debug == nil {
+		return nil
+	}
+	var vars []*Variable
+	for _, info := range debug.Vars {
+		if info.Global || !info.visible(f.srcPos) ||
+			info.Index >= f.frame.fn.NumLocals {
+			continue
+		}
+		value := f.vm.stack[f.frame.basePointer+info.Index]
+		if ptr, ok := value.(*ObjectPtr); ok {
+			value = *ptr.Value
+		}
+		if value != nil {
+			vars = append(vars, &Variable{name: info.Name, value: value})
+		}
+	}
+	return dedupe(vars)
+}
+
+func (f *DebugFrame) Free() []*Variable {
+	debug := f.frame.fn.Debug
+	if debug == nil {
+		return nil
+	}
+	var vars []*Variable
+	for i, name := range debug.Free {
+		if i >= len(f.frame.freeVars) {
+			break
+		}
+		if p := f.frame.freeVars[i]; p != nil && p.Value != nil && *p.Value != nil {
+			vars = append(vars, &Variable{name: name, value: *p.Value})
+		}
+	}
+	return vars
+}
+
+func (s *DebugState) Eval(f *DebugFrame, expr string) (Object, error) {
+	scope := make(map[string]Object)
+	for _, vars := range [][]*Variable{s.globals(f), f.Free(), f.Locals()} {
+		for _, v := range vars {
+			scope[v.name] = v.value
+		}
+	}
+	names := make([]string, 0, len(scope))
+	for name := range scope {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiled, err := exprCache.Get(expr, names...)
+	if err != nil {
+		return nil, evalError(expr, err)
+	}
+	v := NewVM(compiled.bytecode, make([]Object, len(compiled.globals)), -1)
+	copy(v.globals, compiled.globals)
+	for name, value := range scope {
+		v.globals[compiled.params[name]] = value
+	}
+	v.SetStdout(f.vm.Stdout())
+	v.SetStderr(f.vm.Stderr())
+	if err := runRecover(v.Run); err != nil {
+		return nil, evalError(expr, err)
+	}
+	res := v.globals[compiled.result]
+	if res == nil {
+		res = UndefinedValue
+	}
+	return res, nil
+}
+
+// evalError, CompileExpr'in sardığı betiğe göre verilen hatayı kullanıcının
+// yazdığı ifadeye göre yeniden yazar.
+func evalError(expr string, err error) error {
+	var list parser.ErrorList
+	var cerr *CompilerError
+	switch {
+	case errors.As(err, &list) && len(list) > 0:
+		return exprError(expr, list[0].Pos.Column, list[0].Msg)
+	case errors.As(err, &cerr):
+		pos := cerr.FileSet.Position(cerr.Node.Pos())
+		return exprError(expr, pos.Column, cerr.Err.Error())
+	}
+	lines := strings.Split(err.Error(), "\n\tat ")
+	msg := strings.TrimPrefix(lines[0], "Çalışma Hatası: ")
+	if len(lines) > 1 && strings.HasPrefix(lines[1], "(main):") {
+		col, _ := strconv.Atoi(lines[1][strings.LastIndexByte(lines[1], ':')+1:])
+		return exprError(expr, col, msg)
+	}
+	return errors.New(msg)
+}
+
+func exprError(expr string, col int, msg string) error {
+	col -= len(resultName + " := (")
+	col += len(expr) - len(strings.TrimLeft(expr, " \t"))
+	if col < 1 {
+		col = 1
+	}
+	return fmt.Errorf("sütun %d: %s", col, msg)
+}
+
+func dedupe(vars []*Variable) []*Variable {
+	index := make(map[string]int, len(vars))
+	var out []*Variable
+	for _, v := range vars {
+		if i, ok := index[v.name]; ok {
+			out[i] = v
+			continue
+		}
+		index[v.name] = len(out)
+		out = append(out, v)
+	}
+	return out
+}
+
+func (v *VM) SetDebugger(d *Debugger) {
+	v.debugger = d
+}
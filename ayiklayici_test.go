@@ -0,0 +1,105 @@
+package lokum
+
+import (
+	"testing"
+)
+
+const debugSrc = `f3 := fn(a) {
+	x := a + 1
+	dön x
+}
+f2 := fn() {
+	y := f3(1)
+	dön y
+}
+z := f2()
+w := z
+`
+
+type debugStop struct {
+	reason StopReason
+	line   int
+	frames int
+	action DebugAction
+}
+
+func TestDebuggerSteps(t *testing.T) {
+	c, err := NewScript([]byte(debugSrc)).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stops := []debugStop{
+		{StopBreakpoint, 6, 2, DebugStepIn},
+		{StopStep, 2, 3, DebugStepOver},
+		{StopStep, 3, 3, DebugStepOut},
+		{StopStep, 6, 2, DebugStepOver},
+		{StopStep, 7, 2, DebugStepOut},
+		{StopStep, 9, 1, DebugStepOver},
+		{StopStep, 10, 1, DebugContinue},
+	}
+	i := 0
+	d := c.Debug(func(s *DebugState) DebugAction {
+		if i >= len(stops) {
+			t.Fatalf("beklenmeyen durma: %s satır %d", s.Reason, s.Pos.Line)
+		}
+		want := stops[i]
+		i++
+		if s.Reason != want.reason || s.Pos.Line != want.line ||
+			len(s.Frames()) != want.frames {
+			t.Fatalf("durma %d: %s satır %d (%d çerçeve), beklenen %s satır %d (%d çerçeve)",
+				i, s.Reason, s.Pos.Line, len(s.Frames()),
+				want.reason, want.line, want.frames)
+		}
+		if s.Pos.Line == 3 {
+			checkDebugLocals(t, s)
+		}
+		return want.action
+	})
+	d.SetStopOnEntry(false)
+	if bp := d.AddBreakpoint("(main)", 6); !bp.Verified || bp.Line != 6 {
+		t.Fatalf("kesme noktası %+v", bp)
+	}
+	if bp := d.AddBreakpoint("(main)", 20); bp.Verified {
+		t.Fatalf("kodsuz satırda kesme noktası doğrulandı: %+v", bp)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(stops) {
+		t.Fatalf("%d kez duruldu, beklenen %d", i, len(stops))
+	}
+}
+
+func checkDebugLocals(t *testing.T, s *DebugState) {
+	t.Helper()
+	f := s.Frames()[0]
+	if f.Name != "f3" {
+		t.Fatalf("çerçeve %s, beklenen f3", f.Name)
+	}
+	locals := make(map[string]Object)
+	for _, v := range f.Locals() {
+		locals[v.Name()] = v.Object()
+	}
+	if len(locals) != 2 || !locals["a"].Equals(&Int{Value: 1}) ||
+		!locals["x"].Equals(&Int{Value: 2}) {
+		t.Fatalf("yerel değişkenler %v", locals)
+	}
+	res, err := s.Eval(f, "x * 10 + a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Equals(&Int{Value: 21}) {
+		t.Fatalf("%s, beklenen 21", res)
+	}
+
+	for expr, want := range map[string]string{
+		"x +":       "sütun 4: operand beklenildi')' bulundu",
+		"  yok * 2": "sütun 3: geçersiz referanslar 'yok'",
+		"a + x()":   "sütun 5: çağrılamaz: int",
+		"x / 0":     "runtime error: integer divide by zero",
+	} {
+		if _, err := s.Eval(f, expr); err == nil || err.Error() != want {
+			t.Fatalf("%q: hata %v, beklenen %q", expr, err, want)
+		}
+	}
+}
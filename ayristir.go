@@ -0,0 +1,217 @@
+package lokum
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/token"
+)
+
+const maxConstDisplay = 40
+
+type disassembler struct {
+	w       io.Writer
+	b       *Bytecode
+	fnIDs   map[*CompiledFunction]string
+	source  func(filename string) []byte
+	lines   map[string][][]byte
+	lastPos parser.SourceFilePos
+}
+
+func (b *Bytecode) Disassemble(
+	w io.Writer,
+	source func(filename string) []byte,
+) error {
+	d := &disassembler{
+		w:      w,
+		b:      b,
+		fnIDs:  map[*CompiledFunction]string{b.MainFunction: "main"},
+		source: source,
+		lines:  make(map[string][][]byte),
+	}
+	for idx, c := range b.Constants {
+		if fn, ok := c.(*CompiledFunction); ok {
+			if _, exists := d.fnIDs[fn]; !exists {
+				d.fnIDs[fn] = fmt.Sprintf("fn%d", idx)
+			}
+		}
+	}
+
+	if err := d.function("main", b.MainFunction); err != nil {
+		return err
+	}
+	for idx, c := range b.Constants {
+		fn, ok := c.(*CompiledFunction)
+		if !ok || d.fnIDs[fn] != fmt.Sprintf("fn%d", idx) {
+			continue
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+		if err := d.function(d.fnIDs[fn], fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *disassembler) function(id string, fn *CompiledFunction) error {
+	labels := jumpLabels(fn.Instructions)
+	_, err := fmt.Fprintf(d.w,
+		"== %s: parametre=%d yerel=%d değişken_arg=%t serbest=%d ==\n",
+		id, fn.NumParameters, fn.NumLocals, fn.VarArgs, numFree(fn))
+	if err != nil {
+		return err
+	}
+
+	d.lastPos = parser.SourceFilePos{}
+	iterateInstructions(fn.Instructions,
+		func(pos int, op parser.Opcode, operands []int) bool {
+			if err = d.sourceLine(fn, pos); err != nil {
+				return false
+			}
+			if label, ok := labels[pos]; ok {
+				if _, err = fmt.Fprintf(d.w, "%s:\n", label); err != nil {
+					return false
+				}
+			}
+			_, err = fmt.Fprintf(d.w, "  %04d %-8s %s\n", pos,
+				parser.OpcodeNames[op], d.operands(op, operands, labels))
+			return err == nil
+		})
+	return err
+}
+
+func (d *disassembler) operands(
+	op parser.Opcode,
+	operands []int,
+	labels map[int]string,
+) string {
+	switch op {
+	case parser.OpJump, parser.OpJumpFalsy, parser.OpAndJump,
+		parser.OpOrJump:
+		return labels[operands[0]]
+	case parser.OpConstant, parser.OpImport:
+		return fmt.Sprintf("%-5d ; %s", operands[0], d.constant(operands[0]))
+	case parser.OpClosure:
+		return fmt.Sprintf("%-5d ; %s serbest=%d", operands[0],
+			d.constant(operands[0]), operands[1])
+	case parser.OpGetBuiltin:
+		if operands[0] < len(builtinFuncs) {
+			return fmt.Sprintf("%-5d ; %s", operands[0],
+				builtinFuncs[operands[0]].Name)
+		}
+	case parser.OpGetFree, parser.OpGetFreePtr, parser.OpSetFree:
+		return fmt.Sprintf("%-5d ; serbest[%d]", operands[0], operands[0])
+	case parser.OpSetSelFree:
+		return fmt.Sprintf("%-5d %-5d ; serbest[%d]",
+			operands[0], operands[1], operands[0])
+	case parser.OpBinaryOp:
+		return fmt.Sprintf("%-5d ; %s", operands[0],
+			token.Token(operands[0]).String())
+	}
+
+	var s []string
+	for _, o := range operands {
+		s = append(s, fmt.Sprintf("%-5d", o))
+	}
+	return strings.TrimSpace(strings.Join(s, " "))
+}
+
+func (d *disassembler) constant(idx int) string {
+	if idx >= len(d.b.Constants) {
+		return "?"
+	}
+	switch c := d.b.Constants[idx].(type) {
+	case *CompiledFunction:
+		return d.fnIDs[c]
+	case *ImmutableMap:
+		if name := inferModuleName(c); name != "" {
+			return fmt.Sprintf("modül %q", name)
+		}
+	}
+	s := d.b.Constants[idx].String()
+	if len([]rune(s)) > maxConstDisplay {
+		s = string([]rune(s)[:maxConstDisplay]) + "..."
+	}
+	return s
+}
+
+func (d *disassembler) sourceLine(fn *CompiledFunction, pos int) error {
+	p, ok := fn.SourceMap[pos]
+	if !ok || d.b.FileSet == nil {
+		return nil
+	}
+	filePos := d.b.FileSet.Position(p)
+	if !filePos.IsValid() || (filePos.Filename == d.lastPos.Filename &&
+		filePos.Line == d.lastPos.Line) {
+		return nil
+	}
+	d.lastPos = filePos
+
+	var text []byte
+	if lines := d.sourceLines(filePos.Filename); filePos.Line <= len(lines) {
+		text = bytes.TrimSpace(lines[filePos.Line-1])
+	}
+	_, err := fmt.Fprintf(d.w, "  ; %s:%d | %s\n",
+		filePos.Filename, filePos.Line, text)
+	return err
+}
+
+func (d *disassembler) sourceLines(filename string) [][]byte {
+	if lines, ok := d.lines[filename]; ok {
+		return lines
+	}
+	var lines [][]byte
+	if d.source != nil {
+		if src := d.source(filename); src != nil {
+			lines = bytes.Split(src, []byte("\n"))
+		}
+	}
+	d.lines[filename] = lines
+	return lines
+}
+
+func jumpLabels(insts []byte) map[int]string {
+	var targets []int
+	seen := make(map[int]bool)
+	iterateInstructions(insts,
+		func(pos int, op parser.Opcode, operands []int) bool {
+			switch op {
+			case parser.OpJump, parser.OpJumpFalsy, parser.OpAndJump,
+				parser.OpOrJump:
+				if !seen[operands[0]] {
+					seen[operands[0]] = true
+					targets = append(targets, operands[0])
+				}
+			}
+			return true
+		})
+	sort.Ints(targets)
+
+	labels := make(map[int]string, len(targets))
+	for i, t := range targets {
+		labels[t] = fmt.Sprintf("L%d", i+1)
+	}
+	return labels
+}
+
+func numFree(fn *CompiledFunction) int {
+	n := len(fn.Free)
+	iterateInstructions(fn.Instructions,
+		func(pos int, op parser.Opcode, operands []int) bool {
+			switch op {
+			case parser.OpGetFree, parser.OpGetFreePtr, parser.OpSetFree,
+				parser.OpSetSelFree:
+				if operands[0] >= n {
+					n = operands[0] + 1
+				}
+			}
+			return true
+		})
+	return n
+}
@@ -1,283 +1,460 @@
-package lokum
-
-import (
-	"encoding/gob"
-	"fmt"
-	"io"
-	"reflect"
-
-	"github.com/onrirr/lokum/parser"
-)
-
-type Bytecode struct {
-	FileSet      *parser.SourceFileSet
-	MainFunction *CompiledFunction
-	Constants    []Object
-}
-
-func (b *Bytecode) Encode(w io.Writer) error {
-	enc := gob.NewEncoder(w)
-	if err := enc.Encode(b.FileSet); err != nil {
-		return err
-	}
-	if err := enc.Encode(b.MainFunction); err != nil {
-		return err
-	}
-	return enc.Encode(b.Constants)
-}
-
-func (b *Bytecode) CountObjects() int {
-	n := 0
-	for _, c := range b.Constants {
-		n += CountObjects(c)
-	}
-	return n
-}
-
-func (b *Bytecode) FormatInstructions() []string {
-	return FormatInstructions(b.MainFunction.Instructions, 0)
-}
-
-func (b *Bytecode) FormatConstants() (output []string) {
-	for cidx, cn := range b.Constants {
-		switch cn := cn.(type) {
-		case *CompiledFunction:
-			output = append(output, fmt.Sprintf(
-				"[% 3d] (Compiled Function|%p)", cidx, &cn))
-			for _, l := range FormatInstructions(cn.Instructions, 0) {
-				output = append(output, fmt.Sprintf("     %s", l))
-			}
-		default:
-			output = append(output, fmt.Sprintf("[% 3d] %s (%s|%p)",
-				cidx, cn, reflect.TypeOf(cn).Elem().Name(), &cn))
-		}
-	}
-	return
-}
-
-func (b *Bytecode) Decode(r io.Reader, modules *ModuleMap) error {
-	if modules == nil {
-		modules = NewModuleMap()
-	}
-
-	dec := gob.NewDecoder(r)
-	if err := dec.Decode(&b.FileSet); err != nil {
-		return err
-	}
-
-	if err := dec.Decode(&b.MainFunction); err != nil {
-		return err
-	}
-	if err := dec.Decode(&b.Constants); err != nil {
-		return err
-	}
-	for i, v := range b.Constants {
-		fv, err := fixDecodedObject(v, modules)
-		if err != nil {
-			return err
-		}
-		b.Constants[i] = fv
-	}
-	return nil
-}
-
-func (b *Bytecode) RemoveDuplicates() {
-	var deduped []Object
-
-	indexMap := make(map[int]int)
-	fns := make(map[*CompiledFunction]int)
-	ints := make(map[int64]int)
-	strings := make(map[string]int)
-	floats := make(map[float64]int)
-	chars := make(map[rune]int)
-	immutableMaps := make(map[string]int)
-
-	for curIdx, c := range b.Constants {
-		switch c := c.(type) {
-		case *CompiledFunction:
-			if newIdx, ok := fns[c]; ok {
-				indexMap[curIdx] = newIdx
-			} else {
-				newIdx = len(deduped)
-				fns[c] = newIdx
-				indexMap[curIdx] = newIdx
-				deduped = append(deduped, c)
-			}
-		case *ImmutableMap:
-			modName := inferModuleName(c)
-			newIdx, ok := immutableMaps[modName]
-			if modName != "" && ok {
-				indexMap[curIdx] = newIdx
-			} else {
-				newIdx = len(deduped)
-				immutableMaps[modName] = newIdx
-				indexMap[curIdx] = newIdx
-				deduped = append(deduped, c)
-			}
-		case *Int:
-			if newIdx, ok := ints[c.Value]; ok {
-				indexMap[curIdx] = newIdx
-			} else {
-				newIdx = len(deduped)
-				ints[c.Value] = newIdx
-				indexMap[curIdx] = newIdx
-				deduped = append(deduped, c)
-			}
-		case *String:
-			if newIdx, ok := strings[c.Value]; ok {
-				indexMap[curIdx] = newIdx
-			} else {
-				newIdx = len(deduped)
-				strings[c.Value] = newIdx
-				indexMap[curIdx] = newIdx
-				deduped = append(deduped, c)
-			}
-		case *Float:
-			if newIdx, ok := floats[c.Value]; ok {
-				indexMap[curIdx] = newIdx
-			} else {
-				newIdx = len(deduped)
-				floats[c.Value] = newIdx
-				indexMap[curIdx] = newIdx
-				deduped = append(deduped, c)
-			}
-		case *Char:
-			if newIdx, ok := chars[c.Value]; ok {
-				indexMap[curIdx] = newIdx
-			} else {
-				newIdx = len(deduped)
-				chars[c.Value] = newIdx
-				indexMap[curIdx] = newIdx
-				deduped = append(deduped, c)
-			}
-		default:
-			panic(fmt.Errorf("geçersiz üst seviye tanımlaması: %s",
-				c.TypeName()))
-		}
-	}
-
-	b.Constants = deduped
-
-	updateConstIndexes(b.MainFunction.Instructions, indexMap)
-
-	for _, c := range b.Constants {
-		switch c := c.(type) {
-		case *CompiledFunction:
-			updateConstIndexes(c.Instructions, indexMap)
-		}
-	}
-}
-
-func fixDecodedObject(
-	o Object,
-	modules *ModuleMap,
-) (Object, error) {
-	switch o := o.(type) {
-	case *Bool:
-		if o.IsFalsy() {
-			return FalseValue, nil
-		}
-		return TrueValue, nil
-	case *Undefined:
-		return UndefinedValue, nil
-	case *Array:
-		for i, v := range o.Value {
-			fv, err := fixDecodedObject(v, modules)
-			if err != nil {
-				return nil, err
-			}
-			o.Value[i] = fv
-		}
-	case *ImmutableArray:
-		for i, v := range o.Value {
-			fv, err := fixDecodedObject(v, modules)
-			if err != nil {
-				return nil, err
-			}
-			o.Value[i] = fv
-		}
-	case *Map:
-		for k, v := range o.Value {
-			fv, err := fixDecodedObject(v, modules)
-			if err != nil {
-				return nil, err
-			}
-			o.Value[k] = fv
-		}
-	case *ImmutableMap:
-		modName := inferModuleName(o)
-		if mod := modules.GetBuiltinModule(modName); mod != nil {
-			return mod.AsImmutableMap(modName), nil
-		}
-
-		for k, v := range o.Value {
-
-			if _, isUserFunction := v.(*UserFunction); isUserFunction {
-				return nil, fmt.Errorf("...? bu hatayı alıyorsan ilginç bir şeyler oluyor demektir.")
-			}
-
-			fv, err := fixDecodedObject(v, modules)
-			if err != nil {
-				return nil, err
-			}
-			o.Value[k] = fv
-		}
-	}
-	return o, nil
-}
-
-func updateConstIndexes(insts []byte, indexMap map[int]int) {
-	i := 0
-	for i < len(insts) {
-		op := insts[i]
-		numOperands := parser.OpcodeOperands[op]
-		_, read := parser.ReadOperands(numOperands, insts[i+1:])
-
-		switch op {
-		case parser.OpConstant:
-			curIdx := int(insts[i+2]) | int(insts[i+1])<<8
-			newIdx, ok := indexMap[curIdx]
-			if !ok {
-				panic(fmt.Errorf("sabit index bulunamadı: %d", curIdx))
-			}
-			copy(insts[i:], MakeInstruction(op, newIdx))
-		case parser.OpClosure:
-			curIdx := int(insts[i+2]) | int(insts[i+1])<<8
-			numFree := int(insts[i+3])
-			newIdx, ok := indexMap[curIdx]
-			if !ok {
-				panic(fmt.Errorf("index bulunamadı: %d", curIdx))
-			}
-			copy(insts[i:], MakeInstruction(op, newIdx, numFree))
-		}
-
-		i += 1 + read
-	}
-}
-
-func inferModuleName(mod *ImmutableMap) string {
-	if modName, ok := mod.Value["__module_name__"].(*String); ok {
-		return modName.Value
-	}
-	return ""
-}
-
-func init() {
-	gob.Register(&parser.SourceFileSet{})
-	gob.Register(&parser.SourceFile{})
-	gob.Register(&Array{})
-	gob.Register(&Bool{})
-	gob.Register(&Bytes{})
-	gob.Register(&Char{})
-	gob.Register(&CompiledFunction{})
-	gob.Register(&Error{})
-	gob.Register(&Float{})
-	gob.Register(&ImmutableArray{})
-	gob.Register(&ImmutableMap{})
-	gob.Register(&Int{})
-	gob.Register(&Map{})
-	gob.Register(&String{})
-	gob.Register(&Time{})
-	gob.Register(&Undefined{})
-	gob.Register(&UserFunction{})
-}
+package lokum
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+const (
+	BytecodeMagic   = "\x00LOKUM"
+	BytecodeVersion = 1
+)
+
+type BytecodeEncoding byte
+
+const (
+	EncodingGob BytecodeEncoding = iota
+	EncodingCompact
+)
+
+type BytecodeHeader struct {
+	Version      int
+	Encoding     BytecodeEncoding
+	OpcodeHash   uint32
+	LokumVersion string
+	Checksum     uint32
+}
+
+var opcodeTableHash = opcodeHash()
+
+func IsBytecode(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(BytecodeMagic))
+}
+
+func opcodeHash() uint32 {
+	h := fnv.New32a()
+	for op, name := range parser.OpcodeNames {
+		_, _ = fmt.Fprintf(h, "%d:%s:%v;", op, name, parser.OpcodeOperands[op])
+	}
+	return h.Sum32()
+}
+
+func ReadBytecodeHeader(r io.Reader) (*BytecodeHeader, error) {
+	br, ok := r.(interface {
+		io.Reader
+		io.ByteReader
+	})
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	magic := make([]byte, len(BytecodeMagic))
+	if _, err := io.ReadFull(br, magic); err != nil ||
+		string(magic) != BytecodeMagic {
+		return nil, ErrInvalidBytecode
+	}
+
+	var fixed struct {
+		Version    uint16
+		Encoding   BytecodeEncoding
+		OpcodeHash uint32
+	}
+	if err := binary.Read(br, binary.BigEndian, &fixed); err != nil {
+		return nil, ErrInvalidBytecode
+	}
+	n, err := binary.ReadUvarint(br)
+	if err != nil || n > 256 {
+		return nil, ErrInvalidBytecode
+	}
+	version := make([]byte, n)
+	if _, err := io.ReadFull(br, version); err != nil {
+		return nil, ErrInvalidBytecode
+	}
+	h := &BytecodeHeader{
+		Version:      int(fixed.Version),
+		Encoding:     fixed.Encoding,
+		OpcodeHash:   fixed.OpcodeHash,
+		LokumVersion: string(version),
+	}
+	if err := binary.Read(br, binary.BigEndian, &h.Checksum); err != nil {
+		return nil, ErrInvalidBytecode
+	}
+	return h, nil
+}
+
+func (h *BytecodeHeader) write(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString(BytecodeMagic)
+	_ = binary.Write(&buf, binary.BigEndian, uint16(h.Version))
+	buf.WriteByte(byte(h.Encoding))
+	_ = binary.Write(&buf, binary.BigEndian, h.OpcodeHash)
+	var n [binary.MaxVarintLen64]byte
+	buf.Write(n[:binary.PutUvarint(n[:], uint64(len(h.LokumVersion)))])
+	buf.WriteString(h.LokumVersion)
+	_ = binary.Write(&buf, binary.BigEndian, h.Checksum)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (h *BytecodeHeader) check() error {
+	if h.Version != BytecodeVersion {
+		return fmt.Errorf("%w: %d, beklenen %d (lokum %s)",
+			ErrBytecodeVersion, h.Version, BytecodeVersion, h.LokumVersion)
+	}
+	if h.OpcodeHash != opcodeTableHash {
+		return fmt.Errorf("%w: lokum %s ile derlenmiş, yeniden derleyin",
+			ErrBytecodeOpcodes, h.LokumVersion)
+	}
+	switch h.Encoding {
+	case EncodingGob, EncodingCompact:
+	default:
+		return fmt.Errorf("%w: bilinmeyen kodlama %d",
+			ErrInvalidBytecode, h.Encoding)
+	}
+	return nil
+}
+
+type Bytecode struct {
+	FileSet      *parser.SourceFileSet
+	MainFunction *CompiledFunction
+	Constants    []Object
+}
+
+func (b *Bytecode) Encode(w io.Writer) error {
+	return b.EncodeWith(w, EncodingGob)
+}
+
+func (b *Bytecode) EncodeWith(w io.Writer, encoding BytecodeEncoding) error {
+	var payload bytes.Buffer
+	switch encoding {
+	case EncodingGob:
+		enc := gob.NewEncoder(&payload)
+		if err := enc.Encode(b.FileSet); err != nil {
+			return err
+		}
+		if err := enc.Encode(b.MainFunction); err != nil {
+			return err
+		}
+		if err := enc.Encode(b.Constants); err != nil {
+			return err
+		}
+	case EncodingCompact:
+		if err := b.encodeCompact(&payload); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("bilinmeyen bytecode kodlaması: %d", encoding)
+	}
+
+	h := &BytecodeHeader{
+		Version:      BytecodeVersion,
+		Encoding:     encoding,
+		OpcodeHash:   opcodeTableHash,
+		LokumVersion: Version,
+		Checksum:     crc32.ChecksumIEEE(payload.Bytes()),
+	}
+	if err := h.write(w); err != nil {
+		return err
+	}
+	_, err := w.Write(payload.Bytes())
+	return err
+}
+
+func (b *Bytecode) CountObjects() int {
+	n := 0
+	for _, c := range b.Constants {
+		n += CountObjects(c)
+	}
+	return n
+}
+
+func (b *Bytecode) FormatInstructions() []string {
+	return FormatInstructions(b.MainFunction.Instructions, 0)
+}
+
+func (b *Bytecode) FormatConstants() (output []string) {
+	for cidx, cn := range b.Constants {
+		switch cn := cn.(type) {
+		case *CompiledFunction:
+			output = append(output, fmt.Sprintf(
+				"[% 3d] (Compiled Function)", cidx))
+			for _, l := range FormatInstructions(cn.Instructions, 0) {
+				output = append(output, fmt.Sprintf("     %s", l))
+			}
+		default:
+			output = append(output, fmt.Sprintf("[% 3d] %s (%s)",
+				cidx, cn, reflect.TypeOf(cn).Elem().Name()))
+		}
+	}
+	return
+}
+
+func (b *Bytecode) Decode(r io.Reader, modules *ModuleMap) error {
+	if modules == nil {
+		modules = NewModuleMap()
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	br := bytes.NewReader(data)
+	h, err := ReadBytecodeHeader(br)
+	if err != nil {
+		return err
+	}
+	if err := h.check(); err != nil {
+		return err
+	}
+	payload := data[len(data)-br.Len():]
+	if crc32.ChecksumIEEE(payload) != h.Checksum {
+		return ErrBytecodeChecksum
+	}
+
+	if h.Encoding == EncodingCompact {
+		return b.decodeCompact(payload, modules)
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(payload))
+	if err := dec.Decode(&b.FileSet); err != nil {
+		return err
+	}
+	if err := dec.Decode(&b.MainFunction); err != nil {
+		return err
+	}
+	if err := dec.Decode(&b.Constants); err != nil {
+		return err
+	}
+	for i, v := range b.Constants {
+		fv, err := fixDecodedObject(v, modules)
+		if err != nil {
+			return err
+		}
+		b.Constants[i] = fv
+	}
+	return nil
+}
+
+func (b *Bytecode) RemoveDuplicates() {
+	var deduped []Object
+
+	indexMap := make(map[int]int)
+	fns := make(map[*CompiledFunction]int)
+	ints := make(map[int64]int)
+	strings := make(map[string]int)
+	floats := make(map[float64]int)
+	chars := make(map[rune]int)
+	immutableMaps := make(map[string]int)
+
+	for curIdx, c := range b.Constants {
+		switch c := c.(type) {
+		case *CompiledFunction:
+			if newIdx, ok := fns[c]; ok {
+				indexMap[curIdx] = newIdx
+			} else {
+				newIdx = len(deduped)
+				fns[c] = newIdx
+				indexMap[curIdx] = newIdx
+				deduped = append(deduped, c)
+			}
+		case *ImmutableMap:
+			modName := inferModuleName(c)
+			newIdx, ok := immutableMaps[modName]
+			if modName != "" && ok {
+				indexMap[curIdx] = newIdx
+			} else {
+				newIdx = len(deduped)
+				immutableMaps[modName] = newIdx
+				indexMap[curIdx] = newIdx
+				deduped = append(deduped, c)
+			}
+		case *Int:
+			if newIdx, ok := ints[c.Value]; ok {
+				indexMap[curIdx] = newIdx
+			} else {
+				newIdx = len(deduped)
+				ints[c.Value] = newIdx
+				indexMap[curIdx] = newIdx
+				deduped = append(deduped, c)
+			}
+		case *String:
+			if newIdx, ok := strings[c.Value]; ok {
+				indexMap[curIdx] = newIdx
+			} else {
+				newIdx = len(deduped)
+				strings[c.Value] = newIdx
+				indexMap[curIdx] = newIdx
+				deduped = append(deduped, c)
+			}
+		case *Float:
+			if newIdx, ok := floats[c.Value]; ok {
+				indexMap[curIdx] = newIdx
+			} else {
+				newIdx = len(deduped)
+				floats[c.Value] = newIdx
+				indexMap[curIdx] = newIdx
+				deduped = append(deduped, c)
+			}
+		case *Char:
+			if newIdx, ok := chars[c.Value]; ok {
+				indexMap[curIdx] = newIdx
+			} else {
+				newIdx = len(deduped)
+				chars[c.Value] = newIdx
+				indexMap[curIdx] = newIdx
+				deduped = append(deduped, c)
+			}
+		default:
+			panic(fmt.Errorf("geçersiz üst seviye tanımlaması: %s",
+				c.TypeName()))
+		}
+	}
+
+	b.Constants = deduped
+
+	updateConstIndexes(b.MainFunction.Instructions, indexMap)
+
+	for _, c := range b.Constants {
+		switch c := c.(type) {
+		case *CompiledFunction:
+			updateConstIndexes(c.Instructions, indexMap)
+		}
+	}
+}
+
+func fixDecodedObject(
+	o Object,
+	modules *ModuleMap,
+) (Object, error) {
+	switch o := o.(type) {
+	case *Bool:
+		if o.IsFalsy() {
+			return FalseValue, nil
+		}
+		return TrueValue, nil
+	case *Undefined:
+		return UndefinedValue, nil
+	case *Array:
+		for i, v := range o.Value {
+			fv, err := fixDecodedObject(v, modules)
+			if err != nil {
+				return nil, err
+			}
+			o.Value[i] = fv
+		}
+	case *ImmutableArray:
+		for i, v := range o.Value {
+			fv, err := fixDecodedObject(v, modules)
+			if err != nil {
+				return nil, err
+			}
+			o.Value[i] = fv
+		}
+	case *Map:
+		var err error
+		o.Value.Range(func(k, v Object) bool {
+			var fv Object
+			if fv, err = fixDecodedObject(v, modules); err != nil {
+				return false
+			}
+			err = o.Value.Set(k, fv)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	case *ImmutableMap:
+		modName := inferModuleName(o)
+		if mod := modules.GetBuiltinModule(modName); mod != nil {
+			return mod.AsImmutableMap(modName), nil
+		}
+
+		var err error
+		o.Value.Range(func(k, v Object) bool {
+
+			if _, isUserFunction := v.(*UserFunction); isUserFunction {
+				err = fmt.Errorf("...? bu hatayı alıyorsan ilginç bir şeyler oluyor demektir.")
+				return false
+			}
+
+			var fv Object
+			if fv, err = fixDecodedObject(v, modules); err != nil {
+				return false
+			}
+			err = o.Value.Set(k, fv)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func updateConstIndexes(insts []byte, indexMap map[int]int) {
+	i := 0
+	for i < len(insts) {
+		op := insts[i]
+		numOperands := parser.OpcodeOperands[op]
+		_, read := parser.ReadOperands(numOperands, insts[i+1:])
+
+		switch op {
+		case parser.OpConstant, parser.OpImport:
+			curIdx := int(insts[i+2]) | int(insts[i+1])<<8
+			newIdx, ok := indexMap[curIdx]
+			if !ok {
+				panic(fmt.Errorf("sabit index bulunamadı: %d", curIdx))
+			}
+			copy(insts[i:], MakeInstruction(op, newIdx))
+		case parser.OpClosure:
+			curIdx := int(insts[i+2]) | int(insts[i+1])<<8
+			numFree := int(insts[i+3])
+			newIdx, ok := indexMap[curIdx]
+			if !ok {
+				panic(fmt.Errorf("index bulunamadı: %d", curIdx))
+			}
+			copy(insts[i:], MakeInstruction(op, newIdx, numFree))
+		}
+
+		i += 1 + read
+	}
+}
+
+func inferModuleName(mod *ImmutableMap) string {
+	v, _ := mod.Value.GetString("__module_name__")
+	if modName, ok := v.(*String); ok {
+		return modName.Value
+	}
+	return ""
+}
+
+func init() {
+	gob.Register(&parser.SourceFileSet{})
+	gob.Register(&parser.SourceFile{})
+	gob.Register(&Array{})
+	gob.Register(&Bool{})
+	gob.Register(&Bytes{})
+	gob.Register(&Char{})
+	gob.Register(&CompiledFunction{})
+	gob.Register(&Error{})
+	gob.Register(&Float{})
+	gob.Register(&ImmutableArray{})
+	gob.Register(&ImmutableMap{})
+	gob.Register(&ImmutableSet{})
+	gob.Register(&Int{})
+	gob.Register(&Map{})
+	gob.Register(&Set{})
+	gob.Register(&String{})
+	gob.Register(&Time{})
+	gob.Register(&Undefined{})
+	gob.Register(&UserFunction{})
+}
@@ -0,0 +1,205 @@
+package lokum
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testBytecode(t *testing.T) (*Bytecode, *ModuleMap) {
+	modules := NewModuleMap()
+	modules.AddBuiltinModule("mod", map[string]Object{
+		"sayı": &Int{Value: 7},
+	})
+	s := NewScript([]byte(`
+mod := kullan("mod")
+topla := fn(a, b) { dön a + b }
+sayaç := fn() { x := 0; dön fn() { x++; dön x } }
+sonuç := topla(mod.sayı, 1)
+`))
+	s.SetImports(modules)
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := c.bytecode
+
+	m := NewOrderedMap(0)
+	_ = m.Set(&String{Value: "a"}, &Int{Value: 1})
+	_ = m.Set(&Int{Value: 2}, &Array{Value: []Object{TrueValue}})
+	set := NewOrderedMap(0)
+	_ = set.Set(&String{Value: "x"}, TrueValue)
+	_ = set.Set(&Int{Value: 3}, TrueValue)
+	b.Constants = append(b.Constants,
+		UndefinedValue,
+		TrueValue,
+		FalseValue,
+		&Int{Value: -42},
+		&Float{Value: 3.25},
+		&String{Value: "lokum ğüşıöç"},
+		&Char{Value: 'ş'},
+		&Bytes{Value: []byte{0, 1, 255}},
+		&Time{Value: time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)},
+		&Array{Value: []Object{&Int{Value: 1}, &String{Value: "b"}}},
+		&ImmutableArray{Value: []Object{&Float{Value: 1.5}}},
+		&Map{Value: m},
+		&ImmutableMap{Value: m.Copy()},
+		&Set{Value: set},
+		&ImmutableSet{Value: set.Copy()},
+		&Error{Value: &String{Value: "hata"}},
+	)
+	return b, modules
+}
+
+func TestBytecodeRoundTrip(t *testing.T) {
+	for _, enc := range []BytecodeEncoding{EncodingGob, EncodingCompact} {
+		b, modules := testBytecode(t)
+		var buf bytes.Buffer
+		if err := b.EncodeWith(&buf, enc); err != nil {
+			t.Fatalf("kodlama %d: %v", enc, err)
+		}
+		h, err := ReadBytecodeHeader(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Encoding != enc || h.Version != BytecodeVersion {
+			t.Fatalf("başlık %+v", h)
+		}
+
+		var d Bytecode
+		if err := d.Decode(bytes.NewReader(buf.Bytes()), modules); err != nil {
+			t.Fatalf("kodlama %d: %v", enc, err)
+		}
+		if len(d.Constants) != len(b.Constants) {
+			t.Fatalf("kodlama %d: %d sabit, beklenen %d",
+				enc, len(d.Constants), len(b.Constants))
+		}
+		checkFunction(t, d.MainFunction, b.MainFunction)
+		for i := range b.Constants {
+			checkObject(t, d.Constants[i], b.Constants[i])
+		}
+		if !reflect.DeepEqual(d.FileSet.Position(b.MainFunction.SourcePos(2)),
+			b.FileSet.Position(b.MainFunction.SourcePos(2))) {
+			t.Fatalf("kodlama %d: kaynak konumu farklı", enc)
+		}
+
+		v := NewVM(&d, nil, -1)
+		if err := v.Run(); err != nil {
+			t.Fatalf("kodlama %d: %v", enc, err)
+		}
+	}
+}
+
+func checkFunction(t *testing.T, got, want *CompiledFunction) {
+	t.Helper()
+	if !bytes.Equal(got.Instructions, want.Instructions) ||
+		got.NumLocals != want.NumLocals ||
+		got.NumParameters != want.NumParameters ||
+		got.VarArgs != want.VarArgs ||
+		!reflect.DeepEqual(got.SourceMap, want.SourceMap) {
+		t.Fatalf("fonksiyon farklı: %+v, beklenen %+v", got, want)
+	}
+}
+
+func checkObject(t *testing.T, got, want Object) {
+	t.Helper()
+	if reflect.TypeOf(got) != reflect.TypeOf(want) {
+		t.Fatalf("tip %T, beklenen %T", got, want)
+	}
+	switch want := want.(type) {
+	case *CompiledFunction:
+		checkFunction(t, got.(*CompiledFunction), want)
+	case *ImmutableMap:
+		if name := inferModuleName(want); name != "" {
+			if inferModuleName(got.(*ImmutableMap)) != name {
+				t.Fatalf("modül %s", got)
+			}
+			return
+		}
+		if !got.Equals(want) {
+			t.Fatalf("%s, beklenen %s", got, want)
+		}
+	case *Error:
+		checkObject(t, got.(*Error).Value, want.Value)
+	default:
+		if !got.Equals(want) {
+			t.Fatalf("%s, beklenen %s", got, want)
+		}
+	}
+}
+
+func TestBytecodeCorrupt(t *testing.T) {
+	for _, enc := range []BytecodeEncoding{EncodingGob, EncodingCompact} {
+		b, modules := testBytecode(t)
+		var buf bytes.Buffer
+		if err := b.EncodeWith(&buf, enc); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+
+		corrupt := append([]byte{}, data...)
+		corrupt[len(corrupt)-1] ^= 0xff
+		var d Bytecode
+		err := d.Decode(bytes.NewReader(corrupt), modules)
+		if !errors.Is(err, ErrBytecodeChecksum) {
+			t.Fatalf("kodlama %d: sağlama hatası yerine %v", enc, err)
+		}
+
+		tests := []struct {
+			name string
+			data []byte
+			err  error
+		}{
+			{"boş", nil, ErrInvalidBytecode},
+			{"sihirli", append([]byte("LOKUM\x00"), data[6:]...), ErrInvalidBytecode},
+			{"kısa", data[:len(BytecodeMagic)+3], ErrInvalidBytecode},
+			{"sürüm", patch(data, len(BytecodeMagic)+1, 99), ErrBytecodeVersion},
+			{"kodlama", patch(data, len(BytecodeMagic)+2, 9), ErrInvalidBytecode},
+			{"opcode", patch(data, len(BytecodeMagic)+3, data[len(BytecodeMagic)+3]^1),
+				ErrBytecodeOpcodes},
+		}
+		for _, tt := range tests {
+			var d Bytecode
+			err := d.Decode(bytes.NewReader(tt.data), modules)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("kodlama %d, %s: %v, beklenen %v", enc, tt.name, err, tt.err)
+			}
+		}
+	}
+}
+
+func TestBytecodeCompactTruncated(t *testing.T) {
+	b, modules := testBytecode(t)
+	var buf bytes.Buffer
+	if err := b.EncodeWith(&buf, EncodingCompact); err != nil {
+		t.Fatal(err)
+	}
+	r := bytes.NewReader(buf.Bytes())
+	h, err := ReadBytecodeHeader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := buf.Bytes()[buf.Len()-r.Len():]
+	for _, n := range []int{1, len(payload) / 2, len(payload) - 1} {
+		p := payload[:n]
+		h.Checksum = crc32.ChecksumIEEE(p)
+		var data bytes.Buffer
+		if err := h.write(&data); err != nil {
+			t.Fatal(err)
+		}
+		data.Write(p)
+		var d Bytecode
+		if err := d.Decode(&data, modules); err == nil {
+			t.Fatalf("%d bayt: hata bekleniyordu", n)
+		}
+	}
+}
+
+func patch(data []byte, i int, b byte) []byte {
+	c := append([]byte{}, data...)
+	c[i] = b
+	return c
+}
@@ -0,0 +1,138 @@
+package checker
+
+type signature struct {
+	names  []string
+	params []Type
+	min    int
+	rest   Type
+	result Type
+	infer  func(args []Type) Type
+}
+
+func (s *signature) param(i int) (string, Type) {
+	if i < len(s.params) {
+		name := ""
+		if i < len(s.names) {
+			name = s.names[i]
+		}
+		return name, s.params[i]
+	}
+	return "", s.rest
+}
+
+func (s *signature) resultOf(args []Type) Type {
+	if s.infer != nil {
+		return s.infer(args)
+	}
+	return s.result
+}
+
+func conversion(t Type) *signature {
+	return &signature{
+		names:  []string{"first", "second"},
+		params: []Type{Any, Any},
+		min:    1,
+		infer: func(args []Type) Type {
+			if len(args) > 1 {
+				return t | args[1]
+			}
+			if len(args) == 1 && !args[0].IsDynamic() && args[0]&^t == 0 {
+				return t
+			}
+			return t | Undefined
+		},
+	}
+}
+
+var builtins = map[string]*signature{
+	"yazdır": {rest: Any, result: Undefined},
+	"uzunluk": {
+		names: []string{"first"},
+		params: []Type{
+			Array | ImmutableArray | String | Bytes |
+				Map | ImmutableMap | Set | ImmutableSet,
+		},
+		min:    1,
+		result: Int,
+	},
+	"kopyala": {
+		params: []Type{Any},
+		min:    1,
+		infer: func(args []Type) Type {
+			if len(args) == 0 {
+				return Any
+			}
+			return args[0]
+		},
+	},
+	"ekle": {
+		names:  []string{"first"},
+		params: []Type{Array},
+		min:    1,
+		rest:   Any,
+		result: Array,
+	},
+	"sil": {
+		names:  []string{"first", "second"},
+		params: []Type{Map, Any},
+		min:    2,
+		result: Undefined,
+	},
+	"yazı":      conversion(String),
+	"küme":      {rest: Any, result: Set},
+	"sayı":      conversion(Int),
+	"mantıksal": conversion(Bool),
+	"float":     conversion(Float),
+	"karakter":  conversion(Char),
+	"bytes":     conversion(Bytes),
+	"sınıf":     {params: []Type{Any}, min: 1, result: String},
+	"f": {
+		names:  []string{"format"},
+		params: []Type{String},
+		min:    1,
+		rest:   Any,
+		result: String,
+	},
+	"aralık": {
+		names:  []string{"start", "stop", "step"},
+		params: []Type{Int, Int, Int},
+		min:    2,
+		result: Array,
+	},
+	"kanal": {
+		names:  []string{"first"},
+		params: []Type{Int},
+		result: Channel,
+	},
+	"bekle": {
+		names:  []string{"first"},
+		params: []Type{Task},
+		min:    1,
+		rest:   Task,
+		result: Any,
+	},
+	"seç": {
+		names:  []string{"first"},
+		params: []Type{Channel},
+		min:    1,
+		rest:   Channel,
+		result: Array,
+	},
+}
+
+var predicates = map[string]Type{
+	"sayı_mı":      Int,
+	"float_mı":     Float,
+	"yazı_mı":      String,
+	"mantıksal_mı": Bool,
+	"liste_mi":     Array,
+	"harita_mı":    Map,
+	"küme_mi":      Set,
+	"tanımsız_mı":  Undefined,
+}
+
+func init() {
+	for name := range predicates {
+		builtins[name] = &signature{params: []Type{Any}, min: 1, result: Bool}
+	}
+}
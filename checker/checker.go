@@ -0,0 +1,819 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/token"
+)
+
+type Error struct {
+	Pos parser.SourceFilePos
+	Msg string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("Tip hatası: %s\n\tat %s", e.Msg, e.Pos)
+}
+
+type ErrorList []*Error
+
+func (p ErrorList) Error() string {
+	switch len(p) {
+	case 0:
+		return "no errors"
+	case 1:
+		return p[0].Error()
+	}
+	return fmt.Sprintf("%s (ve +%d hatalar)", p[0], len(p)-1)
+}
+
+func (p ErrorList) Err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return p
+}
+
+type function struct {
+	result Type
+}
+
+type variable struct {
+	declared Type
+	sig      *signature
+	fn       *function
+	escaped  bool
+}
+
+type scope struct {
+	parent *scope
+	vars   map[string]*variable
+}
+
+type refinement map[*variable]Type
+
+type checker struct {
+	file   *parser.File
+	errors ErrorList
+	scope  *scope
+	fn     *function
+	state  map[*variable]Type
+	sigs   map[*parser.FuncType]*signature
+}
+
+func Check(file *parser.File) ErrorList {
+	c := &checker{
+		file:  file,
+		fn:    &function{},
+		state: make(map[*variable]Type),
+		sigs:  make(map[*parser.FuncType]*signature),
+	}
+	c.openScope()
+	c.stmts(file.Stmts)
+	c.closeScope()
+	return c.errors
+}
+
+func (c *checker) errorf(pos parser.Pos, format string, args ...interface{}) {
+	c.errors = append(c.errors, &Error{
+		Pos: c.file.InputFile.Set().Position(pos),
+		Msg: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) openScope() {
+	c.scope = &scope{parent: c.scope, vars: make(map[string]*variable)}
+}
+
+func (c *checker) closeScope() {
+	c.scope = c.scope.parent
+}
+
+func (c *checker) lookup(name string) *variable {
+	for s := c.scope; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *checker) typeOfVar(v *variable) Type {
+	def := v.declared
+	if def == 0 {
+		def = Any
+	}
+	if v.fn != c.fn || v.escaped {
+		return def
+	}
+	if t, ok := c.state[v]; ok {
+		return t
+	}
+	return def
+}
+
+func (c *checker) set(v *variable, t Type) {
+	if v.fn != c.fn {
+		v.escaped = true
+		return
+	}
+	if v.declared != 0 && (t.IsDynamic() || !assignable(t, v.declared)) {
+		t = v.declared
+	}
+	c.state[v] = t
+}
+
+func (c *checker) snapshot() map[*variable]Type {
+	state := make(map[*variable]Type, len(c.state))
+	for v, t := range c.state {
+		state[v] = t
+	}
+	return state
+}
+
+func (c *checker) join(a, b map[*variable]Type) map[*variable]Type {
+	state := make(map[*variable]Type, len(a))
+	get := func(m map[*variable]Type, v *variable) Type {
+		if t, ok := m[v]; ok {
+			return t
+		}
+		if v.declared != 0 {
+			return v.declared
+		}
+		return Any
+	}
+	for v := range a {
+		state[v] = get(a, v) | get(b, v)
+	}
+	for v := range b {
+		state[v] = get(a, v) | get(b, v)
+	}
+	return state
+}
+
+func (c *checker) apply(r refinement) {
+	for v, t := range r {
+		c.state[v] = t
+	}
+}
+
+func (c *checker) resolveType(te *parser.TypeExpr) Type {
+	if te == nil {
+		return 0
+	}
+	var t Type
+	for _, n := range te.Names {
+		nt, ok := typeNames[n.Name]
+		if !ok {
+			c.errorf(n.Pos(), "bilinmeyen tip: %s", n.Name)
+			return Any
+		}
+		t |= nt
+	}
+	return t
+}
+
+func (c *checker) signature(ft *parser.FuncType) *signature {
+	if sig, ok := c.sigs[ft]; ok {
+		return sig
+	}
+	sig := &signature{result: Any}
+	for i, p := range ft.Params.List {
+		t := c.resolveType(ft.Params.Type(i))
+		if ft.Params.VarArgs && i == len(ft.Params.List)-1 {
+			sig.rest = Any
+			continue
+		}
+		if t == 0 {
+			t = Any
+		}
+		sig.names = append(sig.names, p.Name)
+		sig.params = append(sig.params, t)
+	}
+	sig.min = len(sig.params)
+	if ft.Result != nil {
+		sig.result = c.resolveType(ft.Result)
+	}
+	c.sigs[ft] = sig
+	return sig
+}
+
+func (c *checker) stmts(list []parser.Stmt) bool {
+	terminated := false
+	for _, s := range list {
+		if c.stmt(s) {
+			terminated = true
+		}
+	}
+	return terminated
+}
+
+func (c *checker) stmt(s parser.Stmt) bool {
+	switch s := s.(type) {
+	case *parser.ExprStmt:
+		c.expr(s.Expr)
+	case *parser.AssignStmt:
+		c.assign(s)
+	case *parser.IncDecStmt:
+		t := c.expr(s.Expr)
+		res := c.binary(s.TokenPos, t, token.Add, Int)
+		if ident, ok := s.Expr.(*parser.Ident); ok {
+			if v := c.lookup(ident.Name); v != nil {
+				c.check(v, ident.Name, s.TokenPos, res)
+				c.set(v, res)
+			}
+		}
+	case *parser.BlockStmt:
+		c.openScope()
+		terminated := c.stmts(s.Stmts)
+		c.closeScope()
+		return terminated
+	case *parser.IfStmt:
+		return c.ifStmt(s)
+	case *parser.ForStmt:
+		c.openScope()
+		if s.Init != nil {
+			c.stmt(s.Init)
+		}
+		c.widen(s.Body, s.Post)
+		if s.Cond != nil {
+			c.expr(s.Cond)
+			then, _ := c.refine(s.Cond)
+			before := c.snapshot()
+			c.apply(then)
+			c.stmt(s.Body)
+			if s.Post != nil {
+				c.stmt(s.Post)
+			}
+			c.state = before
+		} else {
+			c.stmt(s.Body)
+			if s.Post != nil {
+				c.stmt(s.Post)
+			}
+		}
+		c.widen(s.Body, s.Post)
+		c.closeScope()
+	case *parser.ForInStmt:
+		c.forIn(s)
+	case *parser.ReturnStmt:
+		t := Undefined
+		if s.Result != nil {
+			t = c.expr(s.Result)
+		}
+		if c.fn.result != 0 && !assignable(t, c.fn.result) {
+			c.errorf(s.ReturnPos, "geçersiz dönüş tipi: %s beklendi, %s bulundu",
+				c.fn.result, t)
+		}
+		return true
+	case *parser.ExportStmt:
+		c.expr(s.Result)
+		return true
+	case *parser.BranchStmt:
+		return true
+	}
+	return false
+}
+
+func (c *checker) ifStmt(s *parser.IfStmt) bool {
+	c.openScope()
+	defer c.closeScope()
+
+	if s.Init != nil {
+		c.stmt(s.Init)
+	}
+	c.expr(s.Cond)
+	then, els := c.refine(s.Cond)
+
+	before := c.snapshot()
+	c.apply(then)
+	thenTerm := c.stmt(s.Body)
+	thenState := c.state
+
+	c.state = before
+	c.apply(els)
+	elseTerm := false
+	if s.Else != nil {
+		elseTerm = c.stmt(s.Else)
+	}
+	elseState := c.state
+
+	switch {
+	case thenTerm && elseTerm:
+		c.state = thenState
+	case thenTerm:
+		c.state = elseState
+	case elseTerm:
+		c.state = thenState
+	default:
+		c.state = c.join(thenState, elseState)
+	}
+	return thenTerm && elseTerm
+}
+
+func (c *checker) forIn(s *parser.ForInStmt) {
+	t := c.expr(s.Iterable)
+	iterable := Array | ImmutableArray | String | Bytes | Map | ImmutableMap |
+		Set | ImmutableSet | Channel
+	if !t.IsDynamic() && t&iterable == 0 {
+		c.errorf(s.Iterable.Pos(), "yinelenemez: %s", t)
+	}
+
+	c.openScope()
+	key, value := Any, Any
+	if !t.IsDynamic() &&
+		t&^(Array|ImmutableArray|String|Bytes|Set|ImmutableSet|Channel) == 0 {
+		key = Int
+	}
+	if !t.IsDynamic() && t&^String == 0 {
+		value = Char
+	}
+	if !t.IsDynamic() && t&^Bytes == 0 {
+		value = Int
+	}
+	for _, d := range []struct {
+		ident *parser.Ident
+		t     Type
+	}{{s.Key, key}, {s.Value, value}} {
+		if d.ident == nil || d.ident.Name == "_" {
+			continue
+		}
+		v := &variable{fn: c.fn}
+		c.scope.vars[d.ident.Name] = v
+		c.set(v, d.t)
+	}
+	c.widen(s.Body)
+	c.stmt(s.Body)
+	c.widen(s.Body)
+	c.closeScope()
+}
+
+func (c *checker) widen(nodes ...parser.Stmt) {
+	var walk func(n parser.Stmt)
+	forget := func(e parser.Expr) {
+		if ident, ok := e.(*parser.Ident); ok {
+			if v := c.lookup(ident.Name); v != nil {
+				delete(c.state, v)
+			}
+		}
+	}
+	walk = func(n parser.Stmt) {
+		switch n := n.(type) {
+		case *parser.AssignStmt:
+			if n.Token != token.Define {
+				for _, e := range n.LHS {
+					forget(e)
+				}
+			}
+		case *parser.IncDecStmt:
+			forget(n.Expr)
+		case *parser.BlockStmt:
+			for _, s := range n.Stmts {
+				walk(s)
+			}
+		case *parser.IfStmt:
+			if n.Init != nil {
+				walk(n.Init)
+			}
+			walk(n.Body)
+			if n.Else != nil {
+				walk(n.Else)
+			}
+		case *parser.ForStmt:
+			if n.Init != nil {
+				walk(n.Init)
+			}
+			if n.Post != nil {
+				walk(n.Post)
+			}
+			walk(n.Body)
+		case *parser.ForInStmt:
+			walk(n.Body)
+		}
+	}
+	for _, n := range nodes {
+		if n != nil {
+			walk(n)
+		}
+	}
+}
+
+func (c *checker) check(v *variable, name string, pos parser.Pos, t Type) {
+	if v.declared != 0 && !assignable(t, v.declared) {
+		c.errorf(pos, "'%s' için geçersiz tip: %s beklendi, %s bulundu",
+			name, v.declared, t)
+	}
+}
+
+func (c *checker) assign(s *parser.AssignStmt) {
+	if len(s.LHS) != 1 || len(s.RHS) != 1 {
+		for _, e := range s.RHS {
+			c.expr(e)
+		}
+		return
+	}
+	lhs, rhs := s.LHS[0], s.RHS[0]
+	ident, isIdent := lhs.(*parser.Ident)
+
+	switch s.Token {
+	case token.Define:
+		if !isIdent {
+			c.expr(rhs)
+			return
+		}
+		v := &variable{declared: c.resolveType(s.Type), fn: c.fn}
+		if fl, ok := rhs.(*parser.FuncLit); ok {
+			v.sig = c.signature(fl.Type)
+			c.scope.vars[ident.Name] = v
+		}
+		t := c.expr(rhs)
+		c.check(v, ident.Name, rhs.Pos(), t)
+		c.scope.vars[ident.Name] = v
+		c.set(v, t)
+	case token.Assign:
+		t := c.expr(rhs)
+		if !isIdent {
+			c.expr(lhs)
+			return
+		}
+		if v := c.lookup(ident.Name); v != nil {
+			c.check(v, ident.Name, rhs.Pos(), t)
+			v.sig = nil
+			if fl, ok := rhs.(*parser.FuncLit); ok && v.fn == c.fn {
+				v.sig = c.signature(fl.Type)
+			}
+			c.set(v, t)
+		}
+	default:
+		lt := c.expr(lhs)
+		rt := c.expr(rhs)
+		res := c.binary(s.TokenPos, lt, assignOp(s.Token), rt)
+		if isIdent {
+			if v := c.lookup(ident.Name); v != nil {
+				c.check(v, ident.Name, s.TokenPos, res)
+				c.set(v, res)
+			}
+		}
+	}
+}
+
+func assignOp(tok token.Token) token.Token {
+	switch tok {
+	case token.AddAssign:
+		return token.Add
+	case token.SubAssign:
+		return token.Sub
+	case token.MulAssign:
+		return token.Mul
+	case token.QuoAssign:
+		return token.Quo
+	case token.RemAssign:
+		return token.Rem
+	case token.AndAssign:
+		return token.And
+	case token.OrAssign:
+		return token.Or
+	case token.XorAssign:
+		return token.Xor
+	case token.AndNotAssign:
+		return token.AndNot
+	case token.ShlAssign:
+		return token.Shl
+	case token.ShrAssign:
+		return token.Shr
+	}
+	return tok
+}
+
+func (c *checker) binary(pos parser.Pos, lhs Type, op token.Token, rhs Type) Type {
+	res, invalid := binaryOp(lhs, op, rhs)
+	if len(invalid) == 0 {
+		return res
+	}
+	if res == 0 {
+		c.errorf(pos, "geçersiz operasyon: %s %s %s", lhs, op, rhs)
+		return Any
+	}
+	c.errorf(pos, "geçersiz operasyon olabilir: %s %s %s",
+		invalid[0][0], op, invalid[0][1])
+	return res
+}
+
+func (c *checker) expr(e parser.Expr) Type {
+	switch e := e.(type) {
+	case *parser.IntLit:
+		return Int
+	case *parser.FloatLit:
+		return Float
+	case *parser.StringLit:
+		return String
+	case *parser.CharLit:
+		return Char
+	case *parser.BoolLit:
+		return Bool
+	case *parser.UndefinedLit:
+		return Undefined
+	case *parser.ArrayLit:
+		for _, elt := range e.Elements {
+			c.expr(elt)
+		}
+		return Array
+	case *parser.MapLit:
+		for _, elt := range e.Elements {
+			if elt.KeyExpr != nil {
+				c.expr(elt.KeyExpr)
+			}
+			c.expr(elt.Value)
+		}
+		return Map
+	case *parser.Ident:
+		if v := c.lookup(e.Name); v != nil {
+			return c.typeOfVar(v)
+		}
+		if _, ok := builtins[e.Name]; ok {
+			return Func
+		}
+		return Any
+	case *parser.ParenExpr:
+		return c.expr(e.Expr)
+	case *parser.UnaryExpr:
+		return c.unary(e)
+	case *parser.BinaryExpr:
+		return c.binaryExpr(e)
+	case *parser.CondExpr:
+		c.expr(e.Cond)
+		then, els := c.refine(e.Cond)
+		before := c.snapshot()
+		c.apply(then)
+		t := c.expr(e.True)
+		c.state = before
+		before = c.snapshot()
+		c.apply(els)
+		t |= c.expr(e.False)
+		c.state = before
+		return t
+	case *parser.CallExpr:
+		return c.call(e)
+	case *parser.SpawnExpr:
+		c.call(e.Call)
+		return Task
+	case *parser.ErrorExpr:
+		c.expr(e.Expr)
+		return Err
+	case *parser.ImmutableExpr:
+		return immutable(c.expr(e.Expr))
+	case *parser.FuncLit:
+		c.funcLit(e)
+		return Func
+	case *parser.IndexExpr:
+		c.expr(e.Expr)
+		c.expr(e.Index)
+	case *parser.SelectorExpr:
+		c.expr(e.Expr)
+	case *parser.SliceExpr:
+		t := c.expr(e.Expr)
+		if e.Low != nil {
+			c.expr(e.Low)
+		}
+		if e.High != nil {
+			c.expr(e.High)
+		}
+		if !t.IsDynamic() && t&^(String|Bytes) == 0 {
+			return t
+		}
+	}
+	return Any
+}
+
+func (c *checker) unary(e *parser.UnaryExpr) Type {
+	t := c.expr(e.Expr)
+	var valid Type
+	switch e.Token {
+	case token.Not:
+		return Bool
+	case token.Sub, token.Add:
+		valid = Int | Float
+	case token.Xor:
+		valid = Int
+	default:
+		return Any
+	}
+	if t.IsDynamic() {
+		return Any
+	}
+	if t&valid == 0 {
+		c.errorf(e.TokenPos, "geçersiz operasyon: %s%s", e.Token, t)
+		return Any
+	}
+	if t&^valid != 0 {
+		c.errorf(e.TokenPos, "geçersiz operasyon olabilir: %s%s",
+			e.Token, t&^valid)
+	}
+	return t & valid
+}
+
+func (c *checker) binaryExpr(e *parser.BinaryExpr) Type {
+	switch e.Token {
+	case token.LAnd, token.LOr:
+		lt := c.expr(e.LHS)
+		then, els := c.refine(e.LHS)
+		before := c.snapshot()
+		if e.Token == token.LAnd {
+			c.apply(then)
+		} else {
+			c.apply(els)
+		}
+		rt := c.expr(e.RHS)
+		c.state = before
+		return lt | rt
+	case token.Equal, token.NotEqual:
+		c.expr(e.LHS)
+		c.expr(e.RHS)
+		return Bool
+	}
+	lt := c.expr(e.LHS)
+	rt := c.expr(e.RHS)
+	return c.binary(e.TokenPos, lt, e.Token, rt)
+}
+
+func (c *checker) signatureOf(e parser.Expr) *signature {
+	switch e := e.(type) {
+	case *parser.Ident:
+		if v := c.lookup(e.Name); v != nil {
+			if v.escaped {
+				return nil
+			}
+			return v.sig
+		}
+		return builtins[e.Name]
+	case *parser.ParenExpr:
+		return c.signatureOf(e.Expr)
+	case *parser.FuncLit:
+		return c.signature(e.Type)
+	}
+	return nil
+}
+
+func (c *checker) call(e *parser.CallExpr) Type {
+	ft := c.expr(e.Func)
+	args := make([]Type, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = c.expr(arg)
+	}
+	if !ft.IsDynamic() && ft&Func == 0 {
+		c.errorf(e.Func.Pos(), "çağrılamaz: %s", ft)
+		return Any
+	}
+
+	sig := c.signatureOf(e.Func)
+	if sig == nil {
+		return Any
+	}
+	spread := e.Ellipsis.IsValid()
+	if spread {
+		args = args[:len(args)-1]
+	} else {
+		switch {
+		case sig.rest != 0 && len(args) < sig.min:
+			c.errorf(e.LParen, "yanlış argüman sayısı: want>=%d, got=%d",
+				sig.min, len(args))
+		case sig.rest == 0 && sig.min == len(sig.params) &&
+			len(args) != sig.min:
+			c.errorf(e.LParen, "yanlış argüman sayısı: want=%d, got=%d",
+				sig.min, len(args))
+		case sig.rest == 0 &&
+			(len(args) < sig.min || len(args) > len(sig.params)):
+			c.errorf(e.LParen, "yanlış argüman sayısı: want=%d-%d, got=%d",
+				sig.min, len(sig.params), len(args))
+		}
+	}
+	for i, at := range args {
+		name, want := sig.param(i)
+		if want == 0 || assignable(at, want) {
+			continue
+		}
+		if name == "" {
+			name = fmt.Sprint(i + 1)
+		}
+		c.errorf(e.Args[i].Pos(),
+			"argüman '%s' için geçersiz tip. %s beklendi, %s bulundu",
+			name, want, at)
+	}
+	return sig.resultOf(args)
+}
+
+func (c *checker) funcLit(e *parser.FuncLit) {
+	sig := c.signature(e.Type)
+	outerFn, outerState := c.fn, c.state
+	c.fn = &function{}
+	if e.Type.Result != nil {
+		c.fn.result = sig.result
+	}
+	c.state = make(map[*variable]Type)
+	c.openScope()
+
+	params := e.Type.Params
+	for i, p := range params.List {
+		v := &variable{fn: c.fn}
+		if params.VarArgs && i == len(params.List)-1 {
+			v.declared = Array
+		} else if params.Type(i) != nil {
+			v.declared = sig.params[i]
+		}
+		c.scope.vars[p.Name] = v
+	}
+
+	terminated := c.stmts(e.Body.Stmts)
+	if !terminated && c.fn.result != 0 && !assignable(Undefined, c.fn.result) {
+		c.errorf(e.Body.RBrace, "fonksiyon dönüş yapmadan bitiyor: %s bekleniyor",
+			c.fn.result)
+	}
+
+	c.closeScope()
+	c.fn, c.state = outerFn, outerState
+}
+
+func (c *checker) refine(e parser.Expr) (then, els refinement) {
+	switch e := e.(type) {
+	case *parser.ParenExpr:
+		return c.refine(e.Expr)
+	case *parser.UnaryExpr:
+		if e.Token == token.Not {
+			then, els = c.refine(e.Expr)
+			return els, then
+		}
+	case *parser.CallExpr:
+		fn, ok := e.Func.(*parser.Ident)
+		if !ok || len(e.Args) != 1 || c.lookup(fn.Name) != nil {
+			return nil, nil
+		}
+		t, ok := predicates[fn.Name]
+		if !ok {
+			return nil, nil
+		}
+		if v := c.local(e.Args[0]); v != nil {
+			cur := c.typeOfVar(v)
+			return refinement{v: narrow(cur, t)}, refinement{v: exclude(cur, t)}
+		}
+	case *parser.BinaryExpr:
+		switch e.Token {
+		case token.Equal, token.NotEqual:
+			operand := e.LHS
+			if _, ok := e.LHS.(*parser.UndefinedLit); ok {
+				operand = e.RHS
+			} else if _, ok := e.RHS.(*parser.UndefinedLit); !ok {
+				return nil, nil
+			}
+			v := c.local(operand)
+			if v == nil {
+				return nil, nil
+			}
+			cur := c.typeOfVar(v)
+			then = refinement{v: narrow(cur, Undefined)}
+			els = refinement{v: exclude(cur, Undefined)}
+			if e.Token == token.NotEqual {
+				then, els = els, then
+			}
+			return then, els
+		case token.LAnd:
+			t1, _ := c.refine(e.LHS)
+			before := c.snapshot()
+			c.apply(t1)
+			t2, _ := c.refine(e.RHS)
+			c.state = before
+			return merge(t1, t2), nil
+		case token.LOr:
+			_, f1 := c.refine(e.LHS)
+			before := c.snapshot()
+			c.apply(f1)
+			_, f2 := c.refine(e.RHS)
+			c.state = before
+			return nil, merge(f1, f2)
+		}
+	}
+	return nil, nil
+}
+
+func (c *checker) local(e parser.Expr) *variable {
+	ident, ok := e.(*parser.Ident)
+	if !ok {
+		return nil
+	}
+	v := c.lookup(ident.Name)
+	if v == nil || v.fn != c.fn || v.escaped {
+		return nil
+	}
+	return v
+}
+
+func merge(a, b refinement) refinement {
+	r := make(refinement, len(a)+len(b))
+	for v, t := range a {
+		r[v] = t
+	}
+	for v, t := range b {
+		r[v] = t
+	}
+	return r
+}
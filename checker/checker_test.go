@@ -0,0 +1,167 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+func check(t *testing.T, src string) ErrorList {
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile("test", -1, len(src))
+	file, err := parser.NewParser(srcFile, []byte(src), nil).ParseFile()
+	if err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return Check(file)
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		msg  string
+		pos  string
+	}{
+		{"bildirim", "x: sayı := 1\ny: yazı := \"a\"", "", ""},
+		{"bildirim tipi",
+			`x: sayı := "a"`,
+			"'x' için geçersiz tip: sayı beklendi, yazı bulundu", "test:1:13"},
+		{"atama tipi",
+			"x: sayı := 1\nx = \"a\"",
+			"'x' için geçersiz tip: sayı beklendi, yazı bulundu", "test:2:5"},
+		{"bilinmeyen tip",
+			"x: bilinmez := 1",
+			"bilinmeyen tip: bilinmez", "test:1:4"},
+		{"operasyon",
+			`x := 1 + "a"`,
+			"geçersiz operasyon: sayı + yazı", "test:1:8"},
+		{"yinelenemez",
+			"tekrarla a in 5 {}",
+			"yinelenemez: sayı", "test:1:15"},
+		{"çağrılamaz",
+			"1()",
+			"çağrılamaz: sayı", "test:1:1"},
+		{"fonksiyon",
+			"topla := fn(a: sayı, b: sayı) -> sayı { dön a + b }\nx: sayı := topla(1, 2)",
+			"", ""},
+		{"argüman tipi",
+			"topla := fn(a: sayı, b: sayı) -> sayı { dön a + b }\ntopla(1, \"b\")",
+			"argüman 'b' için geçersiz tip. sayı beklendi, yazı bulundu", "test:2:10"},
+		{"argüman sayısı",
+			"g := fn(a: sayı) -> sayı { dön a }\ng(1, 2)",
+			"yanlış argüman sayısı: want=1, got=2", "test:2:2"},
+		{"dönüş tipi",
+			"g := fn(a: sayı) -> yazı { dön a }",
+			"geçersiz dönüş tipi: yazı beklendi, sayı bulundu", "test:1:30"},
+		{"eksik dönüş",
+			"g := fn(a: sayı) -> sayı { eğer a > 0 { dön 1 } }",
+			"fonksiyon dönüş yapmadan bitiyor: sayı bekleniyor", "test:1:53"},
+		{"tip belirtilmemiş", "x := 1\nx = \"a\"\ny := x + 1", "", ""},
+	}
+	for _, tt := range tests {
+		errs := check(t, tt.src)
+		if tt.msg == "" {
+			if len(errs) != 0 {
+				t.Fatalf("%s: beklenmeyen hata: %v", tt.name, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 {
+			t.Fatalf("%s: %d hata, beklenen 1: %v", tt.name, len(errs), errs)
+		}
+		if errs[0].Msg != tt.msg {
+			t.Fatalf("%s: hata %q, beklenen %q", tt.name, errs[0].Msg, tt.msg)
+		}
+		if pos := errs[0].Pos.String(); pos != tt.pos {
+			t.Fatalf("%s: konum %s, beklenen %s", tt.name, pos, tt.pos)
+		}
+	}
+}
+
+func TestCheckNarrowing(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		msg  string
+	}{
+		{"tanımsız değil",
+			"x: sayı|tanımsız := 1\neğer x != tanımsız { y: sayı := x }",
+			""},
+		{"daraltılmamış",
+			"x: sayı|yazı := 1\nx = \"a\"\ny: sayı := x",
+			"'y' için geçersiz tip: sayı beklendi, yazı bulundu"},
+		{"yüklem",
+			"x: sayı|yazı := 1\neğer sayı_mı(x) { y: sayı := x } yoksa { z: yazı := x }",
+			""},
+		{"yüklem dalı",
+			"x: sayı|yazı := 1\neğer sayı_mı(x) { y: yazı := x }",
+			"'y' için geçersiz tip: yazı beklendi, sayı bulundu"},
+		{"değil",
+			"x: sayı|yazı := 1\neğer !yazı_mı(x) { y: sayı := x }",
+			""},
+		{"ve",
+			"x: sayı|yazı|tanımsız := 1\neğer x != tanımsız && sayı_mı(x) { y: sayı := x }",
+			""},
+		{"erken dönüş",
+			"g := fn(x: sayı|yazı) { eğer yazı_mı(x) { dön }; y: sayı := x }",
+			""},
+		{"dal birleşimi",
+			"x: sayı|tanımsız := tanımsız\neğer x == tanımsız { x = 0 }\ny: sayı := x",
+			""},
+		{"dal birleşimi tanımsız",
+			"x: sayı|tanımsız := tanımsız\neğer x == tanımsız { yazdır(1) }\ny: sayı := x",
+			"'y' için geçersiz tip: sayı beklendi, tanımsız bulundu"},
+	}
+	for _, tt := range tests {
+		errs := check(t, tt.src)
+		switch {
+		case tt.msg == "" && len(errs) != 0:
+			t.Fatalf("%s: beklenmeyen hata: %v", tt.name, errs)
+		case tt.msg != "" && (len(errs) != 1 || errs[0].Msg != tt.msg):
+			t.Fatalf("%s: hatalar %v, beklenen %q", tt.name, errs, tt.msg)
+		}
+	}
+}
+
+func TestCheckBuiltins(t *testing.T) {
+	tests := []struct {
+		src string
+		msg string
+	}{
+		{"n: sayı := uzunluk([1, 2])", ""},
+		{`n: sayı := uzunluk("abc")`, ""},
+		{"uzunluk(1)", "argüman 'first' için geçersiz tip. " +
+			"yazı|bytes|liste|harita|küme beklendi, sayı bulundu"},
+		{"uzunluk()", "yanlış argüman sayısı: want=1, got=0"},
+		{"b: mantıksal := sayı_mı(1)", ""},
+		{"y: yazı := yazı(1)",
+			"'y' için geçersiz tip: yazı beklendi, yazı|tanımsız bulundu"},
+		{`y: yazı := yazı(1, "")`, ""},
+		{`y: sayı := sayı("1")`,
+			"'y' için geçersiz tip: sayı beklendi, sayı|tanımsız bulundu"},
+		{"a: liste := [1]\nb: liste := ekle(a, 2)", ""},
+		{"k: kanal := kanal()", ""},
+	}
+	for _, tt := range tests {
+		errs := check(t, tt.src)
+		switch {
+		case tt.msg == "" && len(errs) != 0:
+			t.Fatalf("%s: beklenmeyen hata: %v", tt.src, errs)
+		case tt.msg != "" && (len(errs) != 1 || errs[0].Msg != tt.msg):
+			t.Fatalf("%s: hatalar %v, beklenen %q", tt.src, errs, tt.msg)
+		}
+	}
+}
+
+func TestErrorListError(t *testing.T) {
+	errs := check(t, "x: sayı := \"a\"\ny: yazı := 1")
+	if len(errs) != 2 {
+		t.Fatalf("%d hata, beklenen 2", len(errs))
+	}
+	if msg := errs.Error(); !strings.HasPrefix(msg, "Tip hatası: 'x'") ||
+		!strings.HasSuffix(msg, "(ve +1 hatalar)") {
+		t.Fatalf("hata metni %q", msg)
+	}
+}
@@ -0,0 +1,249 @@
+package checker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/token"
+)
+
+type Type uint32
+
+const (
+	Int Type = 1 << iota
+	Float
+	String
+	Bool
+	Char
+	Bytes
+	Array
+	ImmutableArray
+	Map
+	ImmutableMap
+	Set
+	ImmutableSet
+	Func
+	Err
+	Undefined
+	Time
+	Channel
+	Task
+	Dynamic
+
+	Any Type = 1<<iota - 1
+)
+
+var typeNames = map[string]Type{
+	"sayı":      Int,
+	"float":     Float,
+	"yazı":      String,
+	"mantıksal": Bool,
+	"karakter":  Char,
+	"bytes":     Bytes,
+	"liste":     Array | ImmutableArray,
+	"harita":    Map | ImmutableMap,
+	"küme":      Set | ImmutableSet,
+	"fn":        Func,
+	"hata":      Err,
+	"tanımsız":  Undefined,
+	"zaman":     Time,
+	"kanal":     Channel,
+	"görev":     Task,
+	"herhangi":  Any,
+}
+
+var typeParts = []struct {
+	t    Type
+	name string
+}{
+	{Int, "sayı"},
+	{Float, "float"},
+	{String, "yazı"},
+	{Bool, "mantıksal"},
+	{Char, "karakter"},
+	{Bytes, "bytes"},
+	{Array | ImmutableArray, "liste"},
+	{Array, "liste"},
+	{ImmutableArray, "sabit-liste"},
+	{Map | ImmutableMap, "harita"},
+	{Map, "harita"},
+	{ImmutableMap, "sabit-harita"},
+	{Set | ImmutableSet, "küme"},
+	{Set, "küme"},
+	{ImmutableSet, "sabit-küme"},
+	{Func, "fn"},
+	{Err, "hata"},
+	{Undefined, "tanımsız"},
+	{Time, "zaman"},
+	{Channel, "kanal"},
+	{Task, "görev"},
+}
+
+func (t Type) IsDynamic() bool {
+	return t&Dynamic != 0
+}
+
+func (t Type) String() string {
+	if t.IsDynamic() {
+		return "herhangi"
+	}
+	var names []string
+	for _, p := range typeParts {
+		if t&p.t == p.t {
+			names = append(names, p.name)
+			t &^= p.t
+		}
+	}
+	if len(names) == 0 {
+		return "hiçbiri"
+	}
+	return strings.Join(names, "|")
+}
+
+func assignable(value, want Type) bool {
+	if value.IsDynamic() || want.IsDynamic() {
+		return true
+	}
+	return value&^want == 0
+}
+
+func narrow(t, to Type) Type {
+	if t.IsDynamic() {
+		return to &^ Dynamic
+	}
+	return t & to
+}
+
+func exclude(t, from Type) Type {
+	return t &^ from
+}
+
+func immutable(t Type) Type {
+	if t&Array != 0 {
+		t = t&^Array | ImmutableArray
+	}
+	if t&Map != 0 {
+		t = t&^Map | ImmutableMap
+	}
+	if t&Set != 0 {
+		t = t&^Set | ImmutableSet
+	}
+	return t
+}
+
+func typeOf(o lokum.Object) Type {
+	switch o.(type) {
+	case *lokum.Int:
+		return Int
+	case *lokum.Float:
+		return Float
+	case *lokum.String:
+		return String
+	case *lokum.Bool:
+		return Bool
+	case *lokum.Char:
+		return Char
+	case *lokum.Bytes:
+		return Bytes
+	case *lokum.Array:
+		return Array
+	case *lokum.ImmutableArray:
+		return ImmutableArray
+	case *lokum.Map:
+		return Map
+	case *lokum.ImmutableMap:
+		return ImmutableMap
+	case *lokum.Set:
+		return Set
+	case *lokum.ImmutableSet:
+		return ImmutableSet
+	case *lokum.CompiledFunction, *lokum.BuiltinFunction, *lokum.UserFunction:
+		return Func
+	case *lokum.Error:
+		return Err
+	case *lokum.Undefined:
+		return Undefined
+	case *lokum.Time:
+		return Time
+	case *lokum.Channel:
+		return Channel
+	case *lokum.Task:
+		return Task
+	}
+	return Any
+}
+
+func sample(t Type) lokum.Object {
+	switch t {
+	case Int:
+		return &lokum.Int{Value: 1}
+	case Float:
+		return &lokum.Float{Value: 1}
+	case String:
+		return &lokum.String{Value: "a"}
+	case Bool:
+		return lokum.TrueValue
+	case Char:
+		return &lokum.Char{Value: 'a'}
+	case Bytes:
+		return &lokum.Bytes{Value: []byte{1}}
+	case Array:
+		return &lokum.Array{}
+	case ImmutableArray:
+		return &lokum.ImmutableArray{}
+	case Map:
+		return &lokum.Map{Value: lokum.NewOrderedMap(0)}
+	case ImmutableMap:
+		return &lokum.ImmutableMap{Value: lokum.NewOrderedMap(0)}
+	case Set:
+		return &lokum.Set{Value: lokum.NewOrderedMap(0)}
+	case ImmutableSet:
+		return &lokum.ImmutableSet{Value: lokum.NewOrderedMap(0)}
+	case Func:
+		return &lokum.CompiledFunction{}
+	case Err:
+		return &lokum.Error{Value: lokum.UndefinedValue}
+	case Undefined:
+		return lokum.UndefinedValue
+	case Time:
+		return &lokum.Time{Value: time.Unix(0, 0)}
+	case Channel:
+		return &lokum.Channel{}
+	case Task:
+		return &lokum.Task{}
+	}
+	return nil
+}
+
+func binaryOp(lhs Type, op token.Token, rhs Type) (res Type, invalid [][2]Type) {
+	if lhs.IsDynamic() || rhs.IsDynamic() {
+		return Any, nil
+	}
+	for l := Type(1); l < Dynamic; l <<= 1 {
+		if lhs&l == 0 {
+			continue
+		}
+		for r := Type(1); r < Dynamic; r <<= 1 {
+			if rhs&r == 0 {
+				continue
+			}
+			o, err := sampleOp(l, op, r)
+			if err != nil {
+				invalid = append(invalid, [2]Type{l, r})
+				continue
+			}
+			res |= typeOf(o)
+		}
+	}
+	return
+}
+
+func sampleOp(lhs Type, op token.Token, rhs Type) (o lokum.Object, err error) {
+	defer func() {
+		if recover() != nil {
+			o, err = nil, nil
+		}
+	}()
+	return sample(lhs).BinaryOp(op, sample(rhs))
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/onrirr/lokum"
+)
+
+const debugPrompt = "(hata-ayıkla) "
+
+type breakpointFlags []string
+
+func (b *breakpointFlags) String() string {
+	return strings.Join(*b, ",")
+}
+
+func (b *breakpointFlags) Set(value string) error {
+	*b = append(*b, value)
+	return nil
+}
+
+type DebugSession struct {
+	in       *bufio.Scanner
+	out      io.Writer
+	main     string
+	debugger *lokum.Debugger
+	sources  map[string][]string
+	frame    int
+}
+
+func NewDebugSession(
+	bytecode *lokum.Bytecode,
+	main string,
+	src []byte,
+	in io.Reader,
+	out io.Writer,
+) *DebugSession {
+	s := &DebugSession{
+		in:      bufio.NewScanner(in),
+		out:     out,
+		main:    main,
+		sources: make(map[string][]string),
+	}
+	if src != nil {
+		s.sources[main] = strings.Split(string(src), "\n")
+	}
+	s.debugger = lokum.NewDebugger(bytecode, s.stop)
+	return s
+}
+
+func (s *DebugSession) Debugger() *lokum.Debugger {
+	return s.debugger
+}
+
+func (s *DebugSession) AddBreakpoint(spec string) error {
+	file, line := s.main, spec
+	if i := strings.LastIndex(spec, ":"); i >= 0 {
+		file, line = spec[:i], spec[i+1:]
+	}
+	n, err := strconv.Atoi(line)
+	if err != nil || n < 1 {
+		return fmt.Errorf("geçersiz kesme noktası: %s", spec)
+	}
+	bp := s.debugger.AddBreakpoint(file, n)
+	if !bp.Verified {
+		_, _ = fmt.Fprintf(s.out,
+			"kesme noktası %d: %s:%d satırında kod yok\n",
+			bp.ID, bp.File, bp.Requested)
+		return nil
+	}
+	_, _ = fmt.Fprintf(s.out, "kesme noktası %d: %s:%d\n",
+		bp.ID, bp.File, bp.Line)
+	return nil
+}
+
+func (s *DebugSession) stop(st *lokum.DebugState) lokum.DebugAction {
+	s.frame = 0
+	if st.Breakpoint != nil {
+		_, _ = fmt.Fprintf(s.out, "durdu (%s %d): %s\n",
+			st.Reason, st.Breakpoint.ID, st.Pos)
+	} else {
+		_, _ = fmt.Fprintf(s.out, "durdu (%s): %s\n", st.Reason, st.Pos)
+	}
+	s.printLine(st.Pos.Filename, st.Pos.Line, true)
+
+	for {
+		_, _ = fmt.Fprint(s.out, debugPrompt)
+		if !s.in.Scan() {
+			_, _ = fmt.Fprintln(s.out)
+			return lokum.DebugStop
+		}
+		line := strings.TrimSpace(s.in.Text())
+		cmd, arg := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			cmd, arg = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		switch cmd {
+		case "":
+		case "d", "devam":
+			return lokum.DebugContinue
+		case "a", "adım":
+			return lokum.DebugStepIn
+		case "s", "sonraki":
+			return lokum.DebugStepOver
+		case "ç", "çık":
+			return lokum.DebugStepOut
+		case "q", "bitir":
+			return lokum.DebugStop
+		case "k", "kes":
+			if err := s.AddBreakpoint(arg); err != nil {
+				_, _ = fmt.Fprintln(s.out, err)
+			}
+		case "kl", "kesmeler":
+			for _, bp := range s.debugger.Breakpoints() {
+				note := ""
+				if !bp.Verified {
+					note = " (kod yok)"
+				}
+				_, _ = fmt.Fprintf(s.out, "%d\t%s:%d%s\n",
+					bp.ID, bp.File, bp.Line, note)
+			}
+		case "sil":
+			id, err := strconv.Atoi(arg)
+			if err != nil || !s.debugger.RemoveBreakpoint(id) {
+				_, _ = fmt.Fprintf(s.out, "kesme noktası bulunamadı: %s\n", arg)
+			}
+		case "y", "yığın":
+			for i, f := range st.Frames() {
+				mark := " "
+				if i == s.frame {
+					mark = "*"
+				}
+				_, _ = fmt.Fprintf(s.out, "%s%d %s\t%s\n", mark, i, f.Name, f.Pos)
+			}
+		case "çr", "çerçeve":
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 0 || n >= len(st.Frames()) {
+				_, _ = fmt.Fprintf(s.out, "geçersiz çerçeve: %s\n", arg)
+				break
+			}
+			s.frame = n
+			f := st.Frames()[n]
+			_, _ = fmt.Fprintf(s.out, "%d %s\t%s\n", n, f.Name, f.Pos)
+		case "yerel":
+			f := st.Frames()[s.frame]
+			s.printVars(f.Locals())
+			s.printVars(f.Free())
+		case "genel":
+			s.printVars(st.Globals())
+		case "p", "yaz":
+			res, err := st.Eval(st.Frames()[s.frame], arg)
+			if err != nil {
+				_, _ = fmt.Fprintln(s.out, err)
+				break
+			}
+			_, _ = fmt.Fprintln(s.out, res)
+		case "l", "liste":
+			f := st.Frames()[s.frame]
+			for n := f.Pos.Line - 5; n <= f.Pos.Line+5; n++ {
+				s.printLine(f.Pos.Filename, n, n == f.Pos.Line)
+			}
+		case "?", "yardım":
+			_, _ = fmt.Fprint(s.out, debugHelp)
+		default:
+			_, _ = fmt.Fprintf(s.out,
+				"bilinmeyen komut: %s (yardım için ?)\n", cmd)
+		}
+	}
+}
+
+func (s *DebugSession) printVars(vars []*lokum.Variable) {
+	for _, v := range vars {
+		_, _ = fmt.Fprintf(s.out, "%s = %s\n", v.Name(), v.Object())
+	}
+}
+
+func (s *DebugSession) printLine(file string, n int, current bool) {
+	lines, ok := s.sources[file]
+	if !ok {
+		data, err := ioutil.ReadFile(file)
+		if err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+		s.sources[file] = lines
+	}
+	if n < 1 || n > len(lines) {
+		return
+	}
+	mark := " "
+	if current {
+		mark = ">"
+	}
+	_, _ = fmt.Fprintf(s.out, "%s%4d\t%s\n", mark, n,
+		strings.TrimRight(lines[n-1], "\r"))
+}
+
+const debugHelp = `d, devam            çalışmaya devam et
+a, adım             sonraki satıra geç, fonksiyonlara gir
+s, sonraki          sonraki satıra geç, fonksiyonların üstünden atla
+ç, çık              içinde bulunulan fonksiyondan çık
+k, kes [dosya:]satır kesme noktası ekle
+kl, kesmeler        kesme noktalarını listele
+sil <no>            kesme noktasını sil
+y, yığın            çağrı yığınını göster
+çr, çerçeve <no>    çerçeve seç
+yerel               seçili çerçevenin yerel ve serbest değişkenleri
+genel               global değişkenler
+p, yaz <ifade>      ifadeyi seçili çerçevede değerlendir
+l, liste            kaynak kodu göster
+q, bitir            programı durdur
+`
+
+func DebugFile(
+	modules *lokum.ModuleMap,
+	data []byte,
+	inputFile string,
+	resolvePath bool,
+	breakpoints []string,
+	args []string,
+	in io.Reader,
+	out io.Writer,
+) error {
+	bytecode, err := loadBytecode(modules, data, inputFile, resolvePath)
+	if err != nil {
+		return err
+	}
+	var src []byte
+	if !lokum.IsBytecode(data) {
+		src = data
+	}
+
+	session := NewDebugSession(bytecode, filepath.Base(inputFile), src, in, out)
+	for _, spec := range breakpoints {
+		if err := session.AddBreakpoint(spec); err != nil {
+			return err
+		}
+	}
+	machine := lokum.NewVM(bytecode, scriptGlobals(args), -1)
+	machine.SetDebugger(session.Debugger())
+	if err := machine.Run(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(out, "program bitti")
+	return nil
+}
+
+func loadBytecode(
+	modules *lokum.ModuleMap,
+	data []byte,
+	inputFile string,
+	resolvePath bool,
+) (*lokum.Bytecode, error) {
+	if !lokum.IsBytecode(data) {
+		return compileSrc(modules, data, inputFile, resolvePath)
+	}
+	bytecode := &lokum.Bytecode{}
+	if err := bytecode.Decode(bytes.NewReader(data), modules); err != nil {
+		return nil, err
+	}
+	return bytecode, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/stdlib"
+)
+
+func CoverFile(
+	modules *lokum.ModuleMap,
+	data []byte,
+	inputFile string,
+	resolvePath bool,
+	args []string,
+) (*lokum.Coverage, error) {
+	bytecode, err := loadBytecode(modules, data, inputFile, resolvePath)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := lokum.NewCoverage()
+	machine := lokum.NewVM(bytecode, scriptGlobals(args), -1)
+	machine.SetCoverage(coverage)
+	return coverage, machine.Run()
+}
+
+func coverageFiles(
+	coverage *lokum.Coverage,
+	inputFiles ...string,
+) []*lokum.CoverageFile {
+	var files []*lokum.CoverageFile
+	for _, f := range coverage.Files() {
+		if _, ok := stdlib.SourceModules[f.Name]; ok {
+			continue
+		}
+		for _, inputFile := range inputFiles {
+			if f.Name == filepath.Base(inputFile) {
+				f.Name = inputFile
+				break
+			}
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+func printCoverage(w io.Writer, files []*lokum.CoverageFile) {
+	total, covered := 0, 0
+	for _, f := range files {
+		total += len(f.Lines)
+		covered += f.Covered()
+		_, _ = fmt.Fprintf(w, "%6.1f%%  %4d/%-4d  %s\n",
+			f.Percent(), f.Covered(), len(f.Lines), f.Name)
+	}
+	percent := 100.0
+	if total > 0 {
+		percent = 100 * float64(covered) / float64(total)
+	}
+	_, _ = fmt.Fprintf(w, "%6.1f%%  %4d/%-4d  toplam\n", percent, covered, total)
+}
+
+func writeCoverage(files []*lokum.CoverageFile, lcov, html string) error {
+	if lcov != "" {
+		out, err := os.Create(lcov)
+		if err != nil {
+			return err
+		}
+		if err := lokum.WriteLCOV(out, files); err != nil {
+			_ = out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+	if html != "" {
+		out, err := os.Create(html)
+		if err != nil {
+			return err
+		}
+		err = lokum.WriteCoverageHTML(out, files, ioutil.ReadFile)
+		if err != nil {
+			_ = out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
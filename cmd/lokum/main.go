@@ -1,291 +1,1055 @@
-package main
-
-import (
-	"bufio"
-	"bytes"
-	"flag"
-	"fmt"
-	"io"
-	"io/ioutil"
-	"os"
-	"path/filepath"
-	"strings"
-
-	"github.com/onrirr/lokum"
-	"github.com/onrirr/lokum/parser"
-	"github.com/onrirr/lokum/stdlib"
-)
-
-const (
-	sourceFileExt = ".lokum"
-	replPrompt    = ">> "
-)
-
-var (
-	compileOutput string
-	showHelp      bool
-	showVersion   bool
-	resolvePath   bool
-	version       = "beta"
-)
-
-func init() {
-	flag.BoolVar(&showHelp, "yardım", false, "Yardım göster")
-	flag.StringVar(&compileOutput, "o", "", "Compile çıkış dosyası")
-	flag.BoolVar(&showVersion, "sürüm", false, "Sürümü göster")
-	flag.BoolVar(&resolvePath, "resolve", false,
-		"Importları çözümle.")
-	flag.Parse()
-}
-
-func main() {
-	if showHelp {
-		doHelp()
-		os.Exit(2)
-	} else if showVersion {
-		fmt.Println(version)
-		return
-	}
-
-	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
-	inputFile := flag.Arg(0)
-	if inputFile == "" {
-
-		RunREPL(modules, os.Stdin, os.Stdout)
-		return
-	}
-
-	inputData, err := ioutil.ReadFile(inputFile)
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr,
-			"Dosyayı bulamadım: %s\n", err.Error())
-		os.Exit(1)
-	}
-
-	inputFile, err = filepath.Abs(inputFile)
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Dosyayı bulamadım: %s\n", err)
-		os.Exit(1)
-	}
-
-	if len(inputData) > 1 && string(inputData[:2]) == "#!" {
-		copy(inputData, "//")
-	}
-
-	if compileOutput != "" {
-		err := CompileOnly(modules, inputData, inputFile,
-			compileOutput)
-		if err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
-		}
-	} else if filepath.Ext(inputFile) == sourceFileExt {
-		err := CompileAndRun(modules, inputData, inputFile)
-		if err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
-		}
-	} else {
-		if err := RunCompiled(modules, inputData); err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
-		}
-	}
-}
-
-func CompileOnly(
-	modules *lokum.ModuleMap,
-	data []byte,
-	inputFile, outputFile string,
-) (err error) {
-	bytecode, err := compileSrc(modules, data, inputFile)
-	if err != nil {
-		return
-	}
-
-	if outputFile == "" {
-		outputFile = basename(inputFile) + ".out"
-	}
-
-	out, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY, os.ModePerm)
-	if err != nil {
-		return
-	}
-	defer func() {
-		if err != nil {
-			_ = out.Close()
-		} else {
-			err = out.Close()
-		}
-	}()
-
-	err = bytecode.Encode(out)
-	if err != nil {
-		return
-	}
-	fmt.Println(outputFile)
-	return
-}
-
-func CompileAndRun(
-	modules *lokum.ModuleMap,
-	data []byte,
-	inputFile string,
-) (err error) {
-	bytecode, err := compileSrc(modules, data, inputFile)
-	if err != nil {
-		return
-	}
-
-	machine := lokum.NewVM(bytecode, nil, -1)
-	err = machine.Run()
-	return
-}
-
-func RunCompiled(modules *lokum.ModuleMap, data []byte) (err error) {
-	bytecode := &lokum.Bytecode{}
-	err = bytecode.Decode(bytes.NewReader(data), modules)
-	if err != nil {
-		return
-	}
-
-	machine := lokum.NewVM(bytecode, nil, -1)
-	err = machine.Run()
-	return
-}
-
-func RunREPL(modules *lokum.ModuleMap, in io.Reader, out io.Writer) {
-	stdin := bufio.NewScanner(in)
-	fileSet := parser.NewFileSet()
-	globals := make([]lokum.Object, lokum.GlobalsSize)
-	symbolTable := lokum.NewSymbolTable()
-	for idx, fn := range lokum.GetAllBuiltinFunctions() {
-		symbolTable.DefineBuiltin(idx, fn.Name)
-	}
-
-	symbol := symbolTable.Define("__repl_println__")
-	globals[symbol.Index] = &lokum.UserFunction{
-		Name: "println",
-		Value: func(args ...lokum.Object) (ret lokum.Object, err error) {
-			var printArgs []interface{}
-			for _, arg := range args {
-				if _, isUndefined := arg.(*lokum.Undefined); isUndefined {
-					printArgs = append(printArgs, "<undefined>")
-				} else {
-					s, _ := lokum.ToString(arg)
-					printArgs = append(printArgs, s)
-				}
-			}
-			printArgs = append(printArgs, "\n")
-			_, _ = fmt.Print(printArgs...)
-			return
-		},
-	}
-
-	var constants []lokum.Object
-	for {
-		_, _ = fmt.Fprint(out, replPrompt)
-		scanned := stdin.Scan()
-		if !scanned {
-			return
-		}
-
-		line := stdin.Text()
-		srcFile := fileSet.AddFile("repl", -1, len(line))
-		p := parser.NewParser(srcFile, []byte(line), nil)
-		file, err := p.ParseFile()
-		if err != nil {
-			_, _ = fmt.Fprintln(out, err.Error())
-			continue
-		}
-
-		file = addPrints(file)
-		c := lokum.NewCompiler(srcFile, symbolTable, constants, modules, nil)
-		if err := c.Compile(file); err != nil {
-			_, _ = fmt.Fprintln(out, err.Error())
-			continue
-		}
-
-		bytecode := c.Bytecode()
-		machine := lokum.NewVM(bytecode, globals, -1)
-		if err := machine.Run(); err != nil {
-			_, _ = fmt.Fprintln(out, err.Error())
-			continue
-		}
-		constants = bytecode.Constants
-	}
-}
-
-func compileSrc(
-	modules *lokum.ModuleMap,
-	src []byte,
-	inputFile string,
-) (*lokum.Bytecode, error) {
-	fileSet := parser.NewFileSet()
-	srcFile := fileSet.AddFile(filepath.Base(inputFile), -1, len(src))
-
-	p := parser.NewParser(srcFile, src, nil)
-	file, err := p.ParseFile()
-	if err != nil {
-		return nil, err
-	}
-
-	c := lokum.NewCompiler(srcFile, nil, nil, modules, nil)
-	c.EnableFileImport(true)
-	if resolvePath {
-		c.SetImportDir(filepath.Dir(inputFile))
-	}
-
-	if err := c.Compile(file); err != nil {
-		return nil, err
-	}
-
-	bytecode := c.Bytecode()
-	bytecode.RemoveDuplicates()
-	return bytecode, nil
-}
-
-func doHelp() {
-	fmt.Println("...")
-}
-
-func addPrints(file *parser.File) *parser.File {
-	var stmts []parser.Stmt
-	for _, s := range file.Stmts {
-		switch s := s.(type) {
-		case *parser.ExprStmt:
-			stmts = append(stmts, &parser.ExprStmt{
-				Expr: &parser.CallExpr{
-					Func: &parser.Ident{Name: "__repl_println__"},
-					Args: []parser.Expr{s.Expr},
-				},
-			})
-		case *parser.AssignStmt:
-			stmts = append(stmts, s)
-
-			stmts = append(stmts, &parser.ExprStmt{
-				Expr: &parser.CallExpr{
-					Func: &parser.Ident{
-						Name: "__repl_println__",
-					},
-					Args: s.LHS,
-				},
-			})
-		default:
-			stmts = append(stmts, s)
-		}
-	}
-	return &parser.File{
-		InputFile: file.InputFile,
-		Stmts:     stmts,
-	}
-}
-
-func basename(s string) string {
-	s = filepath.Base(s)
-	n := strings.LastIndexByte(s, '.')
-	if n > 0 {
-		return s[:n]
-	}
-	return s
-}
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/checker"
+	"github.com/onrirr/lokum/dap"
+	"github.com/onrirr/lokum/format"
+	"github.com/onrirr/lokum/lint"
+	"github.com/onrirr/lokum/lsp"
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/stdlib"
+)
+
+const (
+	sourceFileExt = ".lokum"
+	replPrompt    = ">> "
+	argsVar       = "argümanlar"
+	stdinFile     = "-"
+	stdinName     = "(stdin)"
+)
+
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+)
+
+var (
+	version            = lokum.Version
+	usageOut io.Writer = os.Stderr
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) int
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{
+			name:    "çalıştır",
+			usage:   "çalıştır [-resolve] <dosya|-> [argümanlar...]",
+			summary: "Kaynak ya da derlenmiş dosyayı çalıştırır",
+			run:     runCmd,
+		},
+		{
+			name:    "derle",
+			usage:   "derle [-o çıktı] [-kompakt] [-resolve] <dosya|->",
+			summary: "Kaynak dosyayı bytecode olarak derler",
+			run:     compileCmd,
+		},
+		{
+			name:    "ayrıştır",
+			usage:   "ayrıştır [-ağaç] [-resolve] <dosya|->...",
+			summary: "Kaynak ya da derlenmiş dosyanın bytecode dökümünü yazdırır",
+			run:     parseCmd,
+		},
+		{
+			name:    "biçimlendir",
+			usage:   "biçimlendir [-w] [-d] [-l] [dosya|dizin|-]...",
+			summary: "Kaynak dosyaları standart biçime sokar",
+			run:     formatCmd,
+		},
+		{
+			name:    "denetle",
+			usage:   "denetle [-json] [-kapat kurallar] [-kurallar] [dosya|dizin|-]...",
+			summary: "Kaynak dosyalarda sık yapılan hataları arar",
+			run:     lintCmd,
+		},
+		{
+			name:    "hata-ayıkla",
+			usage:   "hata-ayıkla [-resolve] [-dap] [-b [dosya:]satır]... <dosya> [argümanlar...]",
+			summary: "Programı kesme noktaları ve adım adım çalıştırma ile hata ayıklayıcıda çalıştırır",
+			run:     debugCmd,
+		},
+		{
+			name:    "profil",
+			usage:   "profil [-resolve] [-o dosya] [-n sayı] [-satır] [-aralık süre] <dosya> [argümanlar...]",
+			summary: "Programı profil çıkararak çalıştırır ve en çok zaman harcayan yerleri gösterir",
+			run:     profileCmd,
+		},
+		{
+			name:    "kapsam",
+			usage:   "kapsam [-resolve] [-lcov dosya] [-html dosya] <dosya> [argümanlar...]",
+			summary: "Programı çalıştırır ve hangi satırların çalıştığını raporlar",
+			run:     coverCmd,
+		},
+		{
+			name:    "test",
+			usage:   "test [-v] [-çalıştır düzenli-ifade] [-junit dosya] [dosya|dizin]...",
+			summary: "*_test.lokum dosyalarındaki test_ fonksiyonlarını çalıştırır",
+			run:     testCmd,
+		},
+		{
+			name:    "lsp",
+			usage:   "lsp",
+			summary: "Editörler için stdio üzerinden LSP sunucusu başlatır",
+			run:     lspCmd,
+		},
+		{
+			name:    "kontrol",
+			usage:   "kontrol <dosya>...",
+			summary: "Dosyaları tip denetiminden geçirir",
+			run:     checkCmd,
+		},
+		{
+			name:    "sürüm",
+			usage:   "sürüm",
+			summary: "Sürümü gösterir",
+			run:     versionCmd,
+		},
+		{
+			name:    "yardım",
+			usage:   "yardım [komut]",
+			summary: "Yardım gösterir",
+			run:     helpCmd,
+		},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+		RunREPL(modules, os.Stdin, os.Stdout)
+		return exitOK
+	}
+
+	switch args[0] {
+	case "-yardım", "-h", "-help", "--help":
+		return helpCmd(args[1:])
+	case "-sürüm":
+		return versionCmd(args[1:])
+	}
+	if cmd := findCommand(args[0]); cmd != nil {
+		return cmd.run(args[1:])
+	}
+	if strings.HasPrefix(args[0], "-") && args[0] != stdinFile {
+		_, _ = fmt.Fprintf(os.Stderr, "bilinmeyen seçenek: %s\n", args[0])
+		doHelp(os.Stderr)
+		return exitUsage
+	}
+	return runCmd(args)
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	cmd := findCommand(name)
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(usageOut)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "Kullanım: lokum %s\n\n%s.\n",
+			cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runCmd(args []string) int {
+	fs := newFlagSet("çalıştır")
+	resolvePath := fs.Bool("resolve", false, "Importları dosyanın dizininden çözümle")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	inputFile, data, err := readInput(fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	scriptArgs := fs.Args()[1:]
+	if lokum.IsBytecode(data) {
+		err = RunCompiled(modules, data, scriptArgs)
+	} else {
+		err = CompileAndRun(modules, data, inputFile, *resolvePath,
+			scriptArgs)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+	return exitOK
+}
+
+func compileCmd(args []string) int {
+	fs := newFlagSet("derle")
+	output := fs.String("o", "", "Çıkış dosyası")
+	compact := fs.Bool("kompakt", false, "Gob yerine kompakt kodlama kullan")
+	resolvePath := fs.Bool("resolve", false, "Importları dosyanın dizininden çözümle")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	inputFile, data, err := readInput(fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	encoding := lokum.EncodingGob
+	if *compact {
+		encoding = lokum.EncodingCompact
+	}
+	err = CompileOnly(modules, data, inputFile, *output, *resolvePath,
+		encoding)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+	return exitOK
+}
+
+func parseCmd(args []string) int {
+	fs := newFlagSet("ayrıştır")
+	printTree := fs.Bool("ağaç", false, "Bytecode yerine sözdizimi ağacını yazdır")
+	resolvePath := fs.Bool("resolve", false, "Importları dosyanın dizininden çözümle")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	code := exitOK
+	for _, arg := range fs.Args() {
+		inputFile, data, err := readInput(arg)
+		if err == nil {
+			if *printTree {
+				err = PrintTree(os.Stdout, data, inputFile)
+			} else {
+				err = Disassemble(os.Stdout, modules, data, inputFile,
+					*resolvePath)
+			}
+		}
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			code = exitError
+		}
+	}
+	return code
+}
+
+func PrintTree(out io.Writer, data []byte, inputFile string) error {
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile(filepath.Base(inputFile), -1, len(data))
+	p := parser.NewParser(srcFile, data, nil)
+	file, err := p.ParseFile()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range file.Stmts {
+		_, _ = fmt.Fprintf(out, "%s\t%s\n", fileSet.Position(stmt.Pos()), stmt)
+	}
+	return nil
+}
+
+func Disassemble(
+	out io.Writer,
+	modules *lokum.ModuleMap,
+	data []byte,
+	inputFile string,
+	resolvePath bool,
+) (err error) {
+	var bytecode *lokum.Bytecode
+	if lokum.IsBytecode(data) {
+		bytecode = &lokum.Bytecode{}
+		err = bytecode.Decode(bytes.NewReader(data), modules)
+		data = nil
+	} else {
+		bytecode, err = compileSrc(modules, data, inputFile, resolvePath)
+	}
+	if err != nil {
+		return
+	}
+
+	dir := filepath.Dir(inputFile)
+	return bytecode.Disassemble(out, func(name string) []byte {
+		if data != nil && name == filepath.Base(inputFile) {
+			return data
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		src, _ := ioutil.ReadFile(name)
+		return src
+	})
+}
+
+func formatCmd(args []string) int {
+	fs := newFlagSet("biçimlendir")
+	write := fs.Bool("w", false, "Sonucu çıktıya değil dosyaya yaz")
+	diff := fs.Bool("d", false, "Sonuç yerine farkları yazdır")
+	list := fs.Bool("l", false, "Biçimi farklı olan dosyaları listele")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+
+	files, err := sourceFiles(fs.Args())
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	code := exitOK
+	for _, name := range files {
+		changed, err := FormatFile(os.Stdout, name, *write, *diff, *list)
+		if err != nil {
+			if name == stdinFile {
+				name = stdinName
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %s\n", name, err.Error())
+			code = exitError
+		} else if changed && (*diff || *list) {
+			code = exitError
+		}
+	}
+	return code
+}
+
+func sourceFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return []string{stdinFile}, nil
+	}
+	var files []string
+	for _, path := range paths {
+		if path == stdinFile {
+			files = append(files, path)
+			continue
+		}
+		err := filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && (name == path || filepath.Ext(name) == sourceFileExt) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func FormatFile(
+	out io.Writer,
+	name string,
+	write, diff, list bool,
+) (changed bool, err error) {
+	var src []byte
+	if name == stdinFile {
+		src, err = ioutil.ReadAll(os.Stdin)
+		name = stdinName
+	} else {
+		src, err = ioutil.ReadFile(name)
+	}
+	if err != nil {
+		return
+	}
+
+	res, err := FormatSource(src)
+	if err != nil {
+		return
+	}
+	changed = !bytes.Equal(src, res)
+
+	if list && changed {
+		_, _ = fmt.Fprintln(out, name)
+	}
+	if diff && changed {
+		WriteDiff(out, name, src, res)
+	}
+	if write && changed && name != stdinName {
+		var info os.FileInfo
+		if info, err = os.Stat(name); err != nil {
+			return
+		}
+		err = ioutil.WriteFile(name, res, info.Mode().Perm())
+	}
+	if !write && !diff && !list {
+		_, err = out.Write(res)
+	}
+	return
+}
+
+func FormatSource(src []byte) ([]byte, error) {
+	var shebang []byte
+	if len(src) > 1 && string(src[:2]) == "#!" {
+		end := bytes.IndexByte(src, '\n')
+		if end < 0 {
+			return src, nil
+		}
+		shebang, src = src[:end+1:end+1], src[end+1:]
+	}
+	res, err := format.Source(src)
+	if err != nil {
+		return nil, err
+	}
+	return append(shebang, res...), nil
+}
+
+func lintCmd(args []string) int {
+	fs := newFlagSet("denetle")
+	asJSON := fs.Bool("json", false, "Sonuçları JSON olarak yazdır")
+	disable := fs.String("kapat", "", "Virgülle ayrılmış, kapatılacak kurallar")
+	listRules := fs.Bool("kurallar", false, "Kuralları listele")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if *listRules {
+		for _, r := range lint.Rules {
+			fmt.Printf("%s  %-24s %s\n", r.ID, r.Name, r.Doc)
+		}
+		return exitOK
+	}
+
+	cfg := &lint.Config{Disabled: make(map[string]bool)}
+	for _, id := range strings.Split(*disable, ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		r := lint.FindRule(id)
+		if r == nil {
+			_, _ = fmt.Fprintf(os.Stderr, "bilinmeyen kural: %s\n", id)
+			return exitUsage
+		}
+		cfg.Disabled[r.ID] = true
+	}
+
+	files, err := sourceFiles(fs.Args())
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	code := exitOK
+	issues := []*lint.Issue{}
+	for _, name := range files {
+		found, err := LintFile(name, cfg)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			code = exitError
+			continue
+		}
+		issues = append(issues, found...)
+	}
+	if len(issues) > 0 {
+		code = exitError
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(issues)
+		return code
+	}
+	for _, i := range issues {
+		fmt.Println(i)
+	}
+	return code
+}
+
+func LintFile(name string, cfg *lint.Config) ([]*lint.Issue, error) {
+	var (
+		src []byte
+		err error
+	)
+	if name == stdinFile {
+		src, err = ioutil.ReadAll(os.Stdin)
+		name = stdinName
+	} else {
+		src, err = ioutil.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(src) > 1 && string(src[:2]) == "#!" {
+		src = append([]byte("//"), src[2:]...)
+	}
+	return lint.Source(name, src, cfg)
+}
+
+func debugCmd(args []string) int {
+	fs := newFlagSet("hata-ayıkla")
+	resolvePath := fs.Bool("resolve", false, "Importları dosyanın dizininden çözümle")
+	dapMode := fs.Bool("dap", false, "Stdio üzerinden Debug Adapter Protocol sunucusu başlat")
+	var breakpoints breakpointFlags
+	fs.Var(&breakpoints, "b", "Kesme noktası ekle; birden çok kez verilebilir")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	if *dapMode {
+		launch := func(program string, args []string) (
+			*lokum.Bytecode, []lokum.Object, error,
+		) {
+			inputFile, data, err := readInput(program)
+			if err != nil {
+				return nil, nil, err
+			}
+			bytecode, err := loadBytecode(modules, data, inputFile, *resolvePath)
+			if err != nil {
+				return nil, nil, err
+			}
+			return bytecode, scriptGlobals(args), nil
+		}
+		if err := dap.NewServer(os.Stdin, os.Stdout, launch).Serve(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		return exitOK
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if fs.Arg(0) == stdinFile {
+		_, _ = fmt.Fprintln(os.Stderr,
+			"hata ayıklayıcı komutları standart girdiden okur, dosya verin")
+		return exitUsage
+	}
+
+	inputFile, data, err := readInput(fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	err = DebugFile(modules, data, inputFile, *resolvePath, breakpoints,
+		fs.Args()[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+	return exitOK
+}
+
+func profileCmd(args []string) int {
+	fs := newFlagSet("profil")
+	resolvePath := fs.Bool("resolve", false, "Importları dosyanın dizininden çözümle")
+	output := fs.String("o", "", "pprof biçimindeki profilin yazılacağı dosya")
+	top := fs.Int("n", 20, "Raporda gösterilecek satır sayısı; 0 hepsini gösterir")
+	byLine := fs.Bool("satır", false, "Fonksiyonlar yerine kaynak satırlarına göre raporla")
+	period := fs.Duration("aralık", lokum.DefaultProfilePeriod, "Örnekleme aralığı")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	inputFile, data, err := readInput(fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	profiler, runErr := ProfileFile(modules, data, inputFile, *resolvePath,
+		fs.Args()[1:], *period)
+	if profiler == nil {
+		_, _ = fmt.Fprintln(os.Stderr, runErr.Error())
+		return exitError
+	}
+	if *output != "" {
+		if err := writeProfile(profiler, *output); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			return exitError
+		}
+	}
+	if err := profiler.WriteReport(os.Stderr, *top, *byLine); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+	if runErr != nil {
+		_, _ = fmt.Fprintln(os.Stderr, runErr.Error())
+		return exitError
+	}
+	return exitOK
+}
+
+func coverCmd(args []string) int {
+	fs := newFlagSet("kapsam")
+	resolvePath := fs.Bool("resolve", false, "Importları dosyanın dizininden çözümle")
+	lcov := fs.String("lcov", "", "LCOV biçimindeki raporun yazılacağı dosya")
+	html := fs.String("html", "", "HTML raporun yazılacağı dosya")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	inputFile, data, err := readInput(fs.Arg(0))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	coverage, runErr := CoverFile(modules, data, inputFile, *resolvePath,
+		fs.Args()[1:])
+	if coverage == nil {
+		_, _ = fmt.Fprintln(os.Stderr, runErr.Error())
+		return exitError
+	}
+	files := coverageFiles(coverage, inputFile)
+	if err := writeCoverage(files, *lcov, *html); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+	printCoverage(os.Stderr, files)
+	if runErr != nil {
+		_, _ = fmt.Fprintln(os.Stderr, runErr.Error())
+		return exitError
+	}
+	return exitOK
+}
+
+func testCmd(args []string) int {
+	fs := newFlagSet("test")
+	verbose := fs.Bool("v", false, "Geçen ve atlanan testleri de göster")
+	pattern := fs.String("çalıştır", "", "Yalnızca adı düzenli ifadeye uyan testleri çalıştır")
+	junit := fs.String("junit", "", "JUnit XML raporunun yazılacağı dosya")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+
+	var filter *regexp.Regexp
+	if *pattern != "" {
+		re, err := regexp.Compile(*pattern)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "geçersiz düzenli ifade: %s\n", err)
+			return exitUsage
+		}
+		filter = re
+	}
+	files, err := testFiles(fs.Args())
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+	if len(files) == 0 {
+		_, _ = fmt.Fprintln(os.Stderr, "test dosyası bulunamadı")
+		return exitError
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	code := exitOK
+	var suites []*TestSuite
+	for _, file := range files {
+		suite := RunTestFile(modules, file, filter, *verbose, os.Stdout)
+		printTestSuite(os.Stdout, suite)
+		if suite.Failed() {
+			code = exitError
+		}
+		suites = append(suites, suite)
+	}
+	if *junit != "" {
+		if err := writeJUnit(suites, *junit); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			return exitError
+		}
+	}
+	return code
+}
+
+func lspCmd(args []string) int {
+	fs := newFlagSet("lsp")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if err := lsp.NewServer(os.Stdin, os.Stdout).Serve(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+func checkCmd(args []string) int {
+	fs := newFlagSet("kontrol")
+	if err := fs.Parse(args); err != nil {
+		return parseError(err)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if !CheckFiles(fs.Args(), os.Stderr) {
+		return exitError
+	}
+	return exitOK
+}
+
+func versionCmd(args []string) int {
+	fmt.Println(version)
+	return exitOK
+}
+
+func helpCmd(args []string) int {
+	if len(args) == 0 {
+		doHelp(os.Stdout)
+		return exitOK
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		_, _ = fmt.Fprintf(os.Stderr, "bilinmeyen komut: %s\n", args[0])
+		return exitUsage
+	}
+	usageOut = os.Stdout
+	return cmd.run([]string{"-h"})
+}
+
+func parseError(err error) int {
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+	return exitUsage
+}
+
+func readInput(name string) (string, []byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if name == stdinFile {
+		data, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", nil, err
+		}
+		name = stdinName
+	} else {
+		data, err = ioutil.ReadFile(name)
+		if err != nil {
+			return "", nil, fmt.Errorf("Dosyayı bulamadım: %s", err)
+		}
+		name, err = filepath.Abs(name)
+		if err != nil {
+			return "", nil, fmt.Errorf("Dosyayı bulamadım: %s", err)
+		}
+	}
+
+	if len(data) > 1 && string(data[:2]) == "#!" {
+		copy(data, "//")
+	}
+	return name, data, nil
+}
+
+func CompileOnly(
+	modules *lokum.ModuleMap,
+	data []byte,
+	inputFile, outputFile string,
+	resolvePath bool,
+	encoding lokum.BytecodeEncoding,
+) (err error) {
+	bytecode, err := compileSrc(modules, data, inputFile, resolvePath)
+	if err != nil {
+		return
+	}
+
+	if outputFile == "" {
+		outputFile = basename(inputFile) + ".out"
+	}
+
+	out, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_ = out.Close()
+		} else {
+			err = out.Close()
+		}
+	}()
+
+	err = bytecode.EncodeWith(out, encoding)
+	if err != nil {
+		return
+	}
+	fmt.Println(outputFile)
+	return
+}
+
+func CompileAndRun(
+	modules *lokum.ModuleMap,
+	data []byte,
+	inputFile string,
+	resolvePath bool,
+	args []string,
+) (err error) {
+	bytecode, err := compileSrc(modules, data, inputFile, resolvePath)
+	if err != nil {
+		return
+	}
+
+	machine := lokum.NewVM(bytecode, scriptGlobals(args), -1)
+	err = machine.Run()
+	return
+}
+
+func RunCompiled(
+	modules *lokum.ModuleMap,
+	data []byte,
+	args []string,
+) (err error) {
+	bytecode := &lokum.Bytecode{}
+	err = bytecode.Decode(bytes.NewReader(data), modules)
+	if err != nil {
+		return
+	}
+
+	machine := lokum.NewVM(bytecode, scriptGlobals(args), -1)
+	err = machine.Run()
+	return
+}
+
+func scriptGlobals(args []string) []lokum.Object {
+	arr := &lokum.ImmutableArray{Value: make([]lokum.Object, len(args))}
+	for i, arg := range args {
+		arr.Value[i] = &lokum.String{Value: arg}
+	}
+	globals := make([]lokum.Object, lokum.GlobalsSize)
+	globals[0] = arr
+	return globals
+}
+
+func CheckFiles(files []string, out io.Writer) bool {
+	ok := true
+	for _, inputFile := range files {
+		src, err := ioutil.ReadFile(inputFile)
+		if err != nil {
+			_, _ = fmt.Fprintf(out, "Dosyayı bulamadım: %s\n", err.Error())
+			ok = false
+			continue
+		}
+
+		fileSet := parser.NewFileSet()
+		srcFile := fileSet.AddFile(filepath.Base(inputFile), -1, len(src))
+		p := parser.NewParser(srcFile, src, nil)
+		file, err := p.ParseFile()
+		if err != nil {
+			_, _ = fmt.Fprintln(out, err.Error())
+			ok = false
+			continue
+		}
+
+		for _, e := range checker.Check(file) {
+			_, _ = fmt.Fprintln(out, e.Error())
+			ok = false
+		}
+	}
+	return ok
+}
+
+func RunREPL(modules *lokum.ModuleMap, in io.Reader, out io.Writer) {
+	stdin := bufio.NewScanner(in)
+	fileSet := parser.NewFileSet()
+	globals := make([]lokum.Object, lokum.GlobalsSize)
+	symbolTable := lokum.NewSymbolTable()
+	for idx, fn := range lokum.GetAllBuiltinFunctions() {
+		symbolTable.DefineBuiltin(idx, fn.Name)
+	}
+
+	symbol := symbolTable.Define("__repl_println__")
+	globals[symbol.Index] = &lokum.UserFunction{
+		Name: "println",
+		Value: func(args ...lokum.Object) (ret lokum.Object, err error) {
+			var printArgs []interface{}
+			for _, arg := range args {
+				if _, isUndefined := arg.(*lokum.Undefined); isUndefined {
+					printArgs = append(printArgs, "<undefined>")
+				} else {
+					s, _ := lokum.ToString(arg)
+					printArgs = append(printArgs, s)
+				}
+			}
+			printArgs = append(printArgs, "\n")
+			_, _ = fmt.Print(printArgs...)
+			return
+		},
+	}
+
+	var constants []lokum.Object
+	for {
+		_, _ = fmt.Fprint(out, replPrompt)
+		scanned := stdin.Scan()
+		if !scanned {
+			return
+		}
+
+		line := stdin.Text()
+		srcFile := fileSet.AddFile("repl", -1, len(line))
+		p := parser.NewParser(srcFile, []byte(line), nil)
+		file, err := p.ParseFile()
+		if err != nil {
+			_, _ = fmt.Fprintln(out, err.Error())
+			continue
+		}
+
+		file = addPrints(file)
+		c := lokum.NewCompiler(srcFile, symbolTable, constants, modules, nil)
+		if err := c.Compile(file); err != nil {
+			_, _ = fmt.Fprintln(out, err.Error())
+			continue
+		}
+
+		bytecode := c.Bytecode()
+		machine := lokum.NewVM(bytecode, globals, -1)
+		if err := machine.Run(); err != nil {
+			_, _ = fmt.Fprintln(out, err.Error())
+			continue
+		}
+		constants = bytecode.Constants
+	}
+}
+
+func compileSrc(
+	modules *lokum.ModuleMap,
+	src []byte,
+	inputFile string,
+	resolvePath bool,
+) (*lokum.Bytecode, error) {
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile(filepath.Base(inputFile), -1, len(src))
+
+	p := parser.NewParser(srcFile, src, nil)
+	file, err := p.ParseFile()
+	if err != nil {
+		return nil, err
+	}
+
+	symbolTable := lokum.NewSymbolTable()
+	symbolTable.Define(argsVar)
+	c := lokum.NewCompiler(srcFile, symbolTable, nil, modules, nil)
+	c.EnableFileImport(true)
+	if resolvePath && inputFile != stdinName {
+		c.SetImportDir(filepath.Dir(inputFile))
+	}
+
+	if err := c.Compile(file); err != nil {
+		return nil, err
+	}
+
+	bytecode := c.Bytecode()
+	bytecode.RemoveDuplicates()
+	return bytecode, nil
+}
+
+func doHelp(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Lokum %s\n\nKullanım:\n\n", version)
+	_, _ = fmt.Fprintln(w, "\tlokum                  REPL başlatır")
+	_, _ = fmt.Fprintln(w, "\tlokum <dosya> [arg...] lokum çalıştır ile aynı")
+	_, _ = fmt.Fprintln(w, "\tlokum <komut> [seçenekler]")
+	_, _ = fmt.Fprint(w, "\nKomutlar:\n\n")
+	width := 0
+	for _, cmd := range commands {
+		if n := utf8.RuneCountInString(cmd.name); n > width {
+			width = n
+		}
+	}
+	for _, cmd := range commands {
+		pad := strings.Repeat(" ", width-utf8.RuneCountInString(cmd.name))
+		_, _ = fmt.Fprintf(w, "\t%s%s  %s\n", cmd.name, pad, cmd.summary)
+	}
+	_, _ = fmt.Fprint(w, "\nKomut hakkında ayrıntı için: lokum yardım <komut>\n")
+	_, _ = fmt.Fprintf(w, "Betik argümanları %s değişkeninden okunur.\n", argsVar)
+	_, _ = fmt.Fprintln(w, "Çıkış kodları: 0 başarılı, 1 hata, 2 hatalı kullanım.")
+}
+
+func addPrints(file *parser.File) *parser.File {
+	var stmts []parser.Stmt
+	for _, s := range file.Stmts {
+		switch s := s.(type) {
+		case *parser.ExprStmt:
+			stmts = append(stmts, &parser.ExprStmt{
+				Expr: &parser.CallExpr{
+					Func: &parser.Ident{Name: "__repl_println__"},
+					Args: []parser.Expr{s.Expr},
+				},
+			})
+		case *parser.AssignStmt:
+			stmts = append(stmts, s)
+
+			stmts = append(stmts, &parser.ExprStmt{
+				Expr: &parser.CallExpr{
+					Func: &parser.Ident{
+						Name: "__repl_println__",
+					},
+					Args: s.LHS,
+				},
+			})
+		default:
+			stmts = append(stmts, s)
+		}
+	}
+	return &parser.File{
+		InputFile: file.InputFile,
+		Stmts:     stmts,
+	}
+}
+
+func basename(s string) string {
+	s = filepath.Base(s)
+	n := strings.LastIndexByte(s, '.')
+	if n > 0 {
+		return s[:n]
+	}
+	return s
+}
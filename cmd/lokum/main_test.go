@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFormatFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lokum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "a.lokum")
+	src := "x:=1\nyaz( x )\n"
+	want := "x := 1\nyaz(x)\n"
+	if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	changed, err := FormatFile(&out, name, false, false, false)
+	if err != nil || !changed || out.String() != want {
+		t.Fatalf("çıktı %q (%v, %v), beklenen %q", out.String(), changed, err, want)
+	}
+
+	out.Reset()
+	changed, err = FormatFile(&out, name, false, true, false)
+	if err != nil || !changed {
+		t.Fatalf("-d: %v, %v", changed, err)
+	}
+	for _, line := range []string{"--- " + name, "+++ " + name,
+		"-x:=1", "-yaz( x )", "+x := 1", "+yaz(x)"} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("-d çıktısında %q yok:\n%s", line, out.String())
+		}
+	}
+
+	out.Reset()
+	changed, err = FormatFile(&out, name, false, false, true)
+	if err != nil || !changed || out.String() != name+"\n" {
+		t.Fatalf("-l: %q (%v, %v)", out.String(), changed, err)
+	}
+
+	out.Reset()
+	changed, err = FormatFile(&out, name, true, false, false)
+	if err != nil || !changed || out.Len() != 0 {
+		t.Fatalf("-w: %q (%v, %v)", out.String(), changed, err)
+	}
+	res, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != want {
+		t.Fatalf("-w dosyası %q, beklenen %q", res, want)
+	}
+
+	out.Reset()
+	changed, err = FormatFile(&out, name, false, true, false)
+	if err != nil || changed || out.Len() != 0 {
+		t.Fatalf("biçimli dosyada -d: %q (%v, %v)", out.String(), changed, err)
+	}
+}
+
+func TestHelpAlignment(t *testing.T) {
+	var out bytes.Buffer
+	doHelp(&out)
+	column := -1
+	for _, cmd := range commands {
+		prefix := "\t" + cmd.name + " "
+		var line string
+		for _, l := range strings.Split(out.String(), "\n") {
+			if strings.HasPrefix(l, prefix) {
+				line = l
+			}
+		}
+		i := strings.Index(line, cmd.summary)
+		if i < 0 {
+			t.Fatalf("yardımda %s yok:\n%s", cmd.name, out.String())
+		}
+		col := utf8.RuneCountInString(line[:i])
+		if column >= 0 && col != column {
+			t.Fatalf("%s açıklaması %d. sütunda, beklenen %d", cmd.name, col, column)
+		}
+		column = col
+	}
+}
+
+// captureOutput, fn çalışırken os.Stdout ve os.Stderr'e yazılanları toplar.
+func captureOutput(t *testing.T, fn func()) string {
+	f, err := ioutil.TempFile("", "lokum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	stdout, stderr, usage := os.Stdout, os.Stderr, usageOut
+	os.Stdout, os.Stderr, usageOut = f, f, f
+	defer func() { os.Stdout, os.Stderr, usageOut = stdout, stderr, usage }()
+	fn()
+
+	data, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRunDispatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lokum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	script := filepath.Join(dir, "a.lokum")
+	src := "io := kullan(\"io\")\nio.yazdır(\"merhaba \", argümanlar[0])\n"
+	if err := ioutil.WriteFile(script, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		code int
+		out  string
+	}{
+		{[]string{"çalıştır", script, "dünya"}, exitOK, "merhaba dünya\n"},
+		{[]string{script, "dünya"}, exitOK, "merhaba dünya\n"},
+		{[]string{"çalıştır", filepath.Join(dir, "yok.lokum")}, exitError, "Dosyayı bulamadım"},
+		{[]string{"çalıştır"}, exitUsage, "Kullanım: lokum çalıştır"},
+		{[]string{"derle", "-bilinmez", script}, exitUsage, "-bilinmez"},
+		{[]string{"sürüm"}, exitOK, version + "\n"},
+		{[]string{"-sürüm"}, exitOK, version + "\n"},
+		{[]string{"yardım"}, exitOK, "Komutlar:"},
+		{[]string{"-h"}, exitOK, "Komutlar:"},
+		{[]string{"yardım", "derle"}, exitOK, "Kullanım: lokum derle"},
+		{[]string{"yardım", "yok"}, exitUsage, "bilinmeyen komut: yok"},
+		{[]string{"-bilinmez"}, exitUsage, "bilinmeyen seçenek: -bilinmez"},
+	}
+	for _, tt := range tests {
+		var code int
+		out := captureOutput(t, func() { code = run(tt.args) })
+		if code != tt.code || !strings.Contains(out, tt.out) {
+			t.Fatalf("%q: çıkış %d, çıktı %q; beklenen %d, %q",
+				tt.args, code, out, tt.code, tt.out)
+		}
+	}
+}
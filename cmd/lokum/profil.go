@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/onrirr/lokum"
+)
+
+func ProfileFile(
+	modules *lokum.ModuleMap,
+	data []byte,
+	inputFile string,
+	resolvePath bool,
+	args []string,
+	period time.Duration,
+) (*lokum.Profiler, error) {
+	bytecode, err := loadBytecode(modules, data, inputFile, resolvePath)
+	if err != nil {
+		return nil, err
+	}
+
+	profiler := lokum.NewProfiler(period)
+	machine := lokum.NewVM(bytecode, scriptGlobals(args), -1)
+	machine.SetProfiler(profiler)
+	profiler.Start()
+	err = machine.Run()
+	profiler.Stop()
+	return profiler, err
+}
+
+func writeProfile(profiler *lokum.Profiler, name string) error {
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := profiler.WritePprof(out); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
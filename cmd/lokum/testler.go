@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/stdlib"
+)
+
+const (
+	testFileSuffix = "_test" + sourceFileExt
+	testFuncPrefix = "test_"
+)
+
+type TestStatus int
+
+const (
+	TestPassed TestStatus = iota
+	TestFailed
+	TestSkipped
+	TestErrored
+)
+
+type TestResult struct {
+	Name     string
+	Status   TestStatus
+	Message  string
+	Failure  *stdlib.TestFailure
+	Trace    []string
+	Output   string
+	Duration time.Duration
+}
+
+type TestSuite struct {
+	File     string
+	Err      error
+	Results  []*TestResult
+	Duration time.Duration
+}
+
+func (s *TestSuite) Failed() bool {
+	if s.Err != nil {
+		return true
+	}
+	for _, r := range s.Results {
+		if r.Status == TestFailed || r.Status == TestErrored {
+			return true
+		}
+	}
+	return false
+}
+
+func testFiles(paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	var files []string
+	for _, path := range paths {
+		err := filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && (name == path || strings.HasSuffix(name, testFileSuffix)) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func RunTestFile(
+	modules *lokum.ModuleMap,
+	inputFile string,
+	filter *regexp.Regexp,
+	verbose bool,
+	out io.Writer,
+) *TestSuite {
+	suite := &TestSuite{File: inputFile}
+	start := time.Now()
+	defer func() { suite.Duration = time.Since(start) }()
+
+	src, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		suite.Err = err
+		return suite
+	}
+	bytecode, tests, err := compileTests(modules, src, inputFile)
+	if err != nil {
+		suite.Err = err
+		return suite
+	}
+
+	for _, test := range tests {
+		if filter != nil && !filter.MatchString(test.Name) {
+			continue
+		}
+		res, err := runTest(bytecode, test)
+		if err != nil {
+			suite.Err = err
+			return suite
+		}
+		if res == nil {
+			continue
+		}
+		if verbose {
+			_, _ = fmt.Fprintf(out, "=== ÇALIŞ   %s\n", test.Name)
+		}
+		suite.Results = append(suite.Results, res)
+		printTestResult(out, res, verbose)
+	}
+	return suite
+}
+
+func compileTests(
+	modules *lokum.ModuleMap,
+	src []byte,
+	inputFile string,
+) (*lokum.Bytecode, []*lokum.Symbol, error) {
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile(filepath.Base(inputFile), -1, len(src))
+
+	p := parser.NewParser(srcFile, src, nil)
+	file, err := p.ParseFile()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	symbolTable := lokum.NewSymbolTable()
+	symbolTable.Define(argsVar)
+	c := lokum.NewCompiler(srcFile, symbolTable, nil, modules, nil)
+	c.EnableFileImport(true)
+	c.SetImportDir(filepath.Dir(inputFile))
+	if err := c.Compile(file); err != nil {
+		return nil, nil, err
+	}
+
+	var tests []*lokum.Symbol
+	for _, name := range symbolTable.Names() {
+		if !strings.HasPrefix(name, testFuncPrefix) {
+			continue
+		}
+		sym, _, ok := symbolTable.Resolve(name, false)
+		if ok && sym.Scope == lokum.ScopeGlobal {
+			tests = append(tests, sym)
+		}
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Index < tests[j].Index
+	})
+
+	bytecode := c.Bytecode()
+	bytecode.RemoveDuplicates()
+	return bytecode, tests, nil
+}
+
+func runTest(bytecode *lokum.Bytecode, test *lokum.Symbol) (*TestResult, error) {
+	var output bytes.Buffer
+	globals := scriptGlobals(nil)
+	machine := lokum.NewVM(bytecode, globals, -1)
+	machine.SetStdout(&output)
+	machine.SetStderr(&output)
+	if err := machine.Run(); err != nil {
+		return nil, err
+	}
+	fn, ok := globals[test.Index].(*lokum.CompiledFunction)
+	if !ok {
+		return nil, nil
+	}
+
+	res := &TestResult{Name: test.Name}
+	start := time.Now()
+	err := callTest(machine, fn)
+	res.Duration = time.Since(start)
+	res.Output = output.String()
+
+	var msg string
+	if err != nil {
+		msg = err.Error()
+		if i := strings.LastIndex(msg, "\n\tat "); i >= 0 {
+			msg = msg[:i]
+		}
+	}
+	var failure *stdlib.TestFailure
+	var skip *stdlib.TestSkip
+	switch {
+	case err == nil:
+		res.Status = TestPassed
+	case errors.As(err, &skip):
+		res.Status = TestSkipped
+		res.Message = skip.Reason
+	case errors.As(err, &failure):
+		res.Status = TestFailed
+		res.Failure = failure
+		res.Message = failure.Error()
+		res.Trace = traceLines(msg)
+	default:
+		res.Status = TestErrored
+		res.Message = msg
+	}
+	return res, nil
+}
+
+func callTest(machine *lokum.VM, fn lokum.Object) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panik: %v", r)
+		}
+	}()
+	_, err = machine.Call(fn)
+	return err
+}
+
+func traceLines(msg string) []string {
+	var lines []string
+	for _, line := range strings.Split(msg, "\n") {
+		if !strings.HasPrefix(line, "\tat ") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "\tat ")
+		if n := len(lines); n == 0 || lines[n-1] != line {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func printTestResult(out io.Writer, res *TestResult, verbose bool) {
+	elapsed := fmt.Sprintf("(%.2fs)", res.Duration.Seconds())
+	switch res.Status {
+	case TestPassed:
+		if verbose {
+			_, _ = fmt.Fprintf(out, "--- GEÇTİ: %s %s\n", res.Name, elapsed)
+		}
+	case TestSkipped:
+		if verbose {
+			_, _ = fmt.Fprintf(out, "--- ATLANDI: %s %s\n", res.Name, elapsed)
+			if res.Message != "" {
+				_, _ = fmt.Fprintf(out, "    %s\n", res.Message)
+			}
+		}
+	default:
+		_, _ = fmt.Fprintf(out, "--- BAŞARISIZ: %s %s\n", res.Name, elapsed)
+		_, _ = fmt.Fprint(out, indentLines(failureText(res), "    "))
+	}
+	if res.Output != "" && (verbose || res.Status == TestFailed ||
+		res.Status == TestErrored) {
+		_, _ = fmt.Fprint(out, indentLines(res.Output, "    "))
+	}
+}
+
+func failureText(res *TestResult) string {
+	if res.Failure == nil {
+		return res.Message + "\n"
+	}
+	var sb strings.Builder
+	if len(res.Trace) > 0 {
+		_, _ = fmt.Fprintf(&sb, "%s:\n", res.Trace[0])
+	}
+	f := res.Failure
+	if f.Case != "" {
+		_, _ = fmt.Fprintf(&sb, "vaka: %s\n", f.Case)
+	}
+	_, _ = fmt.Fprintf(&sb, "%s\n", f.Message)
+	if f.Compared {
+		if strings.Contains(f.Expected+f.Actual, "\n") {
+			WriteDiff(&sb, "beklenen/gerçek",
+				[]byte(f.Expected+"\n"), []byte(f.Actual+"\n"))
+		} else {
+			_, _ = fmt.Fprintf(&sb, "beklenen: %s\ngerçek:   %s\n",
+				f.Expected, f.Actual)
+		}
+	}
+	for i := 1; i < len(res.Trace); i++ {
+		_, _ = fmt.Fprintf(&sb, "\tat %s\n", res.Trace[i])
+	}
+	return sb.String()
+}
+
+func indentLines(s, indent string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	return indent + strings.Join(lines, "\n"+indent) + "\n"
+}
+
+func printTestSuite(out io.Writer, suite *TestSuite) {
+	elapsed := fmt.Sprintf("%.3fs", suite.Duration.Seconds())
+	switch {
+	case suite.Err != nil:
+		_, _ = fmt.Fprint(out, indentLines(suite.Err.Error(), "    "))
+		_, _ = fmt.Fprintf(out, "BAŞARISIZ\t%s\t%s\n", suite.File, elapsed)
+	case suite.Failed():
+		_, _ = fmt.Fprintf(out, "BAŞARISIZ\t%s\t%s\n", suite.File, elapsed)
+	case len(suite.Results) == 0:
+		_, _ = fmt.Fprintf(out, "ok\t%s\t%s [test yok]\n", suite.File, elapsed)
+	default:
+		_, _ = fmt.Fprintf(out, "ok\t%s\t%s\n", suite.File, elapsed)
+	}
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitProblem `xml:"skipped,omitempty"`
+	Output    string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+func WriteJUnit(w io.Writer, suites []*TestSuite) error {
+	var total time.Duration
+	doc := &junitSuites{}
+	for _, suite := range suites {
+		js := junitSuite{
+			Name: suite.File,
+			Time: seconds(suite.Duration),
+		}
+		if suite.Err != nil {
+			js.Errors++
+			js.Cases = append(js.Cases, junitCase{
+				Name:      "(yükleme)",
+				ClassName: suite.File,
+				Time:      seconds(0),
+				Error:     &junitProblem{Message: suite.Err.Error()},
+			})
+		}
+		for _, res := range suite.Results {
+			jc := junitCase{
+				Name:      res.Name,
+				ClassName: suite.File,
+				Time:      seconds(res.Duration),
+				Output:    res.Output,
+			}
+			switch res.Status {
+			case TestFailed:
+				js.Failures++
+				jc.Failure = &junitProblem{
+					Message: res.Message,
+					Text:    failureText(res),
+				}
+			case TestErrored:
+				js.Errors++
+				jc.Error = &junitProblem{Message: res.Message}
+			case TestSkipped:
+				js.Skipped++
+				jc.Skipped = &junitProblem{Message: res.Message}
+			}
+			js.Cases = append(js.Cases, jc)
+		}
+		js.Tests = len(js.Cases)
+		doc.Tests += js.Tests
+		doc.Failures += js.Failures
+		doc.Errors += js.Errors
+		doc.Skipped += js.Skipped
+		total += suite.Duration
+		doc.Suites = append(doc.Suites, js)
+	}
+	doc.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func writeJUnit(suites []*TestSuite, name string) error {
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := WriteJUnit(out, suites); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
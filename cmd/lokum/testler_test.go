@@ -0,0 +1,99 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onrirr/lokum/stdlib"
+)
+
+func TestRunTestFileRecoversPanic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lokum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "panik_test.lokum")
+	src := `test := kullan("test")
+test_bekle := fn() { test.hata_bekle(fn() { x := 1/0 }, "divide by zero") }
+test_panik := fn() { x := 0; y := 1/x }
+test_sonra := fn() { test.doğru_mu(1 == 1) }
+`
+	if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	suite := RunTestFile(modules, file, nil, false, ioutil.Discard)
+	if suite.Err != nil {
+		t.Fatal(suite.Err)
+	}
+	want := []TestStatus{TestPassed, TestErrored, TestPassed}
+	if len(suite.Results) != len(want) {
+		t.Fatalf("%d sonuç, beklenen %d", len(suite.Results), len(want))
+	}
+	for i, res := range suite.Results {
+		if res.Status != want[i] {
+			t.Fatalf("%s: durum %d, beklenen %d (%s)", res.Name, res.Status, want[i], res.Message)
+		}
+	}
+	if msg := suite.Results[1].Message; !strings.Contains(msg, "divide by zero") {
+		t.Fatalf("hata mesajı %q", msg)
+	}
+}
+
+func TestRunTestFileTable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lokum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "tablo_test.lokum")
+	src := `test := kullan("test")
+test_eşit := fn() {
+	test.tablo([{ad: "bir", x: 1}, {ad: "iki", x: 2}], fn(v) {
+		test.eşit(1, v.x)
+	})
+}
+test_hata := fn() {
+	test.tablo({a: 1, b: 0}, fn(x) {
+		eğer x == 0 { y := x() }
+	})
+}
+`
+	if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modules := stdlib.GetModuleMap(stdlib.AllModuleNames()...)
+	suite := RunTestFile(modules, file, nil, false, ioutil.Discard)
+	if suite.Err != nil {
+		t.Fatal(suite.Err)
+	}
+	if len(suite.Results) != 2 {
+		t.Fatalf("%d sonuç, beklenen 2", len(suite.Results))
+	}
+
+	failed := suite.Results[0]
+	if failed.Status != TestFailed || failed.Failure.Case != "iki" {
+		t.Fatalf("%s: durum %d, vaka %+v", failed.Name, failed.Status, failed.Failure)
+	}
+	if want := "vaka iki: değerler eşit değil: beklenen 1, gerçek 2"; failed.Message != want {
+		t.Fatalf("mesaj %q, beklenen %q", failed.Message, want)
+	}
+	if want := []string{"tablo_test.lokum:4:3", "tablo_test.lokum:3:2"}; !reflect.DeepEqual(failed.Trace, want) {
+		t.Fatalf("iz %q, beklenen %q", failed.Trace, want)
+	}
+
+	errored := suite.Results[1]
+	want := "Çalışma Hatası: vaka b: çağrılamaz: int\n\tat tablo_test.lokum:9:23\n\tat tablo_test.lokum:8:2"
+	if errored.Status != TestErrored || errored.Message != want {
+		t.Fatalf("%s: durum %d, mesaj %q", errored.Name, errored.Status, errored.Message)
+	}
+}
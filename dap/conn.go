@@ -0,0 +1,118 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Message struct {
+	Seq        int             `json:"seq"`
+	Type       string          `json:"type"`
+	Command    string          `json:"command,omitempty"`
+	Event      string          `json:"event,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	RequestSeq int             `json:"request_seq,omitempty"`
+	Success    *bool           `json:"success,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+}
+
+type Conn struct {
+	r   *textproto.Reader
+	w   io.Writer
+	mu  sync.Mutex
+	seq int
+}
+
+func NewConn(r io.Reader, w io.Writer) *Conn {
+	return &Conn{r: textproto.NewReader(bufio.NewReader(r)), w: w}
+}
+
+func (c *Conn) Read() (*Message, error) {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("geçersiz Content-Length: %q",
+			header.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		return nil, err
+	}
+	msg := &Message{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (c *Conn) Write(msg *Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	msg.Seq = c.seq
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+func (c *Conn) Request(command string, args interface{}) error {
+	msg := &Message{Type: "request", Command: command}
+	if err := setJSON(&msg.Arguments, args); err != nil {
+		return err
+	}
+	return c.Write(msg)
+}
+
+func (c *Conn) Event(event string, body interface{}) error {
+	msg := &Message{Type: "event", Event: event}
+	if err := setJSON(&msg.Body, body); err != nil {
+		return err
+	}
+	return c.Write(msg)
+}
+
+func (c *Conn) respond(req *Message, body interface{}, err error) error {
+	success := err == nil
+	msg := &Message{
+		Type:       "response",
+		Command:    req.Command,
+		RequestSeq: req.Seq,
+		Success:    &success,
+	}
+	if err != nil {
+		msg.Message = err.Error()
+		return c.Write(msg)
+	}
+	if err := setJSON(&msg.Body, body); err != nil {
+		return err
+	}
+	return c.Write(msg)
+}
+
+func setJSON(dst *json.RawMessage, v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	*dst = data
+	return nil
+}
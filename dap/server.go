@@ -0,0 +1,458 @@
+package dap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/onrirr/lokum"
+)
+
+const threadID = 1
+
+var errNotPaused = errors.New("program duraklatılmadı")
+
+type Launcher func(program string, args []string) (
+	*lokum.Bytecode, []lokum.Object, error)
+
+type Server struct {
+	conn     *Conn
+	launch   Launcher
+	lock     sync.Mutex
+	program  string
+	debugger *lokum.Debugger
+	vm       *lokum.VM
+	pending  map[string][]int
+	launched bool
+	ready    bool
+	state    *lokum.DebugState
+	resume   chan lokum.DebugAction
+	refs     [][]value
+	done     chan struct{}
+}
+
+type value struct {
+	name   string
+	object lokum.Object
+}
+
+func NewServer(in io.Reader, out io.Writer, launch Launcher) *Server {
+	return &Server{
+		conn:    NewConn(in, out),
+		launch:  launch,
+		pending: make(map[string][]int),
+		resume:  make(chan lokum.DebugAction, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+func (s *Server) Serve() error {
+	for {
+		msg, err := s.conn.Read()
+		if err == io.EOF {
+			s.terminate()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Type != "request" {
+			continue
+		}
+		body, err := s.dispatch(msg)
+		if err := s.conn.respond(msg, body, err); err != nil {
+			return err
+		}
+		switch msg.Command {
+		case "initialize":
+			if err := s.conn.Event("initialized", nil); err != nil {
+				return err
+			}
+		case "launch", "configurationDone":
+			s.start()
+		case "disconnect":
+			return nil
+		}
+	}
+}
+
+func (s *Server) dispatch(msg *Message) (interface{}, error) {
+	switch msg.Command {
+	case "initialize":
+		return &Capabilities{
+			SupportsConfigurationDoneRequest: true,
+			SupportsEvaluateForHovers:        true,
+			SupportsTerminateRequest:         true,
+		}, nil
+	case "launch":
+		var args LaunchArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return nil, s.load(args)
+	case "configurationDone":
+		s.lock.Lock()
+		s.ready = true
+		s.lock.Unlock()
+		return nil, nil
+	case "setBreakpoints":
+		var args SetBreakpointsArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.setBreakpoints(args), nil
+	case "threads":
+		return &ThreadsResponse{
+			Threads: []Thread{{ID: threadID, Name: "ana"}},
+		}, nil
+	case "stackTrace":
+		return s.stackTrace()
+	case "scopes":
+		var args ScopesArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.scopes(args.FrameID)
+	case "variables":
+		var args VariablesArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.variables(args.VariablesReference)
+	case "evaluate":
+		var args EvaluateArguments
+		if err := decode(msg.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return s.evaluate(args)
+	case "continue":
+		return &ContinueResponse{AllThreadsContinued: true},
+			s.continueWith(lokum.DebugContinue)
+	case "next":
+		return nil, s.continueWith(lokum.DebugStepOver)
+	case "stepIn":
+		return nil, s.continueWith(lokum.DebugStepIn)
+	case "stepOut":
+		return nil, s.continueWith(lokum.DebugStepOut)
+	case "pause":
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if s.debugger != nil {
+			s.debugger.Pause()
+		}
+		return nil, nil
+	case "terminate", "disconnect":
+		s.terminate()
+		return nil, nil
+	}
+	return nil, fmt.Errorf("desteklenmeyen istek: %s", msg.Command)
+}
+
+func decode(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func (s *Server) load(args LaunchArguments) error {
+	if args.Program == "" {
+		return errors.New("program belirtilmedi")
+	}
+	bytecode, globals, err := s.launch(args.Program, args.Args)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.program = args.Program
+	s.debugger = lokum.NewDebugger(bytecode, s.stopped)
+	s.debugger.SetStopOnEntry(args.StopOnEntry)
+	for file, lines := range s.pending {
+		s.debugger.SetBreakpoints(file, lines)
+	}
+	s.vm = lokum.NewVM(bytecode, globals, -1)
+	s.vm.SetDebugger(s.debugger)
+	s.vm.SetStdout(&output{conn: s.conn, category: "stdout"})
+	s.vm.SetStderr(&output{conn: s.conn, category: "stderr"})
+	return nil
+}
+
+func (s *Server) start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.vm == nil || !s.ready || s.launched {
+		return
+	}
+	s.launched = true
+	go func() {
+		defer close(s.done)
+		exitCode := 0
+		if err := s.vm.Run(); err != nil {
+			exitCode = 1
+			_ = s.conn.Event("output", &OutputEvent{
+				Category: "stderr",
+				Output:   err.Error() + "\n",
+			})
+		}
+		_ = s.conn.Event("exited", &ExitedEvent{ExitCode: exitCode})
+		_ = s.conn.Event("terminated", nil)
+	}()
+}
+
+func (s *Server) terminate() {
+	s.lock.Lock()
+	launched, paused := s.launched, s.state != nil
+	if s.vm != nil && !paused {
+		s.vm.Abort()
+	}
+	s.lock.Unlock()
+	if !launched {
+		return
+	}
+	if paused {
+		s.resume <- lokum.DebugStop
+	}
+	<-s.done
+}
+
+func (s *Server) stopped(st *lokum.DebugState) lokum.DebugAction {
+	s.lock.Lock()
+	s.state = st
+	s.refs = nil
+	s.lock.Unlock()
+
+	event := &StoppedEvent{
+		ThreadID:          threadID,
+		AllThreadsStopped: true,
+		Description:       st.Reason.String(),
+	}
+	switch st.Reason {
+	case lokum.StopEntry:
+		event.Reason = "entry"
+	case lokum.StopBreakpoint:
+		event.Reason = "breakpoint"
+		event.HitBreakpointIDs = []int{st.Breakpoint.ID}
+	case lokum.StopPause:
+		event.Reason = "pause"
+	default:
+		event.Reason = "step"
+	}
+	if err := s.conn.Event("stopped", event); err != nil {
+		return lokum.DebugStop
+	}
+
+	action := <-s.resume
+	s.lock.Lock()
+	s.state = nil
+	s.lock.Unlock()
+	return action
+}
+
+func (s *Server) continueWith(action lokum.DebugAction) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.state == nil {
+		return errNotPaused
+	}
+	s.state = nil
+	s.resume <- action
+	return nil
+}
+
+func (s *Server) setBreakpoints(args SetBreakpointsArguments) *SetBreakpointsResponse {
+	lines := make([]int, len(args.Breakpoints))
+	for i, bp := range args.Breakpoints {
+		lines[i] = bp.Line
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	res := &SetBreakpointsResponse{Breakpoints: []Breakpoint{}}
+	if s.debugger == nil {
+		s.pending[args.Source.Path] = lines
+		for _, line := range lines {
+			res.Breakpoints = append(res.Breakpoints, Breakpoint{Line: line})
+		}
+		return res
+	}
+	for _, bp := range s.debugger.SetBreakpoints(args.Source.Path, lines) {
+		res.Breakpoints = append(res.Breakpoints, Breakpoint{
+			ID:       bp.ID,
+			Verified: bp.Verified,
+			Line:     bp.Line,
+			Source:   &args.Source,
+		})
+	}
+	return res
+}
+
+func (s *Server) paused() (*lokum.DebugState, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.state == nil {
+		return nil, errNotPaused
+	}
+	return s.state, nil
+}
+
+func (s *Server) frame(id int) (*lokum.DebugState, *lokum.DebugFrame, error) {
+	st, err := s.paused()
+	if err != nil {
+		return nil, nil, err
+	}
+	frames := st.Frames()
+	if id < 0 || id >= len(frames) {
+		return nil, nil, fmt.Errorf("geçersiz çerçeve: %d", id)
+	}
+	return st, frames[id], nil
+}
+
+func (s *Server) stackTrace() (*StackTraceResponse, error) {
+	st, err := s.paused()
+	if err != nil {
+		return nil, err
+	}
+	res := &StackTraceResponse{StackFrames: []StackFrame{}}
+	for i, f := range st.Frames() {
+		res.StackFrames = append(res.StackFrames, StackFrame{
+			ID:     i,
+			Name:   f.Name,
+			Source: s.source(f.Pos.Filename),
+			Line:   f.Pos.Line,
+			Column: f.Pos.Column,
+		})
+	}
+	res.TotalFrames = len(res.StackFrames)
+	return res, nil
+}
+
+func (s *Server) source(file string) *Source {
+	src := &Source{Name: filepath.Base(file), Path: file}
+	if file == filepath.Base(s.program) {
+		src.Path = s.program
+	}
+	return src
+}
+
+func (s *Server) scopes(id int) (*ScopesResponse, error) {
+	st, f, err := s.frame(id)
+	if err != nil {
+		return nil, err
+	}
+	return &ScopesResponse{Scopes: []Scope{
+		{Name: "Yerel", VariablesReference: s.reference(values(f.Locals()))},
+		{Name: "Serbest", VariablesReference: s.reference(values(f.Free()))},
+		{Name: "Global", VariablesReference: s.reference(values(st.Globals()))},
+	}}, nil
+}
+
+func (s *Server) variables(ref int) (*VariablesResponse, error) {
+	if _, err := s.paused(); err != nil {
+		return nil, err
+	}
+	s.lock.Lock()
+	if ref < 1 || ref > len(s.refs) {
+		s.lock.Unlock()
+		return nil, fmt.Errorf("geçersiz değişken referansı: %d", ref)
+	}
+	vals := s.refs[ref-1]
+	s.lock.Unlock()
+
+	res := &VariablesResponse{Variables: []Variable{}}
+	for _, v := range vals {
+		res.Variables = append(res.Variables, Variable{
+			Name:               v.name,
+			Value:              v.object.String(),
+			Type:               v.object.TypeName(),
+			VariablesReference: s.reference(children(v.object)),
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) evaluate(args EvaluateArguments) (*EvaluateResponse, error) {
+	id := 0
+	if args.FrameID != nil {
+		id = *args.FrameID
+	}
+	st, f, err := s.frame(id)
+	if err != nil {
+		return nil, err
+	}
+	res, err := st.Eval(f, args.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return &EvaluateResponse{
+		Result:             res.String(),
+		Type:               res.TypeName(),
+		VariablesReference: s.reference(children(res)),
+	}, nil
+}
+
+func (s *Server) reference(vals []value) int {
+	if vals == nil {
+		return 0
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.refs = append(s.refs, vals)
+	return len(s.refs)
+}
+
+func values(vars []*lokum.Variable) []value {
+	vals := make([]value, 0, len(vars))
+	for _, v := range vars {
+		vals = append(vals, value{name: v.Name(), object: v.Object()})
+	}
+	return vals
+}
+
+func children(o lokum.Object) []value {
+	var vals []value
+	switch o := o.(type) {
+	case *lokum.Array:
+		for i, elem := range o.Value {
+			vals = append(vals, value{fmt.Sprintf("[%d]", i), elem})
+		}
+	case *lokum.ImmutableArray:
+		for i, elem := range o.Value {
+			vals = append(vals, value{fmt.Sprintf("[%d]", i), elem})
+		}
+	case *lokum.Map:
+		vals = entries(o.Value)
+	case *lokum.ImmutableMap:
+		vals = entries(o.Value)
+	}
+	return vals
+}
+
+func entries(m *lokum.OrderedMap) []value {
+	var vals []value
+	m.Range(func(key, elem lokum.Object) bool {
+		vals = append(vals, value{key.String(), elem})
+		return true
+	})
+	return vals
+}
+
+type output struct {
+	conn     *Conn
+	category string
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	err := o.conn.Event("output", &OutputEvent{
+		Category: o.category,
+		Output:   string(p),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
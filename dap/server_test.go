@@ -0,0 +1,190 @@
+package dap
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/parser"
+)
+
+const testProgram = `f3 := fn(a) {
+	x := a + 1
+	dön x
+}
+f2 := fn() {
+	y := f3(1)
+	dön y
+}
+z := f2()
+`
+
+func testLaunch(program string, args []string) (*lokum.Bytecode, []lokum.Object, error) {
+	src := []byte(testProgram)
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile("ana.lokum", -1, len(src))
+	file, err := parser.NewParser(srcFile, src, nil).ParseFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	c := lokum.NewCompiler(srcFile, nil, nil, nil, nil)
+	if err := c.Compile(file); err != nil {
+		return nil, nil, err
+	}
+	return c.Bytecode(), nil, nil
+}
+
+type testClient struct {
+	t        *testing.T
+	conn     *Conn
+	messages chan *Message
+	queue    []*Message
+}
+
+func newTestClient(t *testing.T) (*testClient, chan error) {
+	cr, cw := io.Pipe()
+	sr, sw := io.Pipe()
+	server := NewServer(cr, sw, testLaunch)
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Serve()
+		_ = sw.Close()
+	}()
+
+	c := &testClient{t: t, conn: NewConn(sr, cw), messages: make(chan *Message, 100)}
+	go func() {
+		defer close(c.messages)
+		for {
+			msg, err := c.conn.Read()
+			if err != nil {
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+	return c, done
+}
+
+func (c *testClient) request(command string, args interface{}) *Message {
+	c.t.Helper()
+	if err := c.conn.Request(command, args); err != nil {
+		c.t.Fatal(err)
+	}
+	res := c.expect("response", command)
+	if res.Success == nil || !*res.Success {
+		c.t.Fatalf("%s başarısız: %s", command, res.Message)
+	}
+	return res
+}
+
+func (c *testClient) expect(typ, name string) *Message {
+	c.t.Helper()
+	match := func(msg *Message) bool {
+		return msg.Type == typ && (msg.Command == name || msg.Event == name)
+	}
+	for i, msg := range c.queue {
+		if match(msg) {
+			c.queue = append(c.queue[:i], c.queue[i+1:]...)
+			return msg
+		}
+	}
+	for {
+		select {
+		case msg, ok := <-c.messages:
+			if !ok {
+				c.t.Fatalf("%s %s beklenirken bağlantı kapandı", typ, name)
+			}
+			if match(msg) {
+				return msg
+			}
+			c.queue = append(c.queue, msg)
+		case <-time.After(5 * time.Second):
+			c.t.Fatalf("%s %s için zaman aşımı", typ, name)
+		}
+	}
+}
+
+func (c *testClient) body(msg *Message, v interface{}) {
+	c.t.Helper()
+	if err := json.Unmarshal(msg.Body, v); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) stopped(reason string, line int) {
+	c.t.Helper()
+	var event StoppedEvent
+	c.body(c.expect("event", "stopped"), &event)
+	if event.Reason != reason {
+		c.t.Fatalf("durma nedeni %s, beklenen %s", event.Reason, reason)
+	}
+	var trace StackTraceResponse
+	c.body(c.request("stackTrace", &StackTraceArguments{ThreadID: threadID}), &trace)
+	if len(trace.StackFrames) == 0 || trace.StackFrames[0].Line != line {
+		c.t.Fatalf("yığın %+v, beklenen satır %d", trace.StackFrames, line)
+	}
+}
+
+func TestServerSession(t *testing.T) {
+	c, done := newTestClient(t)
+
+	c.request("initialize", nil)
+	c.expect("event", "initialized")
+
+	var bps SetBreakpointsResponse
+	c.body(c.request("setBreakpoints", &SetBreakpointsArguments{
+		Source:      Source{Path: "/proje/ana.lokum"},
+		Breakpoints: []SourceBreakpoint{{Line: 6}},
+	}), &bps)
+	if len(bps.Breakpoints) != 1 {
+		t.Fatalf("kesme noktaları %+v", bps.Breakpoints)
+	}
+	c.request("launch", &LaunchArguments{Program: "/proje/ana.lokum"})
+	c.request("configurationDone", nil)
+	c.stopped("breakpoint", 6)
+
+	c.request("stepIn", nil)
+	c.stopped("step", 2)
+
+	var scopes ScopesResponse
+	c.body(c.request("scopes", &ScopesArguments{FrameID: 0}), &scopes)
+	if len(scopes.Scopes) == 0 || scopes.Scopes[0].Name != "Yerel" {
+		t.Fatalf("kapsamlar %+v", scopes.Scopes)
+	}
+	var vars VariablesResponse
+	c.body(c.request("variables", &VariablesArguments{
+		VariablesReference: scopes.Scopes[0].VariablesReference,
+	}), &vars)
+	if len(vars.Variables) != 1 || vars.Variables[0].Name != "a" ||
+		vars.Variables[0].Value != "1" {
+		t.Fatalf("yerel değişkenler %+v", vars.Variables)
+	}
+
+	frame := 0
+	var eval EvaluateResponse
+	c.body(c.request("evaluate", &EvaluateArguments{
+		Expression: "a * 10",
+		FrameID:    &frame,
+	}), &eval)
+	if eval.Result != "10" {
+		t.Fatalf("değerlendirme %q, beklenen 10", eval.Result)
+	}
+
+	c.request("stepOut", nil)
+	c.stopped("step", 6)
+
+	c.request("continue", nil)
+	var exited ExitedEvent
+	c.body(c.expect("event", "exited"), &exited)
+	if exited.ExitCode != 0 {
+		t.Fatalf("çıkış kodu %d", exited.ExitCode)
+	}
+	c.expect("event", "terminated")
+
+	c.request("disconnect", nil)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,31 +1,168 @@
-package lokum
-
-import (
-	"context"
-	"fmt"
-	"strings"
-)
-
-func Eval(
-	ctx context.Context,
-	expr string,
-	params map[string]interface{},
-) (interface{}, error) {
-	expr = strings.TrimSpace(expr)
-	if expr == "" {
-		return nil, fmt.Errorf("empty expression")
-	}
-
-	script := NewScript([]byte(fmt.Sprintf("__res__ := (%s)", expr)))
-	for pk, pv := range params {
-		err := script.Add(pk, pv)
-		if err != nil {
-			return nil, fmt.Errorf("script add: %w", err)
-		}
-	}
-	compiled, err := script.RunContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("script run: %w", err)
-	}
-	return compiled.Get("__res__").Value(), nil
-}
+package lokum
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const resultName = "__res__"
+
+var exprCache = NewExprCache(1024)
+
+func Eval(
+	ctx context.Context,
+	expr string,
+	params map[string]interface{},
+) (interface{}, error) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiled, err := exprCache.Get(expr, names...)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Eval(ctx, params)
+}
+
+type CompiledExpr struct {
+	bytecode *Bytecode
+	params   map[string]int
+	globals  []Object
+	result   int
+	pool     sync.Pool
+}
+
+func CompileExpr(expr string, params ...string) (*CompiledExpr, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	script := NewScript([]byte(fmt.Sprintf("%s := (%s)", resultName, expr)))
+	for _, name := range params {
+		if err := script.Add(name, nil); err != nil {
+			return nil, fmt.Errorf("script add: %w", err)
+		}
+	}
+	compiled, err := script.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("script compile: %w", err)
+	}
+
+	e := &CompiledExpr{
+		bytecode: compiled.bytecode,
+		params:   make(map[string]int, len(params)),
+		globals:  compiled.globals,
+		result:   compiled.globalIndexes[resultName],
+	}
+	for _, name := range params {
+		e.params[name] = compiled.globalIndexes[name]
+	}
+	e.pool.New = func() interface{} {
+		return NewVM(e.bytecode, make([]Object, len(e.globals)), -1)
+	}
+	return e, nil
+}
+
+func (e *CompiledExpr) Eval(
+	ctx context.Context,
+	params map[string]interface{},
+) (interface{}, error) {
+	v := e.pool.Get().(*VM)
+	defer e.pool.Put(v)
+
+	copy(v.globals, e.globals)
+	for name, value := range params {
+		idx, ok := e.params[name]
+		if !ok {
+			return nil, fmt.Errorf("tanımlanmamış parametre: %s", name)
+		}
+		obj, err := FromInterface(value)
+		if err != nil {
+			return nil, fmt.Errorf("script add: %w", err)
+		}
+		v.globals[idx] = obj
+	}
+
+	var err error
+	if ctx.Done() == nil {
+		err = runRecover(v.Run)
+	} else {
+		err = runContext(ctx, v, v.Run)
+	}
+	res := v.globals[e.result]
+	for i := range v.globals {
+		v.globals[i] = nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("script run: %w", err)
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return ToInterface(res), nil
+}
+
+type ExprCache struct {
+	size  int
+	lock  sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+type exprCacheEntry struct {
+	key  string
+	expr *CompiledExpr
+}
+
+func NewExprCache(size int) *ExprCache {
+	return &ExprCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *ExprCache) Get(expr string, params ...string) (*CompiledExpr, error) {
+	key := expr + "\x00" + strings.Join(params, "\x00")
+
+	c.lock.Lock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		c.lock.Unlock()
+		return el.Value.(*exprCacheEntry).expr, nil
+	}
+	c.lock.Unlock()
+
+	compiled, err := CompileExpr(expr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(*exprCacheEntry).expr, nil
+	}
+	c.items[key] = c.order.PushFront(&exprCacheEntry{key: key, expr: compiled})
+	for c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.items, el.Value.(*exprCacheEntry).key)
+	}
+	return compiled, nil
+}
+
+func (c *ExprCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.order.Len()
+}
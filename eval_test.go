@@ -0,0 +1,81 @@
+package lokum
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const benchExpr = `a * 2 + uzunluk(b)`
+
+var benchParams = map[string]interface{}{"a": 21, "b": "lokum"}
+
+func TestEvalRecoversPanic(t *testing.T) {
+	params := map[string]interface{}{
+		"patla": CallableFunc(func(args ...Object) (Object, error) {
+			panic("bom")
+		}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, ctx := range []context.Context{context.Background(), ctx} {
+		_, err := Eval(ctx, "patla()", params)
+		if err == nil || !strings.Contains(err.Error(), "bom") {
+			t.Fatalf("hata %v", err)
+		}
+	}
+	res, err := Eval(context.Background(), benchExpr, benchParams)
+	if err != nil || res != int64(47) {
+		t.Fatalf("%v %v", res, err)
+	}
+}
+
+func BenchmarkEval(b *testing.B) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		if _, err := Eval(ctx, benchExpr, benchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompileExprRun(b *testing.B) {
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		e, err := CompileExpr(benchExpr, "a", "b")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := e.Eval(ctx, benchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiledExprEval(b *testing.B) {
+	ctx := context.Background()
+	e, err := CompileExpr(benchExpr, "a", "b")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.Eval(ctx, benchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExprCache(b *testing.B) {
+	ctx := context.Background()
+	cache := NewExprCache(16)
+	for i := 0; i < b.N; i++ {
+		e, err := cache.Get(benchExpr, "a", "b")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := e.Eval(ctx, benchParams); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
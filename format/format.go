@@ -0,0 +1,56 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+var ErrSemantics = errors.New("biçimlendirme programın anlamını değiştirdi")
+
+func Source(src []byte) ([]byte, error) {
+	file, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &printer{
+		src:      src,
+		file:     file.InputFile,
+		comments: file.Comments,
+	}
+	p.stmtList(file.Stmts, parser.NoPos)
+	p.flushComments(parser.NoPos)
+	out := p.bytes()
+
+	formatted, err := parse(out)
+	if err != nil || fingerprint(formatted) != fingerprint(file) ||
+		len(formatted.Comments) != len(file.Comments) {
+		return nil, ErrSemantics
+	}
+	if bytes.Contains(src, []byte("\r\n")) {
+		out = bytes.ReplaceAll(out, []byte("\n"), []byte("\r\n"))
+	}
+	return out, nil
+}
+
+func parse(src []byte) (*parser.File, error) {
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile("(kaynak)", -1, len(src))
+	p := parser.NewParserWithMode(srcFile, src, nil, parser.ScanComments)
+	return p.ParseFile()
+}
+
+func fingerprint(file *parser.File) string {
+	var b strings.Builder
+	for _, s := range file.Stmts {
+		if _, ok := s.(*parser.EmptyStmt); ok {
+			continue
+		}
+		b.WriteString(s.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
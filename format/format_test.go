@@ -0,0 +1,121 @@
+package format_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/format"
+)
+
+var formatTests = []struct {
+	name string
+	src  string
+	want string
+}{
+	{
+		"boşluklar",
+		"x:=1\ny:=x+2*3\n",
+		"x := 1\ny := x + 2 * 3\n",
+	},
+	{
+		"yorumlar",
+		"// başlık\nx:=1   // sayaç\n/* blok\nyorum */\ny:=2\n",
+		"// başlık\nx := 1 // sayaç\n/* blok\nyorum */\ny := 2\n",
+	},
+	{
+		"tip işaretleri",
+		"x:sayı:=1\ntopla:=fn(a:sayı,b:sayı|float)->sayı{dön a+b}\n",
+		"x: sayı := 1\ntopla := fn(a: sayı, b: sayı|float) -> sayı { dön a + b }\n",
+	},
+	{
+		"bloklar",
+		"x:=0\neğer x>0{\nx=1\n}yoksa{\nx=2\n}\ntekrarla i:=0;i<3;i++{x+=i}\n",
+		"x := 0\neğer x > 0 {\n\tx = 1\n} yoksa {\n\tx = 2\n}\ntekrarla i := 0; i < 3; i++ {\n\tx += i\n}\n",
+	},
+	{
+		"çok satırlı liste",
+		"y:=[1,2,\n3]\n",
+		"y := [\n\t1,\n\t2,\n\t3\n]\n",
+	},
+	{
+		"uzun satır",
+		`uzun := [ "` + strings.Repeat("a", 30) + `", "` + strings.Repeat("b", 30) +
+			`", "` + strings.Repeat("c", 30) + `" ]` + "\n",
+		"uzun := [\n\t\"" + strings.Repeat("a", 30) + "\",\n\t\"" +
+			strings.Repeat("b", 30) + "\",\n\t\"" + strings.Repeat("c", 30) + "\"\n]\n",
+	},
+	{
+		"harita anahtarları",
+		"m:={a:1,\"b c\":2}\ntekrarla k,d in m{x:=k}\n",
+		"m := {a: 1, \"b c\": 2}\ntekrarla k, d in m {\n\tx := k\n}\n",
+	},
+	{
+		"crlf",
+		"x:=1\r\ny:=2\r\n",
+		"x := 1\r\ny := 2\r\n",
+	},
+}
+
+func TestSource(t *testing.T) {
+	for _, tt := range formatTests {
+		got, err := format.Source([]byte(tt.src))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Fatalf("%s:\n%s\nbeklenen:\n%s", tt.name, got, tt.want)
+		}
+		again, err := format.Source(got)
+		if err != nil {
+			t.Fatalf("%s: ikinci biçimlendirme: %v", tt.name, err)
+		}
+		if string(again) != string(got) {
+			t.Fatalf("%s: biçimlendirme kararlı değil:\n%s\nikinci:\n%s",
+				tt.name, got, again)
+		}
+	}
+}
+
+func TestSourceKeepsSemantics(t *testing.T) {
+	src := `
+topla:=fn(a:sayı,b:sayı)->sayı{dön a+b}
+x:=0 // sayaç
+tekrarla i:=0;i<10;i++{
+	eğer i%2==0{x+=topla(i,1)}yoksa{x-=i}
+}
+m:={a:[1,2,
+3],"b c":{d:x}}
+k:=küme(1,2,3)
+s:=yazı(x)+"!"
+`
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := globals(t, src)
+	got := globals(t, string(formatted))
+	if len(got) != len(want) {
+		t.Fatalf("%d global, beklenen %d", len(got), len(want))
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Fatalf("%s = %s, beklenen %s", name, got[name], value)
+		}
+	}
+}
+
+func globals(t *testing.T, src string) map[string]string {
+	c, err := lokum.NewScript([]byte(src)).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]string)
+	for _, v := range c.GetAll() {
+		values[v.Name()] = v.Object().String()
+	}
+	return values
+}
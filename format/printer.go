@@ -0,0 +1,483 @@
+package format
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/token"
+)
+
+const (
+	maxWidth = 100
+	tabWidth = 4
+)
+
+type printer struct {
+	src      []byte
+	file     *parser.SourceFile
+	comments []*parser.Comment
+	next     int
+	buf      bytes.Buffer
+	indent   int
+	lastLine int
+	flat     bool
+}
+
+func (p *printer) bytes() []byte {
+	out := bytes.Trim(p.buf.Bytes(), "\n")
+	if len(out) == 0 {
+		return nil
+	}
+	return append(out, '\n')
+}
+
+func (p *printer) print(s ...string) {
+	for _, s := range s {
+		p.buf.WriteString(s)
+	}
+}
+
+func (p *printer) newline() {
+	p.buf.WriteByte('\n')
+	for i := 0; i < p.indent; i++ {
+		p.buf.WriteByte('\t')
+	}
+}
+
+func (p *printer) line(pos parser.Pos) int {
+	return p.file.Position(pos).Line
+}
+
+func (p *printer) column() int {
+	b := p.buf.Bytes()
+	col := 0
+	for i := len(b) - 1; i >= 0 && b[i] != '\n'; i-- {
+		if b[i] == '\t' {
+			col += tabWidth
+		} else if utf8.RuneStart(b[i]) {
+			col++
+		}
+	}
+	return col
+}
+
+func (p *printer) comment() *parser.Comment {
+	if p.flat || p.next >= len(p.comments) {
+		return nil
+	}
+	return p.comments[p.next]
+}
+
+func (p *printer) hasComments(from, to parser.Pos) bool {
+	for _, c := range p.comments[p.next:] {
+		if c.Pos() >= to {
+			break
+		}
+		if c.Pos() > from {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *printer) separate(line int) {
+	if p.lastLine > 0 && line-p.lastLine > 1 {
+		p.buf.WriteByte('\n')
+	}
+}
+
+func (p *printer) flushComments(before parser.Pos) {
+	for c := p.comment(); c != nil; c = p.comment() {
+		if before.IsValid() && c.Pos() >= before {
+			return
+		}
+		line := p.line(c.Pos())
+		p.separate(line)
+		p.newline()
+		p.print(c.Text)
+		p.lastLine = line + strings.Count(c.Text, "\n")
+		p.next++
+	}
+}
+
+func (p *printer) trailingComments(end, limit parser.Pos) {
+	line := p.line(end - 1)
+	for c := p.comment(); c != nil; c = p.comment() {
+		if c.Pos() >= end && p.line(c.Pos()) != line ||
+			limit.IsValid() && c.Pos() >= limit {
+			return
+		}
+		p.print(" ", c.Text)
+		p.lastLine = p.line(c.Pos()) + strings.Count(c.Text, "\n")
+		p.next++
+		if strings.HasPrefix(c.Text, "//") {
+			return
+		}
+	}
+}
+
+func (p *printer) stmtList(list []parser.Stmt, end parser.Pos) {
+	p.lastLine = 0
+	for _, s := range list {
+		if _, ok := s.(*parser.EmptyStmt); ok {
+			continue
+		}
+		p.flushComments(s.Pos())
+		p.separate(p.line(s.Pos()))
+		p.newline()
+		p.stmt(s)
+		p.lastLine = p.line(s.End() - 1)
+		p.trailingComments(s.End(), end)
+	}
+	p.flushComments(end)
+}
+
+func (p *printer) block(b *parser.BlockStmt) {
+	if p.flat {
+		if len(b.Stmts) == 0 {
+			p.print("{}")
+			return
+		}
+		p.print("{ ")
+		first := true
+		for _, s := range b.Stmts {
+			if _, ok := s.(*parser.EmptyStmt); ok {
+				continue
+			}
+			if !first {
+				p.print("; ")
+			}
+			first = false
+			p.stmt(s)
+		}
+		p.print(" }")
+		return
+	}
+
+	if len(b.Stmts) == 0 && !p.hasComments(b.LBrace, b.RBrace) {
+		p.print("{}")
+		return
+	}
+	limit := b.RBrace
+	if len(b.Stmts) > 0 {
+		limit = b.Stmts[0].Pos()
+	}
+	p.print("{")
+	p.trailingComments(b.LBrace+1, limit)
+	p.indent++
+	p.stmtList(b.Stmts, b.RBrace)
+	p.indent--
+	p.newline()
+	p.print("}")
+}
+
+func (p *printer) stmt(s parser.Stmt) {
+	switch s := s.(type) {
+	case *parser.AssignStmt:
+		p.exprList(s.LHS)
+		if s.Type != nil {
+			p.print(": ")
+			p.typeExpr(s.Type)
+		}
+		p.print(" ", s.Token.String(), " ")
+		p.exprList(s.RHS)
+	case *parser.BranchStmt:
+		p.print(s.Token.String())
+		if s.Label != nil {
+			p.print(" ", s.Label.Name)
+		}
+	case *parser.ExportStmt:
+		p.print(token.Export.String(), " ")
+		p.expr(s.Result)
+	case *parser.ExprStmt:
+		p.expr(s.Expr)
+	case *parser.ForInStmt:
+		p.print(token.For.String(), " ")
+		if s.Key.Name != "_" || s.Key.NamePos != s.Value.NamePos {
+			p.print(s.Key.Name, ", ")
+		}
+		p.print(s.Value.Name, " ", token.In.String(), " ")
+		p.expr(s.Iterable)
+		p.print(" ")
+		p.block(s.Body)
+	case *parser.ForStmt:
+		p.print(token.For.String(), " ")
+		if s.Init != nil || s.Post != nil {
+			if s.Init != nil {
+				p.stmt(s.Init)
+			}
+			p.print("; ")
+			if s.Cond != nil {
+				p.expr(s.Cond)
+			}
+			p.print("; ")
+			if s.Post != nil {
+				p.stmt(s.Post)
+				p.print(" ")
+			}
+		} else if s.Cond != nil {
+			p.expr(s.Cond)
+			p.print(" ")
+		}
+		p.block(s.Body)
+	case *parser.IfStmt:
+		p.print(token.If.String(), " ")
+		if s.Init != nil {
+			p.stmt(s.Init)
+			p.print("; ")
+		}
+		p.expr(s.Cond)
+		p.print(" ")
+		p.block(s.Body)
+		if s.Else != nil {
+			p.print(" ", token.Else.String(), " ")
+			p.stmt(s.Else)
+		}
+	case *parser.BlockStmt:
+		p.block(s)
+	case *parser.IncDecStmt:
+		p.expr(s.Expr)
+		p.print(s.Token.String())
+	case *parser.ReturnStmt:
+		p.print(token.Return.String())
+		if s.Result != nil {
+			p.print(" ")
+			p.expr(s.Result)
+		}
+	default:
+		p.print(s.String())
+	}
+}
+
+func (p *printer) exprList(list []parser.Expr) {
+	for i, x := range list {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.expr(x)
+	}
+}
+
+func (p *printer) expr(x parser.Expr) {
+	switch x := x.(type) {
+	case *parser.ArrayLit:
+		p.elements(x, x.LBrack, x.RBrack, len(x.Elements), "[", "]",
+			func(i int) parser.Expr { return x.Elements[i] })
+	case *parser.MapLit:
+		p.elements(x, x.LBrace, x.RBrace, len(x.Elements), "{", "}",
+			func(i int) parser.Expr { return x.Elements[i] })
+	case *parser.MapElementLit:
+		p.print(p.mapKey(x), ": ")
+		p.expr(x.Value)
+	case *parser.BinaryExpr:
+		p.expr(x.LHS)
+		p.print(" ", x.Token.String(), " ")
+		p.expr(x.RHS)
+	case *parser.CallExpr:
+		p.expr(x.Func)
+		p.print("(")
+		p.exprList(x.Args)
+		if x.Ellipsis.IsValid() {
+			p.print("...")
+		}
+		p.print(")")
+	case *parser.CondExpr:
+		p.expr(x.Cond)
+		p.print(" ? ")
+		p.expr(x.True)
+		p.print(" : ")
+		p.expr(x.False)
+	case *parser.ErrorExpr:
+		p.print(token.Error.String(), "(")
+		p.expr(x.Expr)
+		p.print(")")
+	case *parser.ImmutableExpr:
+		p.print(token.Immutable.String(), "(")
+		p.expr(x.Expr)
+		p.print(")")
+	case *parser.FuncLit:
+		p.funcType(x.Type)
+		p.print(" ")
+		p.funcBody(x.Body)
+	case *parser.FuncType:
+		p.funcType(x)
+	case *parser.Ident:
+		p.print(x.Name)
+	case *parser.ImportExpr:
+		p.print(token.Import.String(), "(", strconv.Quote(x.ModuleName), ")")
+	case *parser.IndexExpr:
+		p.expr(x.Expr)
+		p.print("[")
+		if x.Index != nil {
+			p.expr(x.Index)
+		}
+		p.print("]")
+	case *parser.ParenExpr:
+		p.print("(")
+		p.expr(x.Expr)
+		p.print(")")
+	case *parser.SelectorExpr:
+		p.expr(x.Expr)
+		p.print(".")
+		if sel, ok := x.Sel.(*parser.StringLit); ok {
+			p.print(sel.Value)
+		} else {
+			p.expr(x.Sel)
+		}
+	case *parser.SliceExpr:
+		p.expr(x.Expr)
+		p.print("[")
+		if x.Low != nil {
+			p.expr(x.Low)
+		}
+		p.print(":")
+		if x.High != nil {
+			p.expr(x.High)
+		}
+		p.print("]")
+	case *parser.SpawnExpr:
+		p.print(token.Spawn.String(), " ")
+		p.expr(x.Call)
+	case *parser.UnaryExpr:
+		p.print(x.Token.String())
+		if u, ok := x.Expr.(*parser.UnaryExpr); ok && u.Token == x.Token &&
+			(x.Token == token.Add || x.Token == token.Sub) {
+			p.print(" ")
+		}
+		p.expr(x.Expr)
+	case *parser.TypeExpr:
+		p.typeExpr(x)
+	default:
+		p.print(x.String())
+	}
+}
+
+func (p *printer) elements(
+	x parser.Expr,
+	open, close parser.Pos,
+	n int,
+	lbrack, rbrack string,
+	elem func(i int) parser.Expr,
+) {
+	if !p.multiline(x, open, close, n) {
+		p.print(lbrack)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.expr(elem(i))
+		}
+		p.print(rbrack)
+		return
+	}
+
+	p.print(lbrack)
+	p.trailingComments(open+1, elem(0).Pos())
+	p.indent++
+	p.lastLine = 0
+	for i := 0; i < n; i++ {
+		e := elem(i)
+		p.flushComments(e.Pos())
+		p.separate(p.line(e.Pos()))
+		p.newline()
+		p.expr(e)
+		if i < n-1 {
+			p.print(",")
+		}
+		p.lastLine = p.line(e.End() - 1)
+		limit := close
+		if i < n-1 {
+			limit = elem(i + 1).Pos()
+		}
+		p.trailingComments(e.End(), limit)
+	}
+	p.flushComments(close)
+	p.indent--
+	p.newline()
+	p.print(rbrack)
+}
+
+func (p *printer) multiline(x parser.Expr, open, close parser.Pos, n int) bool {
+	if p.flat || n == 0 {
+		return false
+	}
+	if p.line(open) != p.line(close) || p.hasComments(open, close) {
+		return true
+	}
+	return n > 1 && p.column()+p.width(x) > maxWidth
+}
+
+func (p *printer) width(x parser.Expr) int {
+	q := &printer{src: p.src, file: p.file, flat: true}
+	q.expr(x)
+	return utf8.RuneCount(q.buf.Bytes())
+}
+
+func (p *printer) funcBody(b *parser.BlockStmt) {
+	if p.flat || p.line(b.LBrace) != p.line(b.RBrace) ||
+		p.hasComments(b.LBrace, b.RBrace) {
+		p.block(b)
+		return
+	}
+	p.flat = true
+	p.block(b)
+	p.flat = false
+}
+
+func (p *printer) funcType(t *parser.FuncType) {
+	p.print(token.Func.String(), "(")
+	if t.Params != nil {
+		for i, name := range t.Params.List {
+			if i > 0 {
+				p.print(", ")
+			}
+			if t.Params.VarArgs && i == len(t.Params.List)-1 {
+				p.print("...")
+			}
+			p.print(name.Name)
+			if typ := t.Params.Type(i); typ != nil {
+				p.print(": ")
+				p.typeExpr(typ)
+			}
+		}
+	}
+	p.print(")")
+	if t.Result != nil {
+		p.print(" -> ")
+		p.typeExpr(t.Result)
+	}
+}
+
+func (p *printer) typeExpr(t *parser.TypeExpr) {
+	for i, name := range t.Names {
+		if i > 0 {
+			p.print("|")
+		}
+		p.print(name.Name)
+	}
+}
+
+func (p *printer) mapKey(e *parser.MapElementLit) string {
+	if e.KeyExpr != nil {
+		return e.KeyExpr.String()
+	}
+	src := p.src[p.file.Offset(e.KeyPos):]
+	if len(src) == 0 || (src[0] != '"' && src[0] != '`') {
+		return e.Key
+	}
+	quote := src[0]
+	for i := 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return string(parser.StripCR(src[:i+1], false))
+		}
+	}
+	return strconv.Quote(e.Key)
+}
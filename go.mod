@@ -1,3 +1,3 @@
-module github.com/onrirr/lokum
-
-go 1.13
+module github.com/onrirr/lokum
+
+go 1.16
@@ -0,0 +1,221 @@
+package lokum
+
+import (
+	"sync/atomic"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+func (v *VM) spawn(fn Object, args []Object) *Task {
+	seen := make(map[Object]Object)
+	for i, arg := range args {
+		args[i] = isolate(arg, seen)
+	}
+	task := &Task{done: make(chan struct{})}
+
+	globals := make([]Object, len(v.globals))
+	for i := range v.taskGlobals(fn, args) {
+		if g := v.globals[i]; g != nil {
+			globals[i] = isolate(g, seen)
+		}
+	}
+
+	root := v.taskRoot()
+	if v.budget != nil {
+		atomic.StoreInt32(&v.budget.shared, 1)
+	}
+	child := v.newChild(globals)
+	root.addChild(child)
+	root.tasks.Add(1)
+	cfn, ok := fn.(*CompiledFunction)
+	if !ok {
+		child.ip = 0
+		go func() {
+			defer root.tasks.Done()
+			defer close(task.done)
+			defer root.removeChild(child)
+			defer root.addSpawnStats(child)
+			task.value, task.err = child.callNative(fn, args)
+		}()
+		return task
+	}
+
+	child.stack[0] = isolate(cfn, seen)
+	child.stack[1] = &Array{Value: args}
+	go func() {
+		defer root.tasks.Done()
+		defer close(task.done)
+		defer root.removeChild(child)
+		defer root.addSpawnStats(child)
+		if err := child.runFrom(2); err != nil {
+			task.err = err
+			return
+		}
+		task.value = child.stack[child.sp-1]
+	}()
+	return task
+}
+
+// taskGlobals, görevin erişebileceği global değişkenlerin indekslerini
+// döndürür. fn ve argümanlardan ulaşılan fonksiyonların komutları taranır;
+// görev başlatılırken yalnızca bu globaller kopyalanır.
+func (v *VM) taskGlobals(fn Object, args []Object) map[int]bool {
+	used := make(map[int]bool)
+	seen := make(map[Object]bool)
+	var walk func(o Object)
+	walk = func(o Object) {
+		switch o := o.(type) {
+		case *Array, *ImmutableArray, *Map, *ImmutableMap, *Error,
+			*CompiledFunction:
+			if seen[o] {
+				return
+			}
+			seen[o] = true
+		default:
+			return
+		}
+		switch o := o.(type) {
+		case *Array:
+			for _, e := range o.Value {
+				walk(e)
+			}
+		case *ImmutableArray:
+			for _, e := range o.Value {
+				walk(e)
+			}
+		case *Map:
+			walkMap(o.Value, walk)
+		case *ImmutableMap:
+			walkMap(o.Value, walk)
+		case *Error:
+			walk(o.Value)
+		case *CompiledFunction:
+			for _, p := range o.Free {
+				if p.Value != nil {
+					walk(*p.Value)
+				}
+			}
+			iterateInstructions(o.Instructions,
+				func(_ int, op parser.Opcode, operands []int) bool {
+					switch op {
+					case parser.OpGetGlobal, parser.OpSetGlobal,
+						parser.OpSetSelGlobal:
+						idx := operands[0]
+						if !used[idx] && idx < len(v.globals) {
+							used[idx] = true
+							walk(v.globals[idx])
+						}
+					case parser.OpConstant, parser.OpClosure:
+						if idx := operands[0]; idx < len(v.constants) {
+							walk(v.constants[idx])
+						}
+					}
+					return true
+				})
+		}
+	}
+	walk(fn)
+	for _, arg := range args {
+		walk(arg)
+	}
+	return used
+}
+
+func walkMap(m *OrderedMap, walk func(Object)) {
+	if m == nil {
+		return
+	}
+	m.Range(func(_, value Object) bool {
+		walk(value)
+		return true
+	})
+}
+
+func isolate(o Object, seen map[Object]Object) Object {
+	switch o := o.(type) {
+	case *Array:
+		if c, ok := seen[o]; ok {
+			return c
+		}
+		c := &Array{Value: make([]Object, len(o.Value))}
+		if seen != nil {
+			seen[o] = c
+		}
+		for i, e := range o.Value {
+			c.Value[i] = isolate(e, seen)
+		}
+		return c
+	case *ImmutableArray:
+		arr := make([]Object, len(o.Value))
+		for i, e := range o.Value {
+			arr[i] = isolate(e, seen)
+		}
+		return &ImmutableArray{Value: arr}
+	case *Map:
+		if c, ok := seen[o]; ok {
+			return c
+		}
+		c := &Map{}
+		if seen != nil {
+			seen[o] = c
+		}
+		c.Value = isolateMap(o.Value, seen)
+		return c
+	case *ImmutableMap:
+		return &ImmutableMap{Value: isolateMap(o.Value, seen)}
+	case *Set:
+		if c, ok := seen[o]; ok {
+			return c
+		}
+		c := &Set{}
+		if o.Value != nil {
+			c.Value = o.Value.Copy()
+		}
+		if seen != nil {
+			seen[o] = c
+		}
+		return c
+	case *ImmutableSet:
+		return &ImmutableSet{Value: o.Value.Copy()}
+	case *Error:
+		return &Error{Value: isolate(o.Value, seen)}
+	case *CompiledFunction:
+		if seen == nil {
+			seen = make(map[Object]Object)
+		}
+		if c, ok := seen[o]; ok {
+			return c
+		}
+		c := &CompiledFunction{
+			Instructions:  o.Instructions,
+			NumLocals:     o.NumLocals,
+			NumParameters: o.NumParameters,
+			VarArgs:       o.VarArgs,
+			SourceMap:     o.SourceMap,
+			Free:          make([]*ObjectPtr, len(o.Free)),
+			Debug:         o.Debug,
+		}
+		seen[o] = c
+		for i, p := range o.Free {
+			var value Object = UndefinedValue
+			if p.Value != nil && *p.Value != nil {
+				value = isolate(*p.Value, seen)
+			}
+			c.Free[i] = &ObjectPtr{Value: &value}
+		}
+		return c
+	}
+	return o.Copy()
+}
+
+func isolateMap(m *OrderedMap, seen map[Object]Object) *OrderedMap {
+	if m == nil {
+		return nil
+	}
+	c := NewOrderedMap(m.Len())
+	m.Range(func(key, value Object) bool {
+		_ = c.Set(key, isolate(value, seen))
+		return true
+	})
+	return c
+}
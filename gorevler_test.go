@@ -0,0 +1,132 @@
+package lokum
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAbortBlockedChannel(t *testing.T) {
+	sources := []string{
+		`k := kanal(); k.al()`,
+		`k := kanal(); k.gönder(1)`,
+		`k := kanal(); tekrarla x in k {}`,
+		`seç(kanal(), kanal())`,
+		`k := kanal(); bekle(başlat k.al())`,
+		`k := kanal(); bekle(başlat fn() { dön k.al() }())`,
+	}
+	for _, src := range sources {
+		c, err := NewScript([]byte(src)).Compile()
+		if err != nil {
+			t.Fatalf("%s: %v", src, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		done := make(chan error, 1)
+		go func() { done <- c.RunContext(ctx) }()
+		select {
+		case err = <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s: durdurulamadı", src)
+		}
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Fatalf("%s: %v", src, err)
+		}
+	}
+}
+
+func TestRunStopsTasks(t *testing.T) {
+	c, err := NewScript([]byte(`
+k := kanal()
+başlat fn() { k.al() }()
+başlat fn() { tekrarla { } }()
+`)).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := c.newVM(c.globals)
+	done := make(chan error, 1)
+	go func() { done <- v.Run() }()
+	select {
+	case err = <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("görevler durdurulmadı")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.childLock.Lock()
+	n := len(v.children)
+	v.childLock.Unlock()
+	if n != 0 {
+		t.Fatalf("%d görev hâlâ çalışıyor", n)
+	}
+}
+
+func TestIsolateImmutableSet(t *testing.T) {
+	set := &Set{Value: NewOrderedMap(0)}
+	frozen := &ImmutableSet{Value: set.Value}
+	copied := isolate(frozen, nil).(*ImmutableSet)
+	_ = set.Value.Set(&Int{Value: 1}, TrueValue)
+	if copied.Value.Len() != 0 {
+		t.Fatal("değişmez küme paylaşıldı")
+	}
+}
+
+func TestTaskGlobals(t *testing.T) {
+	c, err := NewScript([]byte(`
+büyük := aralık(0, 1000)
+x := 1
+y := 2
+g := fn() { dön x }
+h := fn() { dön g() }
+m := {f: fn() { dön y }}
+sonuç := bekle(başlat fn() { dön h() + m.f() }())
+`)).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Get("sonuç").Int(); n != 3 {
+		t.Fatalf("sonuç = %d, beklenen 3", n)
+	}
+
+	v := c.newVM(c.globals)
+	used := v.taskGlobals(c.Get("h").Value().(*CompiledFunction),
+		[]Object{c.globals[c.globalIndexes["m"]]})
+	want := map[int]bool{}
+	for _, name := range []string{"x", "y", "g"} {
+		want[c.globalIndexes[name]] = true
+	}
+	if !reflect.DeepEqual(used, want) {
+		t.Fatalf("kopyalanan globaller %v, beklenen %v", used, want)
+	}
+}
+
+func TestChannelClosed(t *testing.T) {
+	c, err := NewScript([]byte(`
+k := kanal(2)
+k.gönder(tanımsız)
+k.kapat()
+a := k.al()
+b := k.al()
+s := seç(k)
+`)).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := c.Get("a").Object(); a != UndefinedValue {
+		t.Fatalf("a = %v, beklenen tanımsız", a)
+	}
+	b, ok := c.Get("b").Object().(*Error)
+	if !ok || b.String() != `error: "kanal kapalı"` {
+		t.Fatalf("b = %v, beklenen kanal kapalı hatası", c.Get("b").Object())
+	}
+	s := c.Get("s").Array()
+	if len(s) != 2 || s[0] != int64(0) {
+		t.Fatalf("s = %v", s)
+	}
+	if _, ok := c.Get("s").Object().(*Array).Value[1].(*Error); !ok {
+		t.Fatalf("seç kapalı kanal için %v döndürdü", s[1])
+	}
+}
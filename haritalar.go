@@ -1,1406 +1,2072 @@
-package lokum
-
-import (
-	"bytes"
-	"fmt"
-	"math"
-	"strconv"
-	"strings"
-	"time"
-
-	"github.com/onrirr/lokum/parser"
-	"github.com/onrirr/lokum/token"
-)
-
-var (
-	TrueValue Object = &Bool{value: true}
-
-	FalseValue Object = &Bool{value: false}
-
-	UndefinedValue Object = &Undefined{}
-)
-
-type Object interface {
-	TypeName() string
-
-	String() string
-
-	BinaryOp(op token.Token, rhs Object) (Object, error)
-
-	IsFalsy() bool
-
-	Equals(another Object) bool
-
-	Copy() Object
-
-	IndexGet(index Object) (value Object, err error)
-
-	IndexSet(index, value Object) error
-
-	Iterate() Iterator
-
-	CanIterate() bool
-
-	Call(args ...Object) (ret Object, err error)
-
-	CanCall() bool
-}
-
-type ObjectImpl struct {
-}
-
-func (o *ObjectImpl) TypeName() string {
-	panic(ErrNotImplemented)
-}
-
-func (o *ObjectImpl) String() string {
-	panic(ErrNotImplemented)
-}
-
-func (o *ObjectImpl) BinaryOp(_ token.Token, _ Object) (Object, error) {
-	return nil, ErrInvalidOperator
-}
-
-func (o *ObjectImpl) Copy() Object {
-	return nil
-}
-
-func (o *ObjectImpl) IsFalsy() bool {
-	return false
-}
-
-func (o *ObjectImpl) Equals(x Object) bool {
-	return o == x
-}
-
-func (o *ObjectImpl) IndexGet(_ Object) (res Object, err error) {
-	return nil, ErrNotIndexable
-}
-
-func (o *ObjectImpl) IndexSet(_, _ Object) (err error) {
-	return ErrNotIndexAssignable
-}
-
-func (o *ObjectImpl) Iterate() Iterator {
-	return nil
-}
-
-func (o *ObjectImpl) CanIterate() bool {
-	return false
-}
-
-func (o *ObjectImpl) Call(_ ...Object) (ret Object, err error) {
-	return nil, nil
-}
-
-func (o *ObjectImpl) CanCall() bool {
-	return false
-}
-
-type Array struct {
-	ObjectImpl
-	Value []Object
-}
-
-func (o *Array) TypeName() string {
-	return "array"
-}
-
-func (o *Array) String() string {
-	var elements []string
-	for _, e := range o.Value {
-		elements = append(elements, e.String())
-	}
-	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
-}
-
-func (o *Array) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	if rhs, ok := rhs.(*Array); ok {
-		switch op {
-		case token.Add:
-			if len(rhs.Value) == 0 {
-				return o, nil
-			}
-			return &Array{Value: append(o.Value, rhs.Value...)}, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *Array) Copy() Object {
-	var c []Object
-	for _, elem := range o.Value {
-		c = append(c, elem.Copy())
-	}
-	return &Array{Value: c}
-}
-
-func (o *Array) IsFalsy() bool {
-	return len(o.Value) == 0
-}
-
-func (o *Array) Equals(x Object) bool {
-	var xVal []Object
-	switch x := x.(type) {
-	case *Array:
-		xVal = x.Value
-	case *ImmutableArray:
-		xVal = x.Value
-	default:
-		return false
-	}
-	if len(o.Value) != len(xVal) {
-		return false
-	}
-	for i, e := range o.Value {
-		if !e.Equals(xVal[i]) {
-			return false
-		}
-	}
-	return true
-}
-
-func (o *Array) IndexGet(index Object) (res Object, err error) {
-	intIdx, ok := index.(*Int)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	idxVal := int(intIdx.Value)
-	if idxVal < 0 || idxVal >= len(o.Value) {
-		res = UndefinedValue
-		return
-	}
-	res = o.Value[idxVal]
-	return
-}
-
-func (o *Array) IndexSet(index, value Object) (err error) {
-	intIdx, ok := ToInt(index)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	if intIdx < 0 || intIdx >= len(o.Value) {
-		err = ErrIndexOutOfBounds
-		return
-	}
-	o.Value[intIdx] = value
-	return nil
-}
-
-func (o *Array) Iterate() Iterator {
-	return &ArrayIterator{
-		v: o.Value,
-		l: len(o.Value),
-	}
-}
-
-func (o *Array) CanIterate() bool {
-	return true
-}
-
-type Bool struct {
-	ObjectImpl
-
-	value bool
-}
-
-func (o *Bool) String() string {
-	if o.value {
-		return "true"
-	}
-
-	return "false"
-}
-
-func (o *Bool) TypeName() string {
-	return "bool"
-}
-
-func (o *Bool) Copy() Object {
-	return o
-}
-
-func (o *Bool) IsFalsy() bool {
-	return !o.value
-}
-
-func (o *Bool) Equals(x Object) bool {
-	return o == x
-}
-
-func (o *Bool) GobDecode(b []byte) (err error) {
-	o.value = b[0] == 1
-	return
-}
-
-func (o *Bool) GobEncode() (b []byte, err error) {
-	if o.value {
-		b = []byte{1}
-	} else {
-		b = []byte{0}
-	}
-	return
-}
-
-type BuiltinFunction struct {
-	ObjectImpl
-	Name  string
-	Value CallableFunc
-}
-
-func (o *BuiltinFunction) TypeName() string {
-	return "builtin-function:" + o.Name
-}
-
-func (o *BuiltinFunction) String() string {
-	return "<builtin-function>"
-}
-
-func (o *BuiltinFunction) Copy() Object {
-	return &BuiltinFunction{Value: o.Value}
-}
-
-func (o *BuiltinFunction) Equals(_ Object) bool {
-	return false
-}
-
-func (o *BuiltinFunction) Call(args ...Object) (Object, error) {
-	return o.Value(args...)
-}
-
-func (o *BuiltinFunction) CanCall() bool {
-	return true
-}
-
-type BuiltinModule struct {
-	Attrs map[string]Object
-}
-
-func (m *BuiltinModule) Import(moduleName string) (interface{}, error) {
-	return m.AsImmutableMap(moduleName), nil
-}
-
-func (m *BuiltinModule) AsImmutableMap(moduleName string) *ImmutableMap {
-	attrs := make(map[string]Object, len(m.Attrs))
-	for k, v := range m.Attrs {
-		attrs[k] = v.Copy()
-	}
-	attrs["__module_name__"] = &String{Value: moduleName}
-	return &ImmutableMap{Value: attrs}
-}
-
-type Bytes struct {
-	ObjectImpl
-	Value []byte
-}
-
-func (o *Bytes) String() string {
-	return string(o.Value)
-}
-
-func (o *Bytes) TypeName() string {
-	return "bytes"
-}
-
-func (o *Bytes) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	switch op {
-	case token.Add:
-		switch rhs := rhs.(type) {
-		case *Bytes:
-			if len(o.Value)+len(rhs.Value) > MaxBytesLen {
-				return nil, ErrBytesLimit
-			}
-			return &Bytes{Value: append(o.Value, rhs.Value...)}, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *Bytes) Copy() Object {
-	return &Bytes{Value: append([]byte{}, o.Value...)}
-}
-
-func (o *Bytes) IsFalsy() bool {
-	return len(o.Value) == 0
-}
-
-func (o *Bytes) Equals(x Object) bool {
-	t, ok := x.(*Bytes)
-	if !ok {
-		return false
-	}
-	return bytes.Equal(o.Value, t.Value)
-}
-
-func (o *Bytes) IndexGet(index Object) (res Object, err error) {
-	intIdx, ok := index.(*Int)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	idxVal := int(intIdx.Value)
-	if idxVal < 0 || idxVal >= len(o.Value) {
-		res = UndefinedValue
-		return
-	}
-	res = &Int{Value: int64(o.Value[idxVal])}
-	return
-}
-
-func (o *Bytes) Iterate() Iterator {
-	return &BytesIterator{
-		v: o.Value,
-		l: len(o.Value),
-	}
-}
-
-func (o *Bytes) CanIterate() bool {
-	return true
-}
-
-type Char struct {
-	ObjectImpl
-	Value rune
-}
-
-func (o *Char) String() string {
-	return string(o.Value)
-}
-
-func (o *Char) TypeName() string {
-	return "char"
-}
-
-func (o *Char) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	switch rhs := rhs.(type) {
-	case *Char:
-		switch op {
-		case token.Add:
-			r := o.Value + rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Char{Value: r}, nil
-		case token.Sub:
-			r := o.Value - rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Char{Value: r}, nil
-		case token.Less:
-			if o.Value < rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if o.Value > rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if o.Value <= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if o.Value >= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	case *Int:
-		switch op {
-		case token.Add:
-			r := o.Value + rune(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Char{Value: r}, nil
-		case token.Sub:
-			r := o.Value - rune(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Char{Value: r}, nil
-		case token.Less:
-			if int64(o.Value) < rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if int64(o.Value) > rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if int64(o.Value) <= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if int64(o.Value) >= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *Char) Copy() Object {
-	return &Char{Value: o.Value}
-}
-
-func (o *Char) IsFalsy() bool {
-	return o.Value == 0
-}
-
-func (o *Char) Equals(x Object) bool {
-	t, ok := x.(*Char)
-	if !ok {
-		return false
-	}
-	return o.Value == t.Value
-}
-
-type CompiledFunction struct {
-	ObjectImpl
-	Instructions  []byte
-	NumLocals     int
-	NumParameters int
-	VarArgs       bool
-	SourceMap     map[int]parser.Pos
-	Free          []*ObjectPtr
-}
-
-func (o *CompiledFunction) TypeName() string {
-	return "compiled-function"
-}
-
-func (o *CompiledFunction) String() string {
-	return "<compiled-function>"
-}
-
-func (o *CompiledFunction) Copy() Object {
-	return &CompiledFunction{
-		Instructions:  append([]byte{}, o.Instructions...),
-		NumLocals:     o.NumLocals,
-		NumParameters: o.NumParameters,
-		VarArgs:       o.VarArgs,
-		Free:          append([]*ObjectPtr{}, o.Free...),
-	}
-}
-
-func (o *CompiledFunction) Equals(_ Object) bool {
-	return false
-}
-
-func (o *CompiledFunction) SourcePos(ip int) parser.Pos {
-	for ip >= 0 {
-		if p, ok := o.SourceMap[ip]; ok {
-			return p
-		}
-		ip--
-	}
-	return parser.NoPos
-}
-
-func (o *CompiledFunction) CanCall() bool {
-	return true
-}
-
-type Error struct {
-	ObjectImpl
-	Value Object
-}
-
-func (o *Error) TypeName() string {
-	return "error"
-}
-
-func (o *Error) String() string {
-	if o.Value != nil {
-		return fmt.Sprintf("error: %s", o.Value.String())
-	}
-	return "error"
-}
-
-func (o *Error) IsFalsy() bool {
-	return true
-}
-
-func (o *Error) Copy() Object {
-	return &Error{Value: o.Value.Copy()}
-}
-
-func (o *Error) Equals(x Object) bool {
-	return o == x
-}
-
-func (o *Error) IndexGet(index Object) (res Object, err error) {
-	if strIdx, _ := ToString(index); strIdx != "value" {
-		err = ErrInvalidIndexOnError
-		return
-	}
-	res = o.Value
-	return
-}
-
-type Float struct {
-	ObjectImpl
-	Value float64
-}
-
-func (o *Float) String() string {
-	return strconv.FormatFloat(o.Value, 'f', -1, 64)
-}
-
-func (o *Float) TypeName() string {
-	return "float"
-}
-
-func (o *Float) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	switch rhs := rhs.(type) {
-	case *Float:
-		switch op {
-		case token.Add:
-			r := o.Value + rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Sub:
-			r := o.Value - rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Mul:
-			r := o.Value * rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Quo:
-			r := o.Value / rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Less:
-			if o.Value < rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if o.Value > rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if o.Value <= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if o.Value >= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	case *Int:
-		switch op {
-		case token.Add:
-			r := o.Value + float64(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Sub:
-			r := o.Value - float64(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Mul:
-			r := o.Value * float64(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Quo:
-			r := o.Value / float64(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Float{Value: r}, nil
-		case token.Less:
-			if o.Value < float64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if o.Value > float64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if o.Value <= float64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if o.Value >= float64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *Float) Copy() Object {
-	return &Float{Value: o.Value}
-}
-
-func (o *Float) IsFalsy() bool {
-	return math.IsNaN(o.Value)
-}
-
-func (o *Float) Equals(x Object) bool {
-	t, ok := x.(*Float)
-	if !ok {
-		return false
-	}
-	return o.Value == t.Value
-}
-
-type ImmutableArray struct {
-	ObjectImpl
-	Value []Object
-}
-
-func (o *ImmutableArray) TypeName() string {
-	return "immutable-array"
-}
-
-func (o *ImmutableArray) String() string {
-	var elements []string
-	for _, e := range o.Value {
-		elements = append(elements, e.String())
-	}
-	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
-}
-
-func (o *ImmutableArray) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	if rhs, ok := rhs.(*ImmutableArray); ok {
-		switch op {
-		case token.Add:
-			return &Array{Value: append(o.Value, rhs.Value...)}, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *ImmutableArray) Copy() Object {
-	var c []Object
-	for _, elem := range o.Value {
-		c = append(c, elem.Copy())
-	}
-	return &Array{Value: c}
-}
-
-func (o *ImmutableArray) IsFalsy() bool {
-	return len(o.Value) == 0
-}
-
-func (o *ImmutableArray) Equals(x Object) bool {
-	var xVal []Object
-	switch x := x.(type) {
-	case *Array:
-		xVal = x.Value
-	case *ImmutableArray:
-		xVal = x.Value
-	default:
-		return false
-	}
-	if len(o.Value) != len(xVal) {
-		return false
-	}
-	for i, e := range o.Value {
-		if !e.Equals(xVal[i]) {
-			return false
-		}
-	}
-	return true
-}
-
-func (o *ImmutableArray) IndexGet(index Object) (res Object, err error) {
-	intIdx, ok := index.(*Int)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	idxVal := int(intIdx.Value)
-	if idxVal < 0 || idxVal >= len(o.Value) {
-		res = UndefinedValue
-		return
-	}
-	res = o.Value[idxVal]
-	return
-}
-
-func (o *ImmutableArray) Iterate() Iterator {
-	return &ArrayIterator{
-		v: o.Value,
-		l: len(o.Value),
-	}
-}
-
-func (o *ImmutableArray) CanIterate() bool {
-	return true
-}
-
-type ImmutableMap struct {
-	ObjectImpl
-	Value map[string]Object
-}
-
-func (o *ImmutableMap) TypeName() string {
-	return "immutable-map"
-}
-
-func (o *ImmutableMap) String() string {
-	var pairs []string
-	for k, v := range o.Value {
-		pairs = append(pairs, fmt.Sprintf("%s: %s", k, v.String()))
-	}
-	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
-}
-
-func (o *ImmutableMap) Copy() Object {
-	c := make(map[string]Object)
-	for k, v := range o.Value {
-		c[k] = v.Copy()
-	}
-	return &Map{Value: c}
-}
-
-func (o *ImmutableMap) IsFalsy() bool {
-	return len(o.Value) == 0
-}
-
-func (o *ImmutableMap) IndexGet(index Object) (res Object, err error) {
-	strIdx, ok := ToString(index)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	res, ok = o.Value[strIdx]
-	if !ok {
-		res = UndefinedValue
-	}
-	return
-}
-
-func (o *ImmutableMap) Equals(x Object) bool {
-	var xVal map[string]Object
-	switch x := x.(type) {
-	case *Map:
-		xVal = x.Value
-	case *ImmutableMap:
-		xVal = x.Value
-	default:
-		return false
-	}
-	if len(o.Value) != len(xVal) {
-		return false
-	}
-	for k, v := range o.Value {
-		tv := xVal[k]
-		if !v.Equals(tv) {
-			return false
-		}
-	}
-	return true
-}
-
-func (o *ImmutableMap) Iterate() Iterator {
-	var keys []string
-	for k := range o.Value {
-		keys = append(keys, k)
-	}
-	return &MapIterator{
-		v: o.Value,
-		k: keys,
-		l: len(keys),
-	}
-}
-
-func (o *ImmutableMap) CanIterate() bool {
-	return true
-}
-
-type Int struct {
-	ObjectImpl
-	Value int64
-}
-
-func (o *Int) String() string {
-	return strconv.FormatInt(o.Value, 10)
-}
-
-func (o *Int) TypeName() string {
-	return "int"
-}
-
-func (o *Int) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	switch rhs := rhs.(type) {
-	case *Int:
-		switch op {
-		case token.Add:
-			r := o.Value + rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Sub:
-			r := o.Value - rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Mul:
-			r := o.Value * rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Quo:
-			r := o.Value / rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Rem:
-			r := o.Value % rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.And:
-			r := o.Value & rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Or:
-			r := o.Value | rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Xor:
-			r := o.Value ^ rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.AndNot:
-			r := o.Value &^ rhs.Value
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Shl:
-			r := o.Value << uint64(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Shr:
-			r := o.Value >> uint64(rhs.Value)
-			if r == o.Value {
-				return o, nil
-			}
-			return &Int{Value: r}, nil
-		case token.Less:
-			if o.Value < rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if o.Value > rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if o.Value <= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if o.Value >= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	case *Float:
-		switch op {
-		case token.Add:
-			return &Float{Value: float64(o.Value) + rhs.Value}, nil
-		case token.Sub:
-			return &Float{Value: float64(o.Value) - rhs.Value}, nil
-		case token.Mul:
-			return &Float{Value: float64(o.Value) * rhs.Value}, nil
-		case token.Quo:
-			return &Float{Value: float64(o.Value) / rhs.Value}, nil
-		case token.Less:
-			if float64(o.Value) < rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if float64(o.Value) > rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if float64(o.Value) <= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if float64(o.Value) >= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	case *Char:
-		switch op {
-		case token.Add:
-			return &Char{Value: rune(o.Value) + rhs.Value}, nil
-		case token.Sub:
-			return &Char{Value: rune(o.Value) - rhs.Value}, nil
-		case token.Less:
-			if o.Value < int64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if o.Value > int64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if o.Value <= int64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if o.Value >= int64(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *Int) Copy() Object {
-	return &Int{Value: o.Value}
-}
-
-func (o *Int) IsFalsy() bool {
-	return o.Value == 0
-}
-
-func (o *Int) Equals(x Object) bool {
-	t, ok := x.(*Int)
-	if !ok {
-		return false
-	}
-	return o.Value == t.Value
-}
-
-type Map struct {
-	ObjectImpl
-	Value map[string]Object
-}
-
-func (o *Map) TypeName() string {
-	return "map"
-}
-
-func (o *Map) String() string {
-	var pairs []string
-	for k, v := range o.Value {
-		pairs = append(pairs, fmt.Sprintf("%s: %s", k, v.String()))
-	}
-	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
-}
-
-func (o *Map) Copy() Object {
-	c := make(map[string]Object)
-	for k, v := range o.Value {
-		c[k] = v.Copy()
-	}
-	return &Map{Value: c}
-}
-
-func (o *Map) IsFalsy() bool {
-	return len(o.Value) == 0
-}
-
-func (o *Map) Equals(x Object) bool {
-	var xVal map[string]Object
-	switch x := x.(type) {
-	case *Map:
-		xVal = x.Value
-	case *ImmutableMap:
-		xVal = x.Value
-	default:
-		return false
-	}
-	if len(o.Value) != len(xVal) {
-		return false
-	}
-	for k, v := range o.Value {
-		tv := xVal[k]
-		if !v.Equals(tv) {
-			return false
-		}
-	}
-	return true
-}
-
-func (o *Map) IndexGet(index Object) (res Object, err error) {
-	strIdx, ok := ToString(index)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	res, ok = o.Value[strIdx]
-	if !ok {
-		res = UndefinedValue
-	}
-	return
-}
-
-func (o *Map) IndexSet(index, value Object) (err error) {
-	strIdx, ok := ToString(index)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	o.Value[strIdx] = value
-	return nil
-}
-
-func (o *Map) Iterate() Iterator {
-	var keys []string
-	for k := range o.Value {
-		keys = append(keys, k)
-	}
-	return &MapIterator{
-		v: o.Value,
-		k: keys,
-		l: len(keys),
-	}
-}
-
-func (o *Map) CanIterate() bool {
-	return true
-}
-
-type ObjectPtr struct {
-	ObjectImpl
-	Value *Object
-}
-
-func (o *ObjectPtr) String() string {
-	return "free-var"
-}
-
-func (o *ObjectPtr) TypeName() string {
-	return "<free-var>"
-}
-
-func (o *ObjectPtr) Copy() Object {
-	return o
-}
-
-func (o *ObjectPtr) IsFalsy() bool {
-	return o.Value == nil
-}
-
-func (o *ObjectPtr) Equals(x Object) bool {
-	return o == x
-}
-
-type String struct {
-	ObjectImpl
-	Value   string
-	runeStr []rune
-}
-
-func (o *String) TypeName() string {
-	return "string"
-}
-
-func (o *String) String() string {
-	return strconv.Quote(o.Value)
-}
-
-func (o *String) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	switch op {
-	case token.Add:
-		switch rhs := rhs.(type) {
-		case *String:
-			if len(o.Value)+len(rhs.Value) > MaxStringLen {
-				return nil, ErrStringLimit
-			}
-			return &String{Value: o.Value + rhs.Value}, nil
-		default:
-			rhsStr := rhs.String()
-			if len(o.Value)+len(rhsStr) > MaxStringLen {
-				return nil, ErrStringLimit
-			}
-			return &String{Value: o.Value + rhsStr}, nil
-		}
-	case token.Less:
-		switch rhs := rhs.(type) {
-		case *String:
-			if o.Value < rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	case token.LessEq:
-		switch rhs := rhs.(type) {
-		case *String:
-			if o.Value <= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	case token.Greater:
-		switch rhs := rhs.(type) {
-		case *String:
-			if o.Value > rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	case token.GreaterEq:
-		switch rhs := rhs.(type) {
-		case *String:
-			if o.Value >= rhs.Value {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *String) IsFalsy() bool {
-	return len(o.Value) == 0
-}
-
-func (o *String) Copy() Object {
-	return &String{Value: o.Value}
-}
-
-func (o *String) Equals(x Object) bool {
-	t, ok := x.(*String)
-	if !ok {
-		return false
-	}
-	return o.Value == t.Value
-}
-
-func (o *String) IndexGet(index Object) (res Object, err error) {
-	intIdx, ok := index.(*Int)
-	if !ok {
-		err = ErrInvalidIndexType
-		return
-	}
-	idxVal := int(intIdx.Value)
-	if o.runeStr == nil {
-		o.runeStr = []rune(o.Value)
-	}
-	if idxVal < 0 || idxVal >= len(o.runeStr) {
-		res = UndefinedValue
-		return
-	}
-	res = &Char{Value: o.runeStr[idxVal]}
-	return
-}
-
-func (o *String) Iterate() Iterator {
-	if o.runeStr == nil {
-		o.runeStr = []rune(o.Value)
-	}
-	return &StringIterator{
-		v: o.runeStr,
-		l: len(o.runeStr),
-	}
-}
-
-func (o *String) CanIterate() bool {
-	return true
-}
-
-type Time struct {
-	ObjectImpl
-	Value time.Time
-}
-
-func (o *Time) String() string {
-	return o.Value.String()
-}
-
-func (o *Time) TypeName() string {
-	return "time"
-}
-
-func (o *Time) BinaryOp(op token.Token, rhs Object) (Object, error) {
-	switch rhs := rhs.(type) {
-	case *Int:
-		switch op {
-		case token.Add:
-			if rhs.Value == 0 {
-				return o, nil
-			}
-			return &Time{Value: o.Value.Add(time.Duration(rhs.Value))}, nil
-		case token.Sub:
-			if rhs.Value == 0 {
-				return o, nil
-			}
-			return &Time{Value: o.Value.Add(time.Duration(-rhs.Value))}, nil
-		}
-	case *Time:
-		switch op {
-		case token.Sub:
-			return &Int{Value: int64(o.Value.Sub(rhs.Value))}, nil
-		case token.Less:
-			if o.Value.Before(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.Greater:
-			if o.Value.After(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.LessEq:
-			if o.Value.Equal(rhs.Value) || o.Value.Before(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		case token.GreaterEq:
-			if o.Value.Equal(rhs.Value) || o.Value.After(rhs.Value) {
-				return TrueValue, nil
-			}
-			return FalseValue, nil
-		}
-	}
-	return nil, ErrInvalidOperator
-}
-
-func (o *Time) Copy() Object {
-	return &Time{Value: o.Value}
-}
-
-func (o *Time) IsFalsy() bool {
-	return o.Value.IsZero()
-}
-
-func (o *Time) Equals(x Object) bool {
-	t, ok := x.(*Time)
-	if !ok {
-		return false
-	}
-	return o.Value.Equal(t.Value)
-}
-
-type Undefined struct {
-	ObjectImpl
-}
-
-func (o *Undefined) TypeName() string {
-	return "undefined"
-}
-
-func (o *Undefined) String() string {
-	return "<undefined>"
-}
-
-func (o *Undefined) Copy() Object {
-	return o
-}
-
-func (o *Undefined) IsFalsy() bool {
-	return true
-}
-
-func (o *Undefined) Equals(x Object) bool {
-	return o == x
-}
-
-func (o *Undefined) IndexGet(_ Object) (Object, error) {
-	return UndefinedValue, nil
-}
-
-func (o *Undefined) Iterate() Iterator {
-	return o
-}
-
-func (o *Undefined) CanIterate() bool {
-	return true
-}
-
-func (o *Undefined) Next() bool {
-	return false
-}
-
-func (o *Undefined) Key() Object {
-	return o
-}
-
-func (o *Undefined) Value() Object {
-	return o
-}
-
-type UserFunction struct {
-	ObjectImpl
-	Name  string
-	Value CallableFunc
-}
-
-func (o *UserFunction) TypeName() string {
-	return "user-function:" + o.Name
-}
-
-func (o *UserFunction) String() string {
-	return "<user-function>"
-}
-
-func (o *UserFunction) Copy() Object {
-	return &UserFunction{Value: o.Value, Name: o.Name}
-}
-
-func (o *UserFunction) Equals(_ Object) bool {
-	return false
-}
-
-func (o *UserFunction) Call(args ...Object) (Object, error) {
-	return o.Value(args...)
-}
-
-func (o *UserFunction) CanCall() bool {
-	return true
-}
+package lokum
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/token"
+)
+
+var (
+	TrueValue Object = &Bool{value: true}
+
+	FalseValue Object = &Bool{value: false}
+
+	UndefinedValue Object = &Undefined{}
+)
+
+type Object interface {
+	TypeName() string
+
+	String() string
+
+	BinaryOp(op token.Token, rhs Object) (Object, error)
+
+	IsFalsy() bool
+
+	Equals(another Object) bool
+
+	Copy() Object
+
+	IndexGet(index Object) (value Object, err error)
+
+	IndexSet(index, value Object) error
+
+	Iterate() Iterator
+
+	CanIterate() bool
+
+	Call(args ...Object) (ret Object, err error)
+
+	CanCall() bool
+}
+
+type ObjectImpl struct {
+}
+
+func (o *ObjectImpl) TypeName() string {
+	panic(ErrNotImplemented)
+}
+
+func (o *ObjectImpl) String() string {
+	panic(ErrNotImplemented)
+}
+
+func (o *ObjectImpl) BinaryOp(_ token.Token, _ Object) (Object, error) {
+	return nil, ErrInvalidOperator
+}
+
+func (o *ObjectImpl) Copy() Object {
+	return nil
+}
+
+func (o *ObjectImpl) IsFalsy() bool {
+	return false
+}
+
+func (o *ObjectImpl) Equals(x Object) bool {
+	return o == x
+}
+
+func (o *ObjectImpl) IndexGet(_ Object) (res Object, err error) {
+	return nil, ErrNotIndexable
+}
+
+func (o *ObjectImpl) IndexSet(_, _ Object) (err error) {
+	return ErrNotIndexAssignable
+}
+
+func (o *ObjectImpl) Iterate() Iterator {
+	return nil
+}
+
+func (o *ObjectImpl) CanIterate() bool {
+	return false
+}
+
+func (o *ObjectImpl) Call(_ ...Object) (ret Object, err error) {
+	return nil, nil
+}
+
+func (o *ObjectImpl) CanCall() bool {
+	return false
+}
+
+type Array struct {
+	ObjectImpl
+	Value []Object
+}
+
+func (o *Array) TypeName() string {
+	return "array"
+}
+
+func (o *Array) String() string {
+	var elements []string
+	for _, e := range o.Value {
+		elements = append(elements, e.String())
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+func (o *Array) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	if rhs, ok := rhs.(*Array); ok {
+		switch op {
+		case token.Add:
+			if len(rhs.Value) == 0 {
+				return o, nil
+			}
+			return &Array{Value: append(o.Value, rhs.Value...)}, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *Array) Copy() Object {
+	var c []Object
+	for _, elem := range o.Value {
+		c = append(c, elem.Copy())
+	}
+	return &Array{Value: c}
+}
+
+func (o *Array) IsFalsy() bool {
+	return len(o.Value) == 0
+}
+
+func (o *Array) Equals(x Object) bool {
+	var xVal []Object
+	switch x := x.(type) {
+	case *Array:
+		xVal = x.Value
+	case *ImmutableArray:
+		xVal = x.Value
+	default:
+		return false
+	}
+	if len(o.Value) != len(xVal) {
+		return false
+	}
+	for i, e := range o.Value {
+		if !e.Equals(xVal[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *Array) IndexGet(index Object) (res Object, err error) {
+	intIdx, ok := index.(*Int)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	idxVal := int(intIdx.Value)
+	if idxVal < 0 || idxVal >= len(o.Value) {
+		res = UndefinedValue
+		return
+	}
+	res = o.Value[idxVal]
+	return
+}
+
+func (o *Array) IndexSet(index, value Object) (err error) {
+	intIdx, ok := ToInt(index)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	if intIdx < 0 || intIdx >= len(o.Value) {
+		err = ErrIndexOutOfBounds
+		return
+	}
+	o.Value[intIdx] = value
+	return nil
+}
+
+func (o *Array) Iterate() Iterator {
+	return &ArrayIterator{
+		v: o.Value,
+		l: len(o.Value),
+	}
+}
+
+func (o *Array) CanIterate() bool {
+	return true
+}
+
+type Bool struct {
+	ObjectImpl
+
+	value bool
+}
+
+func (o *Bool) String() string {
+	if o.value {
+		return "true"
+	}
+
+	return "false"
+}
+
+func (o *Bool) TypeName() string {
+	return "bool"
+}
+
+func (o *Bool) Copy() Object {
+	return o
+}
+
+func (o *Bool) IsFalsy() bool {
+	return !o.value
+}
+
+func (o *Bool) Equals(x Object) bool {
+	return o == x
+}
+
+func (o *Bool) GobDecode(b []byte) (err error) {
+	o.value = b[0] == 1
+	return
+}
+
+func (o *Bool) GobEncode() (b []byte, err error) {
+	if o.value {
+		b = []byte{1}
+	} else {
+		b = []byte{0}
+	}
+	return
+}
+
+type BuiltinFunction struct {
+	ObjectImpl
+	Name     string
+	Value    CallableFunc
+	Interop  InteropFunc
+	receiver Object
+}
+
+func (o *BuiltinFunction) TypeName() string {
+	return "builtin-function:" + o.Name
+}
+
+func (o *BuiltinFunction) String() string {
+	return "<builtin-function>"
+}
+
+func (o *BuiltinFunction) Copy() Object {
+	return &BuiltinFunction{
+		Name:     o.Name,
+		Value:    o.Value,
+		Interop:  o.Interop,
+		receiver: o.receiver,
+	}
+}
+
+func (o *BuiltinFunction) Equals(_ Object) bool {
+	return false
+}
+
+func (o *BuiltinFunction) Call(args ...Object) (Object, error) {
+	if o.Value == nil {
+		return nil, ErrNoInterop
+	}
+	return o.Value(args...)
+}
+
+func (o *BuiltinFunction) CanCall() bool {
+	return true
+}
+
+type BuiltinModule struct {
+	Attrs map[string]Object
+}
+
+func (m *BuiltinModule) Import(moduleName string) (interface{}, error) {
+	return m.AsImmutableMap(moduleName), nil
+}
+
+func (m *BuiltinModule) AsImmutableMap(moduleName string) *ImmutableMap {
+	attrs := orderedMapFromStrings(m.Attrs)
+	attrs.Range(func(key, value Object) bool {
+		_ = attrs.Set(key, value.Copy())
+		return true
+	})
+	attrs.SetString("__module_name__", &String{Value: moduleName})
+	return &ImmutableMap{Value: attrs}
+}
+
+type Bytes struct {
+	ObjectImpl
+	Value []byte
+}
+
+func (o *Bytes) String() string {
+	return string(o.Value)
+}
+
+func (o *Bytes) TypeName() string {
+	return "bytes"
+}
+
+func (o *Bytes) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	switch op {
+	case token.Add:
+		switch rhs := rhs.(type) {
+		case *Bytes:
+			if len(o.Value)+len(rhs.Value) > MaxBytesLen {
+				return nil, ErrBytesLimit
+			}
+			return &Bytes{Value: append(o.Value, rhs.Value...)}, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *Bytes) Copy() Object {
+	return &Bytes{Value: append([]byte{}, o.Value...)}
+}
+
+func (o *Bytes) IsFalsy() bool {
+	return len(o.Value) == 0
+}
+
+func (o *Bytes) Equals(x Object) bool {
+	t, ok := x.(*Bytes)
+	if !ok {
+		return false
+	}
+	return bytes.Equal(o.Value, t.Value)
+}
+
+func (o *Bytes) IndexGet(index Object) (res Object, err error) {
+	intIdx, ok := index.(*Int)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	idxVal := int(intIdx.Value)
+	if idxVal < 0 || idxVal >= len(o.Value) {
+		res = UndefinedValue
+		return
+	}
+	res = &Int{Value: int64(o.Value[idxVal])}
+	return
+}
+
+func (o *Bytes) Iterate() Iterator {
+	return &BytesIterator{
+		v: o.Value,
+		l: len(o.Value),
+	}
+}
+
+func (o *Bytes) CanIterate() bool {
+	return true
+}
+
+type Channel struct {
+	ObjectImpl
+	Value chan Object
+}
+
+func (o *Channel) TypeName() string {
+	return "channel"
+}
+
+func (o *Channel) String() string {
+	return fmt.Sprintf("<kanal %d/%d>", len(o.Value), cap(o.Value))
+}
+
+func (o *Channel) Copy() Object {
+	return o
+}
+
+func (o *Channel) IsFalsy() bool {
+	return false
+}
+
+func (o *Channel) Equals(x Object) bool {
+	return o == x
+}
+
+func (o *Channel) IndexGet(index Object) (res Object, err error) {
+	name, ok := index.(*String)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	switch name.Value {
+	case "gönder", "al", "kapat":
+		return channelMethod(o, name.Value), nil
+	}
+	err = fmt.Errorf("kanal metodu bulunamadı: %s", name.Value)
+	return
+}
+
+func (o *Channel) Iterate() Iterator {
+	return &ChannelIterator{v: o.Value}
+}
+
+func (o *Channel) CanIterate() bool {
+	return true
+}
+
+func (o *Channel) Send(value Object) error {
+	return o.send(nil, value)
+}
+
+func (o *Channel) send(done <-chan struct{}, value Object) (err error) {
+	defer func() {
+		if recover() != nil {
+			err = ErrChannelClosed
+		}
+	}()
+	select {
+	case o.Value <- isolate(value, nil):
+		return nil
+	case <-done:
+		return ErrVMAborted
+	}
+}
+
+func (o *Channel) Receive() Object {
+	value, _ := o.receive(nil)
+	return value
+}
+
+func (o *Channel) receive(done <-chan struct{}) (Object, error) {
+	select {
+	case value, ok := <-o.Value:
+		if !ok {
+			return channelClosedValue(), nil
+		}
+		return value, nil
+	case <-done:
+		return nil, ErrVMAborted
+	}
+}
+
+func (o *Channel) Close() (err error) {
+	defer func() {
+		if recover() != nil {
+			err = ErrChannelClosed
+		}
+	}()
+	close(o.Value)
+	return nil
+}
+
+func channelClosedValue() Object {
+	return &Error{Value: &String{Value: ErrChannelClosed.Error()}}
+}
+
+func channelMethod(o *Channel, name string) *BuiltinFunction {
+	var fn InteropFunc
+	switch name {
+	case "gönder":
+		fn = func(vm Interop, args ...Object) (Object, error) {
+			done := abortChan(vm)
+			for _, arg := range args {
+				if err := o.send(done, arg); err != nil {
+					return nil, err
+				}
+			}
+			return UndefinedValue, nil
+		}
+	case "al":
+		fn = func(vm Interop, args ...Object) (Object, error) {
+			if len(args) != 0 {
+				return nil, ErrWrongNumArguments
+			}
+			return o.receive(abortChan(vm))
+		}
+	case "kapat":
+		fn = func(vm Interop, args ...Object) (Object, error) {
+			if len(args) != 0 {
+				return nil, ErrWrongNumArguments
+			}
+			return UndefinedValue, o.Close()
+		}
+	}
+	return &BuiltinFunction{
+		Name: name,
+		Value: func(args ...Object) (Object, error) {
+			return fn(nil, args...)
+		},
+		Interop: fn,
+	}
+}
+
+func abortChan(vm Interop) <-chan struct{} {
+	if v, ok := vm.(*VM); ok {
+		return v.aborted()
+	}
+	return nil
+}
+
+type Char struct {
+	ObjectImpl
+	Value rune
+}
+
+func (o *Char) String() string {
+	return string(o.Value)
+}
+
+func (o *Char) TypeName() string {
+	return "char"
+}
+
+func (o *Char) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	switch rhs := rhs.(type) {
+	case *Char:
+		switch op {
+		case token.Add:
+			r := o.Value + rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Char{Value: r}, nil
+		case token.Sub:
+			r := o.Value - rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Char{Value: r}, nil
+		case token.Less:
+			if o.Value < rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if o.Value > rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if o.Value <= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if o.Value >= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case *Int:
+		switch op {
+		case token.Add:
+			r := o.Value + rune(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Char{Value: r}, nil
+		case token.Sub:
+			r := o.Value - rune(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Char{Value: r}, nil
+		case token.Less:
+			if int64(o.Value) < rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if int64(o.Value) > rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if int64(o.Value) <= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if int64(o.Value) >= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *Char) Copy() Object {
+	return &Char{Value: o.Value}
+}
+
+func (o *Char) IsFalsy() bool {
+	return o.Value == 0
+}
+
+func (o *Char) Equals(x Object) bool {
+	t, ok := x.(*Char)
+	if !ok {
+		return false
+	}
+	return o.Value == t.Value
+}
+
+type CompiledFunction struct {
+	ObjectImpl
+	Instructions  []byte
+	NumLocals     int
+	NumParameters int
+	VarArgs       bool
+	SourceMap     map[int]parser.Pos
+	Free          []*ObjectPtr
+	Debug         *DebugInfo
+}
+
+func (o *CompiledFunction) TypeName() string {
+	return "compiled-function"
+}
+
+func (o *CompiledFunction) String() string {
+	return "<compiled-function>"
+}
+
+func (o *CompiledFunction) Copy() Object {
+	return &CompiledFunction{
+		Instructions:  append([]byte{}, o.Instructions...),
+		NumLocals:     o.NumLocals,
+		NumParameters: o.NumParameters,
+		VarArgs:       o.VarArgs,
+		Free:          append([]*ObjectPtr{}, o.Free...),
+		Debug:         o.Debug,
+	}
+}
+
+func (o *CompiledFunction) Equals(_ Object) bool {
+	return false
+}
+
+func (o *CompiledFunction) SourcePos(ip int) parser.Pos {
+	for ip >= 0 {
+		if p, ok := o.SourceMap[ip]; ok {
+			return p
+		}
+		ip--
+	}
+	return parser.NoPos
+}
+
+func (o *CompiledFunction) CanCall() bool {
+	return true
+}
+
+type Error struct {
+	ObjectImpl
+	Value Object
+}
+
+func (o *Error) TypeName() string {
+	return "error"
+}
+
+func (o *Error) String() string {
+	if o.Value != nil {
+		return fmt.Sprintf("error: %s", o.Value.String())
+	}
+	return "error"
+}
+
+func (o *Error) IsFalsy() bool {
+	return true
+}
+
+func (o *Error) Copy() Object {
+	return &Error{Value: o.Value.Copy()}
+}
+
+func (o *Error) Equals(x Object) bool {
+	return o == x
+}
+
+func (o *Error) IndexGet(index Object) (res Object, err error) {
+	if strIdx, _ := ToString(index); strIdx != "value" {
+		err = ErrInvalidIndexOnError
+		return
+	}
+	res = o.Value
+	return
+}
+
+type Float struct {
+	ObjectImpl
+	Value float64
+}
+
+func (o *Float) String() string {
+	return strconv.FormatFloat(o.Value, 'f', -1, 64)
+}
+
+func (o *Float) TypeName() string {
+	return "float"
+}
+
+func (o *Float) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	switch rhs := rhs.(type) {
+	case *Float:
+		switch op {
+		case token.Add:
+			r := o.Value + rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Sub:
+			r := o.Value - rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Mul:
+			r := o.Value * rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Quo:
+			r := o.Value / rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Less:
+			if o.Value < rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if o.Value > rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if o.Value <= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if o.Value >= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case *Int:
+		switch op {
+		case token.Add:
+			r := o.Value + float64(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Sub:
+			r := o.Value - float64(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Mul:
+			r := o.Value * float64(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Quo:
+			r := o.Value / float64(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Float{Value: r}, nil
+		case token.Less:
+			if o.Value < float64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if o.Value > float64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if o.Value <= float64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if o.Value >= float64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *Float) Copy() Object {
+	return &Float{Value: o.Value}
+}
+
+func (o *Float) IsFalsy() bool {
+	return math.IsNaN(o.Value)
+}
+
+func (o *Float) Equals(x Object) bool {
+	t, ok := x.(*Float)
+	if !ok {
+		return false
+	}
+	return o.Value == t.Value
+}
+
+type GoValue struct {
+	ObjectImpl
+	Value reflect.Value
+}
+
+func (o *GoValue) TypeName() string {
+	return "go:" + o.Value.Type().String()
+}
+
+func (o *GoValue) String() string {
+	if !o.Value.CanInterface() {
+		return "<" + o.TypeName() + ">"
+	}
+	return fmt.Sprint(o.Value.Interface())
+}
+
+func (o *GoValue) Copy() Object {
+	v := o.Value
+	switch v.Kind() {
+	case reflect.Struct, reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		return &GoValue{Value: c}
+	case reflect.Slice:
+		if v.IsNil() {
+			return o
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		return &GoValue{Value: c}
+	case reflect.Map:
+		if v.IsNil() {
+			return o
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			c.SetMapIndex(k, v.MapIndex(k))
+		}
+		return &GoValue{Value: c}
+	}
+	return o
+}
+
+func (o *GoValue) IsFalsy() bool {
+	v := o.Value
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Func:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+func (o *GoValue) Equals(x Object) bool {
+	t, ok := x.(*GoValue)
+	if !ok || !o.Value.CanInterface() || !t.Value.CanInterface() {
+		return false
+	}
+	return reflect.DeepEqual(o.Value.Interface(), t.Value.Interface())
+}
+
+func (o *GoValue) IndexGet(index Object) (Object, error) {
+	v := indirect(o.Value)
+	switch v.Kind() {
+	case reflect.Struct:
+		name, ok := index.(*String)
+		if !ok {
+			return nil, ErrInvalidIndexType
+		}
+		if idx, ok := structFields(v.Type()).index[name.Value]; ok {
+			return fromValue(v.FieldByIndex(idx))
+		}
+	case reflect.Slice, reflect.Array:
+		if i, ok := index.(*Int); ok {
+			if i.Value < 0 || i.Value >= int64(v.Len()) {
+				return UndefinedValue, nil
+			}
+			return fromValue(v.Index(int(i.Value)))
+		}
+	case reflect.Map:
+		key, ok := toValue(index, v.Type().Key())
+		if ok {
+			if e := v.MapIndex(key); e.IsValid() {
+				return fromValue(e)
+			}
+		}
+	}
+	if name, ok := index.(*String); ok {
+		if m := o.method(name.Value); m.IsValid() {
+			return reflectFunc(name.Value, m), nil
+		}
+		return UndefinedValue, nil
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return nil, ErrInvalidIndexType
+	}
+	return nil, ErrNotIndexable
+}
+
+func (o *GoValue) IndexSet(index, value Object) error {
+	v := indirect(o.Value)
+	switch v.Kind() {
+	case reflect.Struct:
+		name, ok := index.(*String)
+		if !ok {
+			return ErrInvalidIndexType
+		}
+		idx, ok := structFields(v.Type()).index[name.Value]
+		if !ok {
+			return ErrInvalidIndexType
+		}
+		f := v.FieldByIndex(idx)
+		if !f.CanSet() {
+			return ErrNotIndexAssignable
+		}
+		e, ok := toValue(value, f.Type())
+		if !ok {
+			return ErrInvalidIndexValueType
+		}
+		f.Set(e)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, ok := ToInt(index)
+		if !ok {
+			return ErrInvalidIndexType
+		}
+		if i < 0 || i >= v.Len() {
+			return ErrIndexOutOfBounds
+		}
+		f := v.Index(i)
+		if !f.CanSet() {
+			return ErrNotIndexAssignable
+		}
+		e, ok := toValue(value, f.Type())
+		if !ok {
+			return ErrInvalidIndexValueType
+		}
+		f.Set(e)
+		return nil
+	case reflect.Map:
+		if v.IsNil() {
+			return ErrNotIndexAssignable
+		}
+		key, ok := toValue(index, v.Type().Key())
+		if !ok {
+			return ErrInvalidIndexType
+		}
+		e, ok := toValue(value, v.Type().Elem())
+		if !ok {
+			return ErrInvalidIndexValueType
+		}
+		v.SetMapIndex(key, e)
+		return nil
+	}
+	return ErrNotIndexAssignable
+}
+
+func (o *GoValue) CanIterate() bool {
+	switch indirect(o.Value).Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func (o *GoValue) Iterate() Iterator {
+	v := indirect(o.Value)
+	var keys []Object
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, name := range structFields(v.Type()).names {
+			keys = append(keys, &String{Value: name})
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			keys = append(keys, &Int{Value: int64(i)})
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			if key, err := fromValue(k); err == nil {
+				keys = append(keys, key)
+			}
+		}
+		sortKeys(keys)
+	}
+	return &GoValueIterator{v: o, k: keys, l: len(keys)}
+}
+
+func (o *GoValue) method(name string) reflect.Value {
+	v := o.Value
+	if m := v.MethodByName(name); m.IsValid() {
+		return m
+	}
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		return v.Addr().MethodByName(name)
+	}
+	return reflect.Value{}
+}
+
+func (o *GoValue) length() (int, bool) {
+	v := indirect(o.Value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return v.Len(), true
+	}
+	return 0, false
+}
+
+type ImmutableArray struct {
+	ObjectImpl
+	Value []Object
+}
+
+func (o *ImmutableArray) TypeName() string {
+	return "immutable-array"
+}
+
+func (o *ImmutableArray) String() string {
+	var elements []string
+	for _, e := range o.Value {
+		elements = append(elements, e.String())
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+func (o *ImmutableArray) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	if rhs, ok := rhs.(*ImmutableArray); ok {
+		switch op {
+		case token.Add:
+			return &Array{Value: append(o.Value, rhs.Value...)}, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *ImmutableArray) Copy() Object {
+	var c []Object
+	for _, elem := range o.Value {
+		c = append(c, elem.Copy())
+	}
+	return &Array{Value: c}
+}
+
+func (o *ImmutableArray) IsFalsy() bool {
+	return len(o.Value) == 0
+}
+
+func (o *ImmutableArray) Equals(x Object) bool {
+	var xVal []Object
+	switch x := x.(type) {
+	case *Array:
+		xVal = x.Value
+	case *ImmutableArray:
+		xVal = x.Value
+	default:
+		return false
+	}
+	if len(o.Value) != len(xVal) {
+		return false
+	}
+	for i, e := range o.Value {
+		if !e.Equals(xVal[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *ImmutableArray) IndexGet(index Object) (res Object, err error) {
+	intIdx, ok := index.(*Int)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	idxVal := int(intIdx.Value)
+	if idxVal < 0 || idxVal >= len(o.Value) {
+		res = UndefinedValue
+		return
+	}
+	res = o.Value[idxVal]
+	return
+}
+
+func (o *ImmutableArray) Iterate() Iterator {
+	return &ArrayIterator{
+		v: o.Value,
+		l: len(o.Value),
+	}
+}
+
+func (o *ImmutableArray) CanIterate() bool {
+	return true
+}
+
+type ImmutableMap struct {
+	ObjectImpl
+	Value *OrderedMap
+}
+
+func (o *ImmutableMap) TypeName() string {
+	return "immutable-map"
+}
+
+func (o *ImmutableMap) String() string {
+	return mapString(o.Value)
+}
+
+func (o *ImmutableMap) Copy() Object {
+	return &Map{Value: mapCopy(o.Value)}
+}
+
+func (o *ImmutableMap) IsFalsy() bool {
+	return o.Value.Len() == 0
+}
+
+func (o *ImmutableMap) IndexGet(index Object) (res Object, err error) {
+	return mapIndexGet(o.Value, index)
+}
+
+func (o *ImmutableMap) Equals(x Object) bool {
+	return mapEquals(o.Value, x)
+}
+
+func (o *ImmutableMap) Iterate() Iterator {
+	keys := o.Value.Keys()
+	return &MapIterator{
+		v: o.Value,
+		k: keys,
+		l: len(keys),
+	}
+}
+
+func (o *ImmutableMap) CanIterate() bool {
+	return true
+}
+
+type ImmutableSet struct {
+	ObjectImpl
+	Value *OrderedMap
+}
+
+func (o *ImmutableSet) TypeName() string {
+	return "immutable-set"
+}
+
+func (o *ImmutableSet) String() string {
+	return setString(o.Value)
+}
+
+func (o *ImmutableSet) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	return setBinaryOp(o.Value, op, rhs)
+}
+
+func (o *ImmutableSet) Copy() Object {
+	return &Set{Value: o.Value.Copy()}
+}
+
+func (o *ImmutableSet) IsFalsy() bool {
+	return o.Value.Len() == 0
+}
+
+func (o *ImmutableSet) Equals(x Object) bool {
+	return setEquals(o.Value, x)
+}
+
+func (o *ImmutableSet) IndexGet(index Object) (res Object, err error) {
+	name, ok := index.(*String)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	if name.Value != "içerir" {
+		err = fmt.Errorf("küme metodu bulunamadı: %s", name.Value)
+		return
+	}
+	return setMethod(o, name.Value), nil
+}
+
+func (o *ImmutableSet) Iterate() Iterator {
+	keys := o.Value.Keys()
+	return &SetIterator{
+		v: keys,
+		l: len(keys),
+	}
+}
+
+func (o *ImmutableSet) CanIterate() bool {
+	return true
+}
+
+type Int struct {
+	ObjectImpl
+	Value int64
+}
+
+func (o *Int) String() string {
+	return strconv.FormatInt(o.Value, 10)
+}
+
+func (o *Int) TypeName() string {
+	return "int"
+}
+
+func (o *Int) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	switch rhs := rhs.(type) {
+	case *Int:
+		switch op {
+		case token.Add:
+			r := o.Value + rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Sub:
+			r := o.Value - rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Mul:
+			r := o.Value * rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Quo:
+			r := o.Value / rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Rem:
+			r := o.Value % rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.And:
+			r := o.Value & rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Or:
+			r := o.Value | rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Xor:
+			r := o.Value ^ rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.AndNot:
+			r := o.Value &^ rhs.Value
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Shl:
+			r := o.Value << uint64(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Shr:
+			r := o.Value >> uint64(rhs.Value)
+			if r == o.Value {
+				return o, nil
+			}
+			return &Int{Value: r}, nil
+		case token.Less:
+			if o.Value < rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if o.Value > rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if o.Value <= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if o.Value >= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case *Float:
+		switch op {
+		case token.Add:
+			return &Float{Value: float64(o.Value) + rhs.Value}, nil
+		case token.Sub:
+			return &Float{Value: float64(o.Value) - rhs.Value}, nil
+		case token.Mul:
+			return &Float{Value: float64(o.Value) * rhs.Value}, nil
+		case token.Quo:
+			return &Float{Value: float64(o.Value) / rhs.Value}, nil
+		case token.Less:
+			if float64(o.Value) < rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if float64(o.Value) > rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if float64(o.Value) <= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if float64(o.Value) >= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case *Char:
+		switch op {
+		case token.Add:
+			return &Char{Value: rune(o.Value) + rhs.Value}, nil
+		case token.Sub:
+			return &Char{Value: rune(o.Value) - rhs.Value}, nil
+		case token.Less:
+			if o.Value < int64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if o.Value > int64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if o.Value <= int64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if o.Value >= int64(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *Int) Copy() Object {
+	return &Int{Value: o.Value}
+}
+
+func (o *Int) IsFalsy() bool {
+	return o.Value == 0
+}
+
+func (o *Int) Equals(x Object) bool {
+	t, ok := x.(*Int)
+	if !ok {
+		return false
+	}
+	return o.Value == t.Value
+}
+
+type Map struct {
+	ObjectImpl
+	Value *OrderedMap
+}
+
+func (o *Map) TypeName() string {
+	return "map"
+}
+
+func (o *Map) String() string {
+	return mapString(o.Value)
+}
+
+func (o *Map) Copy() Object {
+	return &Map{Value: mapCopy(o.Value)}
+}
+
+func (o *Map) IsFalsy() bool {
+	return o.Value.Len() == 0
+}
+
+func (o *Map) Equals(x Object) bool {
+	return mapEquals(o.Value, x)
+}
+
+func (o *Map) IndexGet(index Object) (res Object, err error) {
+	return mapIndexGet(o.Value, index)
+}
+
+func (o *Map) IndexSet(index, value Object) (err error) {
+	if !IsHashable(index) {
+		return ErrInvalidIndexType
+	}
+	if o.Value == nil {
+		o.Value = NewOrderedMap(0)
+	}
+	return o.Value.Set(index, value)
+}
+
+func (o *Map) Iterate() Iterator {
+	keys := o.Value.Keys()
+	return &MapIterator{
+		v: o.Value,
+		k: keys,
+		l: len(keys),
+	}
+}
+
+func (o *Map) CanIterate() bool {
+	return true
+}
+
+func mapKeyString(key Object) string {
+	switch key := key.(type) {
+	case *String:
+		if isIdentKey(key.Value) {
+			return key.Value
+		}
+		return strconv.Quote(key.Value)
+	case *Char:
+		return strconv.QuoteRune(key.Value)
+	}
+	return key.String()
+}
+
+func isIdentKey(s string) bool {
+	if s == "" || token.Lookup(s) != token.Ident {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func mapString(m *OrderedMap) string {
+	var pairs []string
+	m.Range(func(key, value Object) bool {
+		pairs = append(pairs, fmt.Sprintf("%s: %s", mapKeyString(key), value.String()))
+		return true
+	})
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
+
+func mapCopy(m *OrderedMap) *OrderedMap {
+	c := NewOrderedMap(m.Len())
+	m.Range(func(key, value Object) bool {
+		_ = c.Set(key, value.Copy())
+		return true
+	})
+	return c
+}
+
+func mapEquals(m *OrderedMap, x Object) bool {
+	var xVal *OrderedMap
+	switch x := x.(type) {
+	case *Map:
+		xVal = x.Value
+	case *ImmutableMap:
+		xVal = x.Value
+	default:
+		return false
+	}
+	if m.Len() != xVal.Len() {
+		return false
+	}
+	equal := true
+	m.Range(func(key, value Object) bool {
+		tv, ok := xVal.Get(key)
+		equal = ok && value.Equals(tv)
+		return equal
+	})
+	return equal
+}
+
+func mapIndexGet(m *OrderedMap, index Object) (Object, error) {
+	if !IsHashable(index) {
+		return nil, ErrInvalidIndexType
+	}
+	res, ok := m.Get(index)
+	if !ok {
+		res = UndefinedValue
+	}
+	return res, nil
+}
+
+type ObjectPtr struct {
+	ObjectImpl
+	Value *Object
+}
+
+func (o *ObjectPtr) String() string {
+	return "free-var"
+}
+
+func (o *ObjectPtr) TypeName() string {
+	return "<free-var>"
+}
+
+func (o *ObjectPtr) Copy() Object {
+	return o
+}
+
+func (o *ObjectPtr) IsFalsy() bool {
+	return o.Value == nil
+}
+
+func (o *ObjectPtr) Equals(x Object) bool {
+	return o == x
+}
+
+type Set struct {
+	ObjectImpl
+	Value *OrderedMap
+}
+
+func NewSet(elements ...Object) (*Set, error) {
+	s := &Set{Value: NewOrderedMap(len(elements))}
+	for _, e := range elements {
+		if err := s.Value.Set(e, TrueValue); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (o *Set) TypeName() string {
+	return "set"
+}
+
+func (o *Set) String() string {
+	return setString(o.Value)
+}
+
+func (o *Set) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	return setBinaryOp(o.Value, op, rhs)
+}
+
+func (o *Set) Copy() Object {
+	return &Set{Value: o.Value.Copy()}
+}
+
+func (o *Set) IsFalsy() bool {
+	return o.Value.Len() == 0
+}
+
+func (o *Set) Equals(x Object) bool {
+	return setEquals(o.Value, x)
+}
+
+func (o *Set) IndexGet(index Object) (res Object, err error) {
+	name, ok := index.(*String)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	switch name.Value {
+	case "ekle", "sil", "içerir":
+		return setMethod(o, name.Value), nil
+	}
+	err = fmt.Errorf("küme metodu bulunamadı: %s", name.Value)
+	return
+}
+
+func (o *Set) Iterate() Iterator {
+	keys := o.Value.Keys()
+	return &SetIterator{
+		v: keys,
+		l: len(keys),
+	}
+}
+
+func (o *Set) CanIterate() bool {
+	return true
+}
+
+func setString(m *OrderedMap) string {
+	var elements []string
+	m.Range(func(key, _ Object) bool {
+		elements = append(elements, key.String())
+		return true
+	})
+	return fmt.Sprintf("küme(%s)", strings.Join(elements, ", "))
+}
+
+func setValue(o Object) (*OrderedMap, bool) {
+	switch o := o.(type) {
+	case *Set:
+		return o.Value, true
+	case *ImmutableSet:
+		return o.Value, true
+	}
+	return nil, false
+}
+
+func setEquals(m *OrderedMap, x Object) bool {
+	xVal, ok := setValue(x)
+	if !ok || m.Len() != xVal.Len() {
+		return false
+	}
+	equal := true
+	m.Range(func(key, _ Object) bool {
+		equal = xVal.Has(key)
+		return equal
+	})
+	return equal
+}
+
+func setBinaryOp(lhs *OrderedMap, op token.Token, rhs Object) (Object, error) {
+	rhsVal, ok := setValue(rhs)
+	if !ok {
+		return nil, ErrInvalidOperator
+	}
+	res := NewOrderedMap(0)
+	add := func(key, _ Object) bool {
+		_ = res.Set(key, TrueValue)
+		return true
+	}
+	switch op {
+	case token.Or:
+		lhs.Range(add)
+		rhsVal.Range(add)
+	case token.And:
+		lhs.Range(func(key, value Object) bool {
+			if rhsVal.Has(key) {
+				add(key, value)
+			}
+			return true
+		})
+	case token.Sub:
+		lhs.Range(func(key, value Object) bool {
+			if !rhsVal.Has(key) {
+				add(key, value)
+			}
+			return true
+		})
+	case token.Xor:
+		lhs.Range(func(key, value Object) bool {
+			if !rhsVal.Has(key) {
+				add(key, value)
+			}
+			return true
+		})
+		rhsVal.Range(func(key, value Object) bool {
+			if !lhs.Has(key) {
+				add(key, value)
+			}
+			return true
+		})
+	default:
+		return nil, ErrInvalidOperator
+	}
+	return &Set{Value: res}, nil
+}
+
+func setMethod(o Object, name string) *BuiltinFunction {
+	var fn CallableFunc
+	var receiver Object
+	switch name {
+	case "ekle":
+		set := o.(*Set)
+		receiver = set
+		fn = func(args ...Object) (Object, error) {
+			if set.Value == nil {
+				set.Value = NewOrderedMap(len(args))
+			}
+			for _, arg := range args {
+				if err := set.Value.Set(arg, TrueValue); err != nil {
+					return nil, err
+				}
+			}
+			return set, nil
+		}
+	case "sil":
+		set := o.(*Set)
+		receiver = set
+		fn = func(args ...Object) (Object, error) {
+			removed := false
+			for _, arg := range args {
+				if set.Value.Delete(arg) {
+					removed = true
+				}
+			}
+			if removed {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case "içerir":
+		m, _ := setValue(o)
+		fn = func(args ...Object) (Object, error) {
+			if len(args) != 1 {
+				return nil, ErrWrongNumArguments
+			}
+			if m.Has(args[0]) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	}
+	return &BuiltinFunction{Name: name, Value: fn, receiver: receiver}
+}
+
+type String struct {
+	ObjectImpl
+	Value   string
+	runeStr []rune
+}
+
+func (o *String) TypeName() string {
+	return "string"
+}
+
+func (o *String) String() string {
+	return strconv.Quote(o.Value)
+}
+
+func (o *String) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	switch op {
+	case token.Add:
+		switch rhs := rhs.(type) {
+		case *String:
+			if len(o.Value)+len(rhs.Value) > MaxStringLen {
+				return nil, ErrStringLimit
+			}
+			return &String{Value: o.Value + rhs.Value}, nil
+		default:
+			rhsStr := rhs.String()
+			if len(o.Value)+len(rhsStr) > MaxStringLen {
+				return nil, ErrStringLimit
+			}
+			return &String{Value: o.Value + rhsStr}, nil
+		}
+	case token.Less:
+		switch rhs := rhs.(type) {
+		case *String:
+			if o.Value < rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case token.LessEq:
+		switch rhs := rhs.(type) {
+		case *String:
+			if o.Value <= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case token.Greater:
+		switch rhs := rhs.(type) {
+		case *String:
+			if o.Value > rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	case token.GreaterEq:
+		switch rhs := rhs.(type) {
+		case *String:
+			if o.Value >= rhs.Value {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *String) IsFalsy() bool {
+	return len(o.Value) == 0
+}
+
+func (o *String) Copy() Object {
+	return &String{Value: o.Value}
+}
+
+func (o *String) Equals(x Object) bool {
+	t, ok := x.(*String)
+	if !ok {
+		return false
+	}
+	return o.Value == t.Value
+}
+
+func (o *String) IndexGet(index Object) (res Object, err error) {
+	intIdx, ok := index.(*Int)
+	if !ok {
+		err = ErrInvalidIndexType
+		return
+	}
+	idxVal := int(intIdx.Value)
+	if o.runeStr == nil {
+		o.runeStr = []rune(o.Value)
+	}
+	if idxVal < 0 || idxVal >= len(o.runeStr) {
+		res = UndefinedValue
+		return
+	}
+	res = &Char{Value: o.runeStr[idxVal]}
+	return
+}
+
+func (o *String) Iterate() Iterator {
+	if o.runeStr == nil {
+		o.runeStr = []rune(o.Value)
+	}
+	return &StringIterator{
+		v: o.runeStr,
+		l: len(o.runeStr),
+	}
+}
+
+func (o *String) CanIterate() bool {
+	return true
+}
+
+type Task struct {
+	ObjectImpl
+	done  chan struct{}
+	value Object
+	err   error
+}
+
+func (o *Task) TypeName() string {
+	return "task"
+}
+
+func (o *Task) String() string {
+	select {
+	case <-o.done:
+		return "<görev: bitti>"
+	default:
+		return "<görev: çalışıyor>"
+	}
+}
+
+func (o *Task) Copy() Object {
+	return o
+}
+
+func (o *Task) IsFalsy() bool {
+	return false
+}
+
+func (o *Task) Equals(x Object) bool {
+	return o == x
+}
+
+func (o *Task) Wait() (Object, error) {
+	return o.wait(nil)
+}
+
+func (o *Task) wait(abort <-chan struct{}) (Object, error) {
+	select {
+	case <-o.done:
+	case <-abort:
+		return nil, ErrVMAborted
+	}
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.value == nil {
+		return UndefinedValue, nil
+	}
+	return o.value, nil
+}
+
+type Time struct {
+	ObjectImpl
+	Value time.Time
+}
+
+func (o *Time) String() string {
+	return o.Value.String()
+}
+
+func (o *Time) TypeName() string {
+	return "time"
+}
+
+func (o *Time) BinaryOp(op token.Token, rhs Object) (Object, error) {
+	switch rhs := rhs.(type) {
+	case *Int:
+		switch op {
+		case token.Add:
+			if rhs.Value == 0 {
+				return o, nil
+			}
+			return &Time{Value: o.Value.Add(time.Duration(rhs.Value))}, nil
+		case token.Sub:
+			if rhs.Value == 0 {
+				return o, nil
+			}
+			return &Time{Value: o.Value.Add(time.Duration(-rhs.Value))}, nil
+		}
+	case *Time:
+		switch op {
+		case token.Sub:
+			return &Int{Value: int64(o.Value.Sub(rhs.Value))}, nil
+		case token.Less:
+			if o.Value.Before(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.Greater:
+			if o.Value.After(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.LessEq:
+			if o.Value.Equal(rhs.Value) || o.Value.Before(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		case token.GreaterEq:
+			if o.Value.Equal(rhs.Value) || o.Value.After(rhs.Value) {
+				return TrueValue, nil
+			}
+			return FalseValue, nil
+		}
+	}
+	return nil, ErrInvalidOperator
+}
+
+func (o *Time) Copy() Object {
+	return &Time{Value: o.Value}
+}
+
+func (o *Time) IsFalsy() bool {
+	return o.Value.IsZero()
+}
+
+func (o *Time) Equals(x Object) bool {
+	t, ok := x.(*Time)
+	if !ok {
+		return false
+	}
+	return o.Value.Equal(t.Value)
+}
+
+type Undefined struct {
+	ObjectImpl
+}
+
+func (o *Undefined) TypeName() string {
+	return "undefined"
+}
+
+func (o *Undefined) String() string {
+	return "<undefined>"
+}
+
+func (o *Undefined) Copy() Object {
+	return o
+}
+
+func (o *Undefined) IsFalsy() bool {
+	return true
+}
+
+func (o *Undefined) Equals(x Object) bool {
+	return o == x
+}
+
+func (o *Undefined) IndexGet(_ Object) (Object, error) {
+	return UndefinedValue, nil
+}
+
+func (o *Undefined) Iterate() Iterator {
+	return o
+}
+
+func (o *Undefined) CanIterate() bool {
+	return true
+}
+
+func (o *Undefined) Next() bool {
+	return false
+}
+
+func (o *Undefined) Key() Object {
+	return o
+}
+
+func (o *Undefined) Value() Object {
+	return o
+}
+
+type UserFunction struct {
+	ObjectImpl
+	Name    string
+	Value   CallableFunc
+	Interop InteropFunc
+}
+
+func (o *UserFunction) TypeName() string {
+	return "user-function:" + o.Name
+}
+
+func (o *UserFunction) String() string {
+	return "<user-function>"
+}
+
+func (o *UserFunction) Copy() Object {
+	return &UserFunction{Value: o.Value, Name: o.Name, Interop: o.Interop}
+}
+
+func (o *UserFunction) Equals(_ Object) bool {
+	return false
+}
+
+func (o *UserFunction) Call(args ...Object) (Object, error) {
+	if o.Value == nil {
+		return nil, ErrNoInterop
+	}
+	return o.Value(args...)
+}
+
+func (o *UserFunction) CanCall() bool {
+	return true
+}
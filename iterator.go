@@ -1,170 +1,309 @@
-package lokum
-
-type Iterator interface {
-	Object
-
-	Next() bool
-
-	Key() Object
-
-	Value() Object
-}
-
-type ArrayIterator struct {
-	ObjectImpl
-	v []Object
-	i int
-	l int
-}
-
-func (i *ArrayIterator) TypeName() string {
-	return "array-iterator"
-}
-
-func (i *ArrayIterator) String() string {
-	return "<array-iterator>"
-}
-
-func (i *ArrayIterator) IsFalsy() bool {
-	return true
-}
-
-func (i *ArrayIterator) Equals(Object) bool {
-	return false
-}
-
-func (i *ArrayIterator) Copy() Object {
-	return &ArrayIterator{v: i.v, i: i.i, l: i.l}
-}
-
-func (i *ArrayIterator) Next() bool {
-	i.i++
-	return i.i <= i.l
-}
-
-func (i *ArrayIterator) Key() Object {
-	return &Int{Value: int64(i.i - 1)}
-}
-
-func (i *ArrayIterator) Value() Object {
-	return i.v[i.i-1]
-}
-
-type BytesIterator struct {
-	ObjectImpl
-	v []byte
-	i int
-	l int
-}
-
-func (i *BytesIterator) TypeName() string {
-	return "bytes-iterator"
-}
-
-func (i *BytesIterator) String() string {
-	return "<bytes-iterator>"
-}
-
-func (i *BytesIterator) Equals(Object) bool {
-	return false
-}
-
-func (i *BytesIterator) Copy() Object {
-	return &BytesIterator{v: i.v, i: i.i, l: i.l}
-}
-
-func (i *BytesIterator) Next() bool {
-	i.i++
-	return i.i <= i.l
-}
-
-func (i *BytesIterator) Key() Object {
-	return &Int{Value: int64(i.i - 1)}
-}
-
-func (i *BytesIterator) Value() Object {
-	return &Int{Value: int64(i.v[i.i-1])}
-}
-
-type MapIterator struct {
-	ObjectImpl
-	v map[string]Object
-	k []string
-	i int
-	l int
-}
-
-func (i *MapIterator) TypeName() string {
-	return "map-iterator"
-}
-
-func (i *MapIterator) String() string {
-	return "<map-iterator>"
-}
-
-func (i *MapIterator) IsFalsy() bool {
-	return true
-}
-
-func (i *MapIterator) Equals(Object) bool {
-	return false
-}
-
-func (i *MapIterator) Copy() Object {
-	return &MapIterator{v: i.v, k: i.k, i: i.i, l: i.l}
-}
-
-func (i *MapIterator) Next() bool {
-	i.i++
-	return i.i <= i.l
-}
-
-func (i *MapIterator) Key() Object {
-	k := i.k[i.i-1]
-	return &String{Value: k}
-}
-
-func (i *MapIterator) Value() Object {
-	k := i.k[i.i-1]
-	return i.v[k]
-}
-
-type StringIterator struct {
-	ObjectImpl
-	v []rune
-	i int
-	l int
-}
-
-func (i *StringIterator) TypeName() string {
-	return "string-iterator"
-}
-
-func (i *StringIterator) String() string {
-	return "<string-iterator>"
-}
-
-func (i *StringIterator) IsFalsy() bool {
-	return true
-}
-
-func (i *StringIterator) Equals(Object) bool {
-	return false
-}
-
-func (i *StringIterator) Copy() Object {
-	return &StringIterator{v: i.v, i: i.i, l: i.l}
-}
-
-func (i *StringIterator) Next() bool {
-	i.i++
-	return i.i <= i.l
-}
-
-func (i *StringIterator) Key() Object {
-	return &Int{Value: int64(i.i - 1)}
-}
-
-func (i *StringIterator) Value() Object {
-	return &Char{Value: i.v[i.i-1]}
-}
+package lokum
+
+type Iterator interface {
+	Object
+
+	Next() bool
+
+	Key() Object
+
+	Value() Object
+}
+
+type ArrayIterator struct {
+	ObjectImpl
+	v []Object
+	i int
+	l int
+}
+
+func (i *ArrayIterator) TypeName() string {
+	return "array-iterator"
+}
+
+func (i *ArrayIterator) String() string {
+	return "<array-iterator>"
+}
+
+func (i *ArrayIterator) IsFalsy() bool {
+	return true
+}
+
+func (i *ArrayIterator) Equals(Object) bool {
+	return false
+}
+
+func (i *ArrayIterator) Copy() Object {
+	return &ArrayIterator{v: i.v, i: i.i, l: i.l}
+}
+
+func (i *ArrayIterator) Next() bool {
+	i.i++
+	return i.i <= i.l
+}
+
+func (i *ArrayIterator) Key() Object {
+	return &Int{Value: int64(i.i - 1)}
+}
+
+func (i *ArrayIterator) Value() Object {
+	return i.v[i.i-1]
+}
+
+type BytesIterator struct {
+	ObjectImpl
+	v []byte
+	i int
+	l int
+}
+
+func (i *BytesIterator) TypeName() string {
+	return "bytes-iterator"
+}
+
+func (i *BytesIterator) String() string {
+	return "<bytes-iterator>"
+}
+
+func (i *BytesIterator) Equals(Object) bool {
+	return false
+}
+
+func (i *BytesIterator) Copy() Object {
+	return &BytesIterator{v: i.v, i: i.i, l: i.l}
+}
+
+func (i *BytesIterator) Next() bool {
+	i.i++
+	return i.i <= i.l
+}
+
+func (i *BytesIterator) Key() Object {
+	return &Int{Value: int64(i.i - 1)}
+}
+
+func (i *BytesIterator) Value() Object {
+	return &Int{Value: int64(i.v[i.i-1])}
+}
+
+type ChannelIterator struct {
+	ObjectImpl
+	v    chan Object
+	i    int
+	cur  Object
+	done <-chan struct{}
+}
+
+func (i *ChannelIterator) TypeName() string {
+	return "channel-iterator"
+}
+
+func (i *ChannelIterator) String() string {
+	return "<channel-iterator>"
+}
+
+func (i *ChannelIterator) IsFalsy() bool {
+	return true
+}
+
+func (i *ChannelIterator) Equals(Object) bool {
+	return false
+}
+
+func (i *ChannelIterator) Copy() Object {
+	return &ChannelIterator{v: i.v, i: i.i, cur: i.cur, done: i.done}
+}
+
+func (i *ChannelIterator) Next() bool {
+	var value Object
+	var ok bool
+	select {
+	case value, ok = <-i.v:
+	case <-i.done:
+	}
+	if !ok {
+		return false
+	}
+	i.i++
+	i.cur = value
+	return true
+}
+
+func (i *ChannelIterator) Key() Object {
+	return &Int{Value: int64(i.i - 1)}
+}
+
+func (i *ChannelIterator) Value() Object {
+	return i.cur
+}
+
+type MapIterator struct {
+	ObjectImpl
+	v *OrderedMap
+	k []Object
+	i int
+	l int
+}
+
+func (i *MapIterator) TypeName() string {
+	return "map-iterator"
+}
+
+func (i *MapIterator) String() string {
+	return "<map-iterator>"
+}
+
+func (i *MapIterator) IsFalsy() bool {
+	return true
+}
+
+func (i *MapIterator) Equals(Object) bool {
+	return false
+}
+
+func (i *MapIterator) Copy() Object {
+	return &MapIterator{v: i.v, k: i.k, i: i.i, l: i.l}
+}
+
+func (i *MapIterator) Next() bool {
+	i.i++
+	return i.i <= i.l
+}
+
+func (i *MapIterator) Key() Object {
+	return i.k[i.i-1]
+}
+
+func (i *MapIterator) Value() Object {
+	k := i.k[i.i-1]
+	v, ok := i.v.Get(k)
+	if !ok {
+		return UndefinedValue
+	}
+	return v
+}
+
+type SetIterator struct {
+	ObjectImpl
+	v []Object
+	i int
+	l int
+}
+
+func (i *SetIterator) TypeName() string {
+	return "set-iterator"
+}
+
+func (i *SetIterator) String() string {
+	return "<set-iterator>"
+}
+
+func (i *SetIterator) IsFalsy() bool {
+	return true
+}
+
+func (i *SetIterator) Equals(Object) bool {
+	return false
+}
+
+func (i *SetIterator) Copy() Object {
+	return &SetIterator{v: i.v, i: i.i, l: i.l}
+}
+
+func (i *SetIterator) Next() bool {
+	i.i++
+	return i.i <= i.l
+}
+
+func (i *SetIterator) Key() Object {
+	return &Int{Value: int64(i.i - 1)}
+}
+
+func (i *SetIterator) Value() Object {
+	return i.v[i.i-1]
+}
+
+type StringIterator struct {
+	ObjectImpl
+	v []rune
+	i int
+	l int
+}
+
+func (i *StringIterator) TypeName() string {
+	return "string-iterator"
+}
+
+func (i *StringIterator) String() string {
+	return "<string-iterator>"
+}
+
+func (i *StringIterator) IsFalsy() bool {
+	return true
+}
+
+func (i *StringIterator) Equals(Object) bool {
+	return false
+}
+
+func (i *StringIterator) Copy() Object {
+	return &StringIterator{v: i.v, i: i.i, l: i.l}
+}
+
+func (i *StringIterator) Next() bool {
+	i.i++
+	return i.i <= i.l
+}
+
+func (i *StringIterator) Key() Object {
+	return &Int{Value: int64(i.i - 1)}
+}
+
+func (i *StringIterator) Value() Object {
+	return &Char{Value: i.v[i.i-1]}
+}
+
+type GoValueIterator struct {
+	ObjectImpl
+	v *GoValue
+	k []Object
+	i int
+	l int
+}
+
+func (i *GoValueIterator) TypeName() string {
+	return "go-iterator"
+}
+
+func (i *GoValueIterator) String() string {
+	return "<go-iterator>"
+}
+
+func (i *GoValueIterator) IsFalsy() bool {
+	return true
+}
+
+func (i *GoValueIterator) Equals(Object) bool {
+	return false
+}
+
+func (i *GoValueIterator) Copy() Object {
+	return &GoValueIterator{v: i.v, k: i.k, i: i.i, l: i.l}
+}
+
+func (i *GoValueIterator) Next() bool {
+	i.i++
+	return i.i <= i.l
+}
+
+func (i *GoValueIterator) Key() Object {
+	return i.k[i.i-1]
+}
+
+func (i *GoValueIterator) Value() Object {
+	v, err := i.v.IndexGet(i.k[i.i-1])
+	if err != nil {
+		return UndefinedValue
+	}
+	return v
+}
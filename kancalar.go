@@ -0,0 +1,31 @@
+package lokum
+
+import "github.com/onrirr/lokum/parser"
+
+type Hooks interface {
+	Enter(fn *CompiledFunction, pos parser.SourceFilePos)
+	Exit(fn *CompiledFunction, pos parser.SourceFilePos)
+	Call(fn Object, args []Object, pos parser.SourceFilePos)
+	Import(name string)
+	Error(err error)
+}
+
+type NopHooks struct{}
+
+func (NopHooks) Enter(*CompiledFunction, parser.SourceFilePos) {}
+
+func (NopHooks) Exit(*CompiledFunction, parser.SourceFilePos) {}
+
+func (NopHooks) Call(Object, []Object, parser.SourceFilePos) {}
+
+func (NopHooks) Import(string) {}
+
+func (NopHooks) Error(error) {}
+
+func (v *VM) SetHooks(h Hooks) {
+	v.hooks = h
+}
+
+func (v *VM) pos() parser.SourceFilePos {
+	return v.fileSet.Position(v.curFrame.fn.SourcePos(v.ip))
+}
@@ -0,0 +1,98 @@
+package lokum
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+type recordHooks struct {
+	NopHooks
+	events []string
+}
+
+func (h *recordHooks) Enter(fn *CompiledFunction, pos parser.SourceFilePos) {
+	h.events = append(h.events, fmt.Sprintf("giriş %d", pos.Line))
+}
+
+func (h *recordHooks) Exit(fn *CompiledFunction, pos parser.SourceFilePos) {
+	h.events = append(h.events, fmt.Sprintf("çıkış %d", pos.Line))
+}
+
+func (h *recordHooks) Import(name string) {
+	h.events = append(h.events, "kullan "+name)
+}
+
+func (h *recordHooks) Error(err error) {
+	h.events = append(h.events, "hata")
+}
+
+func TestHooksImportFromBytecode(t *testing.T) {
+	modules := NewModuleMap()
+	modules.AddBuiltinModule("mod", map[string]Object{"sayı": &Int{Value: 7}})
+	modules.AddSourceModule("kaynak", []byte(`paylaş 5`))
+	s := NewScript([]byte(`
+a := kullan("mod")
+b := kullan("kaynak")
+`))
+	s.SetImports(modules)
+	h := &recordHooks{}
+	s.SetHooks(h)
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.events) != 0 {
+		t.Fatalf("derlemede kanca çağrıldı: %v", h.events)
+	}
+
+	var buf bytes.Buffer
+	if err := c.bytecode.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var b Bytecode
+	if err := b.Decode(&buf, modules); err != nil {
+		t.Fatal(err)
+	}
+	v := NewVM(&b, nil, -1)
+	v.SetHooks(h)
+	for i := 0; i < 2; i++ {
+		if err := v.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"kullan mod", "kullan kaynak", "giriş 3", "çıkış 1",
+		"kullan mod", "kullan kaynak", "giriş 3", "çıkış 1",
+	}
+	if !reflect.DeepEqual(h.events, want) {
+		t.Fatalf("olaylar %v, beklenen %v", h.events, want)
+	}
+}
+
+func TestHooksExitOnError(t *testing.T) {
+	s := NewScript([]byte(`iç := fn() {
+	dön "a" - 1
+}
+dış := fn() {
+	dön iç()
+}
+dış()
+`))
+	h := &recordHooks{}
+	s.SetHooks(h)
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err == nil {
+		t.Fatal("hata bekleniyordu")
+	}
+	want := []string{"giriş 7", "giriş 5", "çıkış 2", "çıkış 5", "hata"}
+	if !reflect.DeepEqual(h.events, want) {
+		t.Fatalf("olaylar %v, beklenen %v", h.events, want)
+	}
+}
@@ -0,0 +1,159 @@
+package lokum
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+type Coverage struct {
+	lock  sync.Mutex
+	funcs map[*byte]*coverFunc
+}
+
+type CoverageFile struct {
+	Name  string
+	Lines []CoverageLine
+}
+
+type CoverageLine struct {
+	Line  int
+	Count int64
+}
+
+type coverFunc struct {
+	fn      *CompiledFunction
+	fileSet *parser.SourceFileSet
+	hits    []int64
+}
+
+type coverLine struct {
+	fileSet *parser.SourceFileSet
+	file    string
+	line    int
+}
+
+func NewCoverage() *Coverage {
+	return &Coverage{funcs: make(map[*byte]*coverFunc)}
+}
+
+func (c *Coverage) Add(bytecode *Bytecode) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.add(bytecode.FileSet, bytecode.MainFunction)
+	for _, o := range bytecode.Constants {
+		if fn, ok := o.(*CompiledFunction); ok {
+			c.add(bytecode.FileSet, fn)
+		}
+	}
+}
+
+func (c *Coverage) add(fileSet *parser.SourceFileSet, fn *CompiledFunction) {
+	if fn == nil || len(fn.Instructions) == 0 {
+		return
+	}
+	code := &fn.Instructions[0]
+	if _, ok := c.funcs[code]; ok {
+		return
+	}
+	c.funcs[code] = &coverFunc{
+		fn:      fn,
+		fileSet: fileSet,
+		hits:    make([]int64, len(fn.Instructions)),
+	}
+}
+
+func (c *Coverage) Files() []*CoverageFile {
+	c.lock.Lock()
+	counts := make(map[coverLine]int64)
+	for _, f := range c.funcs {
+		for ip, p := range f.fn.SourceMap {
+			pos := f.fileSet.Position(p)
+			if !pos.IsValid() || ip >= len(f.hits) {
+				continue
+			}
+			key := coverLine{f.fileSet, pos.Filename, pos.Line}
+			n := atomic.LoadInt64(&f.hits[ip])
+			if cur, ok := counts[key]; !ok || n > cur {
+				counts[key] = n
+			}
+		}
+	}
+	c.lock.Unlock()
+
+	lines := make(map[string]map[int]int64)
+	for key, n := range counts {
+		file := lines[key.file]
+		if file == nil {
+			file = make(map[int]int64)
+			lines[key.file] = file
+		}
+		file[key.line] += n
+	}
+	files := make([]*CoverageFile, 0, len(lines))
+	for name, counts := range lines {
+		f := &CoverageFile{Name: name}
+		for line, n := range counts {
+			f.Lines = append(f.Lines, CoverageLine{Line: line, Count: n})
+		}
+		sort.Slice(f.Lines, func(i, j int) bool {
+			return f.Lines[i].Line < f.Lines[j].Line
+		})
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files
+}
+
+func (c *Coverage) record(v *VM) {
+	ip := v.ip + 1
+	fn := v.curFrame.fn
+	if ip >= len(fn.Instructions) {
+		return
+	}
+	if code := &fn.Instructions[0]; code != v.coverCode {
+		c.lock.Lock()
+		f := c.funcs[code]
+		c.lock.Unlock()
+		v.coverCode, v.coverHits = code, nil
+		if f != nil {
+			v.coverHits = f.hits
+		}
+	}
+	if v.coverHits != nil {
+		atomic.AddInt64(&v.coverHits[ip], 1)
+	}
+}
+
+func (f *CoverageFile) Covered() int {
+	n := 0
+	for _, l := range f.Lines {
+		if l.Count > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *CoverageFile) Percent() float64 {
+	if len(f.Lines) == 0 {
+		return 100
+	}
+	return 100 * float64(f.Covered()) / float64(len(f.Lines))
+}
+
+func (v *VM) SetCoverage(c *Coverage) {
+	v.coverage = c
+	v.coverCode, v.coverHits = nil, nil
+	if c != nil {
+		c.Add(&Bytecode{
+			FileSet:      v.fileSet,
+			MainFunction: v.frames[0].fn,
+			Constants:    v.constants,
+		})
+	}
+}
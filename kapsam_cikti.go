@@ -0,0 +1,134 @@
+package lokum
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+var errNoSource = errors.New("kaynak bulunamadı")
+
+func WriteLCOV(w io.Writer, files []*CoverageFile) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range files {
+		_, _ = fmt.Fprintf(bw, "TN:\nSF:%s\n", f.Name)
+		for _, l := range f.Lines {
+			_, _ = fmt.Fprintf(bw, "DA:%d,%d\n", l.Line, l.Count)
+		}
+		_, _ = fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n",
+			len(f.Lines), f.Covered())
+	}
+	return bw.Flush()
+}
+
+type coverageReport struct {
+	Percent string
+	Files   []coverageReportFile
+}
+
+type coverageReportFile struct {
+	ID      int
+	Name    string
+	Lines   int
+	Covered int
+	Percent string
+	Error   string
+	Source  []coverageReportLine
+}
+
+type coverageReportLine struct {
+	Line  int
+	Text  string
+	Class string
+	Count string
+}
+
+func WriteCoverageHTML(
+	w io.Writer,
+	files []*CoverageFile,
+	source func(name string) ([]byte, error),
+) error {
+	report := &coverageReport{}
+	total, covered := 0, 0
+	for i, f := range files {
+		rf := coverageReportFile{
+			ID:      i,
+			Name:    f.Name,
+			Lines:   len(f.Lines),
+			Covered: f.Covered(),
+			Percent: fmt.Sprintf("%.1f%%", f.Percent()),
+		}
+		total += rf.Lines
+		covered += rf.Covered
+
+		var src []byte
+		err := errNoSource
+		if source != nil {
+			src, err = source(f.Name)
+		}
+		if err != nil {
+			rf.Error = err.Error()
+		} else {
+			counts := make(map[int]int64, len(f.Lines))
+			for _, l := range f.Lines {
+				counts[l.Line] = l.Count
+			}
+			text := strings.TrimSuffix(
+				strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+			for i, line := range strings.Split(text, "\n") {
+				rl := coverageReportLine{Line: i + 1, Text: line}
+				if n, ok := counts[i+1]; ok {
+					rl.Count = fmt.Sprint(n)
+					rl.Class = "yok"
+					if n > 0 {
+						rl.Class = "var"
+					}
+				}
+				rf.Source = append(rf.Source, rl)
+			}
+		}
+		report.Files = append(report.Files, rf)
+	}
+	percent := 100.0
+	if total > 0 {
+		percent = 100 * float64(covered) / float64(total)
+	}
+	report.Percent = fmt.Sprintf("%.1f%%", percent)
+	return coverageTemplate.Execute(w, report)
+}
+
+var coverageTemplate = template.Must(template.New("kapsam").Parse(`<!DOCTYPE html>
+<html lang="tr">
+<head>
+<meta charset="utf-8">
+<title>Kapsam raporu</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 2px 8px; text-align: left; }
+.ozet td, .ozet th { border-bottom: 1px solid #ddd; }
+.kaynak { font-family: monospace; white-space: pre; }
+.kaynak td { padding: 0 8px; }
+.no, .sayi { color: #888; text-align: right; }
+.var { background: #dfd; }
+.yok { background: #fdd; }
+</style>
+</head>
+<body>
+<h1>Kapsam raporu: {{.Percent}}</h1>
+<table class="ozet">
+<tr><th>Dosya</th><th>Satır</th><th>Kapsanan</th><th>Oran</th></tr>
+{{range .Files}}<tr><td><a href="#dosya{{.ID}}">{{.Name}}</a></td><td>{{.Lines}}</td><td>{{.Covered}}</td><td>{{.Percent}}</td></tr>
+{{end}}</table>
+{{range .Files}}
+<h2 id="dosya{{.ID}}">{{.Name}} ({{.Percent}})</h2>
+{{if .Error}}<p>{{.Error}}</p>{{else}}<table class="kaynak">
+{{range .Source}}<tr{{with .Class}} class="{{.}}"{{end}}><td class="no">{{.Line}}</td><td class="sayi">{{.Count}}</td><td>{{.Text}}</td></tr>
+{{end}}</table>{{end}}
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,133 @@
+package lokum
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const coverMain = `mod := kullan("mod")
+x := 0
+tekrarla i := 0; i < 3; i++ {
+	eğer i == 1 {
+		x += mod.iki(i)
+	} yoksa {
+		x += 1
+	}
+}
+eğer x > 100 {
+	x = 0
+}
+`
+
+const coverModule = `iki := fn(n) {
+	eğer n < 0 {
+		dön 0
+	}
+	dön n * 2
+}
+paylaş {iki: iki}
+`
+
+func coverRun(t *testing.T) []*CoverageFile {
+	s := NewScript([]byte(coverMain))
+	s.EnableFileImport(true)
+	s.SetImportFS(fstest.MapFS{
+		"mod.lokum": &fstest.MapFile{Data: []byte(coverModule)},
+	})
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cov := NewCoverage()
+	c.SetCoverage(cov)
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cov.Files()
+}
+
+func TestCoverageCounts(t *testing.T) {
+	files := coverRun(t)
+	want := []*CoverageFile{
+		{Name: "(main)", Lines: []CoverageLine{
+			{1, 1}, {2, 1}, {3, 4}, {4, 3}, {5, 1}, {7, 2}, {10, 1}, {11, 0},
+		}},
+		{Name: "mod.lokum", Lines: []CoverageLine{
+			{1, 1}, {2, 1}, {3, 0}, {5, 1}, {7, 1},
+		}},
+	}
+	if !reflect.DeepEqual(files, want) {
+		for _, f := range files {
+			t.Logf("%s %v", f.Name, f.Lines)
+		}
+		t.Fatal("kapsam sayıları beklenenden farklı")
+	}
+	if n, p := files[1].Covered(), files[1].Percent(); n != 4 || p != 80 {
+		t.Fatalf("modül kapsamı %d satır, %%%.1f", n, p)
+	}
+}
+
+func TestWriteLCOV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteLCOV(&buf, coverRun(t)); err != nil {
+		t.Fatal(err)
+	}
+	want := `TN:
+SF:(main)
+DA:1,1
+DA:2,1
+DA:3,4
+DA:4,3
+DA:5,1
+DA:7,2
+DA:10,1
+DA:11,0
+LF:8
+LH:7
+end_of_record
+TN:
+SF:mod.lokum
+DA:1,1
+DA:2,1
+DA:3,0
+DA:5,1
+DA:7,1
+LF:5
+LH:4
+end_of_record
+`
+	if buf.String() != want {
+		t.Fatalf("%s\nbeklenen:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteCoverageHTML(t *testing.T) {
+	files := coverRun(t)
+	var buf bytes.Buffer
+	err := WriteCoverageHTML(&buf, files, func(name string) ([]byte, error) {
+		if name == "mod.lokum" {
+			return []byte(coverModule), nil
+		}
+		return nil, errNoSource
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, s := range []string{
+		"<h1>Kapsam raporu: 84.6%</h1>",
+		`<td><a href="#dosya0">(main)</a></td><td>8</td><td>7</td><td>87.5%</td>`,
+		`<h2 id="dosya1">mod.lokum (80.0%)</h2>`,
+		"<p>kaynak bulunamadı</p>",
+		`<tr class="var"><td class="no">2</td><td class="sayi">1</td><td>	eğer n &lt; 0 {</td></tr>`,
+		`<tr class="yok"><td class="no">3</td><td class="sayi">0</td><td>		dön 0</td></tr>`,
+		`<tr><td class="no">4</td><td class="sayi"></td><td>	}</td></tr>`,
+	} {
+		if !strings.Contains(html, s) {
+			t.Fatalf("HTML raporunda %q yok:\n%s", s, html)
+		}
+	}
+}
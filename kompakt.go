@@ -0,0 +1,434 @@
+package lokum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+const (
+	tagNil byte = iota
+	tagUndefined
+	tagFalse
+	tagTrue
+	tagInt
+	tagFloat
+	tagString
+	tagChar
+	tagBytes
+	tagTime
+	tagArray
+	tagImmutableArray
+	tagMap
+	tagImmutableMap
+	tagSet
+	tagImmutableSet
+	tagError
+	tagCompiledFunction
+	tagModule
+)
+
+var errCompactData = errors.New("bozuk bytecode verisi")
+
+type compactWriter struct {
+	buf *bytes.Buffer
+	tmp [binary.MaxVarintLen64]byte
+}
+
+func (w *compactWriter) uint(v uint64) {
+	w.buf.Write(w.tmp[:binary.PutUvarint(w.tmp[:], v)])
+}
+
+func (w *compactWriter) int(v int64) {
+	w.buf.Write(w.tmp[:binary.PutVarint(w.tmp[:], v)])
+}
+
+func (w *compactWriter) bytes(b []byte) {
+	w.uint(uint64(len(b)))
+	w.buf.Write(b)
+}
+
+func (w *compactWriter) string(s string) {
+	w.uint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (b *Bytecode) encodeCompact(buf *bytes.Buffer) error {
+	w := &compactWriter{buf: buf}
+	w.fileSet(b.FileSet)
+	if err := w.function(b.MainFunction); err != nil {
+		return err
+	}
+	w.uint(uint64(len(b.Constants)))
+	for _, c := range b.Constants {
+		if err := w.object(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *compactWriter) fileSet(s *parser.SourceFileSet) {
+	if s == nil {
+		w.uint(0)
+		return
+	}
+	w.uint(uint64(s.Base))
+	w.uint(uint64(len(s.Files)))
+	for _, f := range s.Files {
+		w.string(f.Name)
+		w.uint(uint64(f.Base))
+		w.uint(uint64(f.Size))
+		w.uint(uint64(len(f.Lines)))
+		prev := 0
+		for _, l := range f.Lines {
+			w.uint(uint64(l - prev))
+			prev = l
+		}
+	}
+}
+
+func (w *compactWriter) function(fn *CompiledFunction) error {
+	w.bytes(fn.Instructions)
+	w.uint(uint64(fn.NumLocals))
+	w.uint(uint64(fn.NumParameters))
+	if fn.VarArgs {
+		w.buf.WriteByte(1)
+	} else {
+		w.buf.WriteByte(0)
+	}
+	ips := make([]int, 0, len(fn.SourceMap))
+	for ip := range fn.SourceMap {
+		ips = append(ips, ip)
+	}
+	sort.Ints(ips)
+	w.uint(uint64(len(ips)))
+	for _, ip := range ips {
+		w.int(int64(ip))
+		w.uint(uint64(fn.SourceMap[ip]))
+	}
+	w.uint(uint64(len(fn.Free)))
+	for _, p := range fn.Free {
+		var v Object
+		if p != nil && p.Value != nil {
+			v = *p.Value
+		}
+		if err := w.object(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *compactWriter) object(o Object) error {
+	switch o := o.(type) {
+	case nil:
+		w.buf.WriteByte(tagNil)
+	case *Undefined:
+		w.buf.WriteByte(tagUndefined)
+	case *Bool:
+		if o.IsFalsy() {
+			w.buf.WriteByte(tagFalse)
+		} else {
+			w.buf.WriteByte(tagTrue)
+		}
+	case *Int:
+		w.buf.WriteByte(tagInt)
+		w.int(o.Value)
+	case *Float:
+		w.buf.WriteByte(tagFloat)
+		w.uint(math.Float64bits(o.Value))
+	case *String:
+		w.buf.WriteByte(tagString)
+		w.string(o.Value)
+	case *Char:
+		w.buf.WriteByte(tagChar)
+		w.int(int64(o.Value))
+	case *Bytes:
+		w.buf.WriteByte(tagBytes)
+		w.bytes(o.Value)
+	case *Time:
+		b, err := o.Value.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		w.buf.WriteByte(tagTime)
+		w.bytes(b)
+	case *Array:
+		w.buf.WriteByte(tagArray)
+		return w.objects(o.Value)
+	case *ImmutableArray:
+		w.buf.WriteByte(tagImmutableArray)
+		return w.objects(o.Value)
+	case *Map:
+		w.buf.WriteByte(tagMap)
+		return w.orderedMap(o.Value)
+	case *ImmutableMap:
+		if name := inferModuleName(o); name != "" {
+			w.buf.WriteByte(tagModule)
+			w.string(name)
+			return nil
+		}
+		w.buf.WriteByte(tagImmutableMap)
+		return w.orderedMap(o.Value)
+	case *Set:
+		w.buf.WriteByte(tagSet)
+		return w.objects(o.Value.Keys())
+	case *ImmutableSet:
+		w.buf.WriteByte(tagImmutableSet)
+		return w.objects(o.Value.Keys())
+	case *Error:
+		w.buf.WriteByte(tagError)
+		return w.object(o.Value)
+	case *CompiledFunction:
+		w.buf.WriteByte(tagCompiledFunction)
+		return w.function(o)
+	default:
+		return fmt.Errorf("bytecode'a yazılamaz: %s", o.TypeName())
+	}
+	return nil
+}
+
+func (w *compactWriter) objects(objs []Object) error {
+	w.uint(uint64(len(objs)))
+	for _, o := range objs {
+		if err := w.object(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *compactWriter) orderedMap(m *OrderedMap) (err error) {
+	w.uint(uint64(m.Len()))
+	m.Range(func(key, value Object) bool {
+		if err = w.object(key); err == nil {
+			err = w.object(value)
+		}
+		return err == nil
+	})
+	return
+}
+
+type compactReader struct {
+	data    []byte
+	modules *ModuleMap
+	err     error
+}
+
+func (b *Bytecode) decodeCompact(data []byte, modules *ModuleMap) error {
+	r := &compactReader{data: data, modules: modules}
+	b.FileSet = r.fileSet()
+	b.MainFunction = r.function()
+	b.Constants = make([]Object, r.len())
+	for i := range b.Constants {
+		b.Constants[i] = r.object()
+	}
+	if r.err == nil && len(r.data) != 0 {
+		r.err = errCompactData
+	}
+	return r.err
+}
+
+func (r *compactReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+	r.data = nil
+}
+
+func (r *compactReader) uint() uint64 {
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail(errCompactData)
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *compactReader) int() int64 {
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail(errCompactData)
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *compactReader) len() int {
+	n := r.uint()
+	if n > uint64(len(r.data)) {
+		r.fail(errCompactData)
+		return 0
+	}
+	return int(n)
+}
+
+func (r *compactReader) byte() byte {
+	if len(r.data) == 0 {
+		r.fail(errCompactData)
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *compactReader) bytes() []byte {
+	n := r.len()
+	b := append([]byte{}, r.data[:n]...)
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *compactReader) string() string {
+	n := r.len()
+	s := string(r.data[:n])
+	r.data = r.data[n:]
+	return s
+}
+
+func (r *compactReader) fileSet() *parser.SourceFileSet {
+	base := int(r.uint())
+	if base == 0 {
+		return nil
+	}
+	s := &parser.SourceFileSet{Base: base}
+	s.Files = make([]*parser.SourceFile, r.len())
+	for i := range s.Files {
+		f := &parser.SourceFile{
+			Name: r.string(),
+			Base: int(r.uint()),
+			Size: int(r.uint()),
+		}
+		f.Lines = make([]int, r.len())
+		prev := 0
+		for j := range f.Lines {
+			prev += int(r.uint())
+			f.Lines[j] = prev
+		}
+		s.Files[i] = f
+	}
+	if len(s.Files) > 0 {
+		s.LastFile = s.Files[len(s.Files)-1]
+	}
+	return s
+}
+
+func (r *compactReader) function() *CompiledFunction {
+	fn := &CompiledFunction{
+		Instructions:  r.bytes(),
+		NumLocals:     int(r.uint()),
+		NumParameters: int(r.uint()),
+		VarArgs:       r.byte() == 1,
+	}
+	n := r.len()
+	fn.SourceMap = make(map[int]parser.Pos, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		ip := int(r.int())
+		fn.SourceMap[ip] = parser.Pos(r.uint())
+	}
+	if n = r.len(); n > 0 {
+		fn.Free = make([]*ObjectPtr, n)
+		for i := range fn.Free {
+			v := r.object()
+			fn.Free[i] = &ObjectPtr{Value: &v}
+		}
+	}
+	return fn
+}
+
+func (r *compactReader) object() Object {
+	switch tag := r.byte(); tag {
+	case tagNil:
+		return nil
+	case tagUndefined:
+		return UndefinedValue
+	case tagFalse:
+		return FalseValue
+	case tagTrue:
+		return TrueValue
+	case tagInt:
+		return &Int{Value: r.int()}
+	case tagFloat:
+		return &Float{Value: math.Float64frombits(r.uint())}
+	case tagString:
+		return &String{Value: r.string()}
+	case tagChar:
+		return &Char{Value: rune(r.int())}
+	case tagBytes:
+		return &Bytes{Value: r.bytes()}
+	case tagTime:
+		var t time.Time
+		if err := t.UnmarshalBinary(r.bytes()); err != nil {
+			r.fail(err)
+		}
+		return &Time{Value: t}
+	case tagArray:
+		return &Array{Value: r.objects()}
+	case tagImmutableArray:
+		return &ImmutableArray{Value: r.objects()}
+	case tagMap:
+		return &Map{Value: r.orderedMap()}
+	case tagImmutableMap:
+		return &ImmutableMap{Value: r.orderedMap()}
+	case tagSet:
+		return &Set{Value: r.set()}
+	case tagImmutableSet:
+		return &ImmutableSet{Value: r.set()}
+	case tagError:
+		return &Error{Value: r.object()}
+	case tagCompiledFunction:
+		return r.function()
+	case tagModule:
+		name := r.string()
+		if mod := r.modules.GetBuiltinModule(name); mod != nil {
+			return mod.AsImmutableMap(name)
+		}
+		r.fail(fmt.Errorf("modül bulunamadı: %s", name))
+		return UndefinedValue
+	default:
+		r.fail(errCompactData)
+		return UndefinedValue
+	}
+}
+
+func (r *compactReader) objects() []Object {
+	objs := make([]Object, r.len())
+	for i := range objs {
+		objs[i] = r.object()
+	}
+	return objs
+}
+
+func (r *compactReader) orderedMap() *OrderedMap {
+	n := r.len()
+	m := NewOrderedMap(n)
+	for i := 0; i < n && r.err == nil; i++ {
+		key := r.object()
+		value := r.object()
+		if err := m.Set(key, value); err != nil {
+			r.fail(err)
+		}
+	}
+	return m
+}
+
+func (r *compactReader) set() *OrderedMap {
+	n := r.len()
+	m := NewOrderedMap(n)
+	for i := 0; i < n && r.err == nil; i++ {
+		if err := m.Set(r.object(), TrueValue); err != nil {
+			r.fail(err)
+		}
+	}
+	return m
+}
@@ -0,0 +1,136 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+const suppressDirective = "denetle:yoksay"
+
+const (
+	RuleUnused       = "L001"
+	RuleShadow       = "L002"
+	RuleUnreachable  = "L003"
+	RuleCondAssign   = "L004"
+	RuleModuleMember = "L005"
+	RuleBranch       = "L006"
+)
+
+type Rule struct {
+	ID   string
+	Name string
+	Doc  string
+}
+
+var Rules = []*Rule{
+	{RuleUnused, "kullanılmayan-değişken",
+		"`:=` ile tanımlanıp hiç okunmayan yerel değişken"},
+	{RuleShadow, "yerleşik-gölgeleme",
+		"yerleşik bir fonksiyonla aynı adı taşıyan tanım"},
+	{RuleUnreachable, "erişilemez-kod",
+		"`dön`, `dur`, `devam` ya da `paylaş` sonrasındaki kod"},
+	{RuleCondAssign, "koşulda-atama",
+		"`eğer` başlığında `:=` yerine yapılan atama"},
+	{RuleModuleMember, "bilinmeyen-modül-üyesi",
+		"gömülü modülde bulunmayan bir üyeye erişim"},
+	{RuleBranch, "döngü-dışı-dal",
+		"fonksiyonun kendi döngüsü dışında kullanılan `dur` ya da `devam`"},
+}
+
+func FindRule(id string) *Rule {
+	for _, r := range Rules {
+		if r.ID == id || r.Name == id {
+			return r
+		}
+	}
+	return nil
+}
+
+type Issue struct {
+	Rule    string `json:"rule"`
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (i *Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: [%s] %s", i.File, i.Line, i.Column,
+		i.Rule, i.Message)
+}
+
+type Config struct {
+	Disabled map[string]bool
+}
+
+func Source(filename string, src []byte, cfg *Config) ([]*Issue, error) {
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile(filename, -1, len(src))
+	p := parser.NewParserWithMode(srcFile, src, nil, parser.ScanComments)
+	file, err := p.ParseFile()
+	if err != nil {
+		return nil, err
+	}
+	return File(file, src, cfg), nil
+}
+
+func File(file *parser.File, src []byte, cfg *Config) []*Issue {
+	l := newLinter(file)
+	l.run()
+
+	suppressed := suppressions(file, src)
+	var issues []*Issue
+	for _, i := range l.issues {
+		if cfg != nil && cfg.Disabled[i.Rule] {
+			continue
+		}
+		if rules := suppressed[i.Line]; rules["*"] || rules[i.Rule] {
+			continue
+		}
+		issues = append(issues, i)
+	}
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].Line != issues[b].Line {
+			return issues[a].Line < issues[b].Line
+		}
+		return issues[a].Column < issues[b].Column
+	})
+	return issues
+}
+
+func suppressions(file *parser.File, src []byte) map[int]map[string]bool {
+	res := make(map[int]map[string]bool)
+	for _, c := range file.Comments {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if !strings.HasPrefix(text, suppressDirective) {
+			continue
+		}
+		pos := file.InputFile.Position(c.Pos())
+		line := pos.Line
+		offset := file.InputFile.Offset(c.Pos())
+		if strings.TrimSpace(string(src[offset-pos.Column+1:offset])) == "" {
+			line++
+		}
+
+		rules := res[line]
+		if rules == nil {
+			rules = make(map[string]bool)
+			res[line] = rules
+		}
+		fields := strings.FieldsFunc(text[len(suppressDirective):],
+			func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(fields) == 0 {
+			rules["*"] = true
+		}
+		for _, f := range fields {
+			if r := FindRule(f); r != nil {
+				rules[r.ID] = true
+			}
+		}
+	}
+	return res
+}
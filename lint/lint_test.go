@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func lintSource(t *testing.T, src string, cfg *Config) []*Issue {
+	issues, err := Source("test.lokum", []byte(src), cfg)
+	if err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return issues
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule string
+		bad  string
+		good string
+		want string
+	}{
+		{RuleUnused,
+			"g := fn() { x := 1 }",
+			"g := fn() { x := 1; dön x }",
+			"test.lokum:1:13: [L001] 'x' tanımlandı ama hiç kullanılmadı"},
+		{RuleShadow,
+			"g := fn(uzunluk) { dön uzunluk }",
+			"g := fn(n) { dön uzunluk(n) }",
+			"test.lokum:1:9: [L002] 'uzunluk' yerleşik fonksiyonu gölgeliyor"},
+		{RuleUnreachable,
+			"g := fn() { dön 1; yazdır(2) }",
+			"g := fn() { yazdır(2); dön 1 }",
+			"test.lokum:1:21: [L003] erişilemez kod"},
+		{RuleCondAssign,
+			"x := 0\neğer x = 1; x > 0 { }",
+			"eğer x := 1; x > 0 { }",
+			"test.lokum:2:9: [L004] koşulda '=' ile atama; " +
+				"'==' ya da ':=' mi kastedildi?"},
+		{RuleModuleMember,
+			"io := kullan(\"io\")\nio.yok()",
+			"io := kullan(\"io\")\nio.yazdır(1)",
+			"test.lokum:2:4: [L005] 'io' modülünde 'yok' bulunmuyor"},
+		{RuleBranch,
+			"tekrarla { g := fn() { dur }; g() }",
+			"tekrarla { dur }",
+			"test.lokum:1:24: [L006] 'dur' döngü dışında kullanılamaz"},
+	}
+	for _, tt := range tests {
+		issues := lintSource(t, tt.bad, nil)
+		if len(issues) != 1 || issues[0].String() != tt.want {
+			t.Fatalf("%s: %v, beklenen %q", tt.rule, issues, tt.want)
+		}
+		if issues[0].Rule != tt.rule || issues[0].Name != FindRule(tt.rule).Name {
+			t.Fatalf("%s: kural %s (%s)", tt.rule, issues[0].Rule, issues[0].Name)
+		}
+		if issues := lintSource(t, tt.good, nil); len(issues) != 0 {
+			t.Fatalf("%s: beklenmeyen sorun: %v", tt.rule, issues)
+		}
+	}
+}
+
+func TestSuppression(t *testing.T) {
+	tests := []struct {
+		src  string
+		want int
+	}{
+		{"g := fn() { x := 1 } // denetle:yoksay", 0},
+		{"g := fn() { x := 1 } // denetle:yoksay L001", 0},
+		{"g := fn() { x := 1 } // denetle:yoksay kullanılmayan-değişken", 0},
+		{"g := fn() { x := 1 } // denetle:yoksay L002", 1},
+		{"// denetle:yoksay L001\ng := fn() { x := 1 }", 0},
+		{"// denetle:yoksay L001\n\ng := fn() { x := 1 }", 1},
+		{"x := 0\neğer x = 1; x > 0 { } // denetle:yoksay L001, L004", 0},
+		{"g := fn() { dön 1; x := 2 } // denetle:yoksay L003", 1},
+	}
+	for _, tt := range tests {
+		if issues := lintSource(t, tt.src, nil); len(issues) != tt.want {
+			t.Fatalf("%q: %v, beklenen %d sorun", tt.src, issues, tt.want)
+		}
+	}
+
+	cfg := &Config{Disabled: map[string]bool{RuleUnused: true}}
+	if issues := lintSource(t, "g := fn() { x := 1 }", cfg); len(issues) != 0 {
+		t.Fatalf("kapatılan kural raporlandı: %v", issues)
+	}
+}
+
+func TestIssueJSON(t *testing.T) {
+	issues := lintSource(t, "uzunluk := 1", nil)
+	data, err := json.Marshal(issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"rule":"L002","name":"yerleşik-gölgeleme","file":"test.lokum",` +
+		`"line":1,"column":1,"message":"'uzunluk' yerleşik fonksiyonu gölgeliyor"}]`
+	if string(data) != want {
+		t.Fatalf("%s\nbeklenen %s", data, want)
+	}
+}
@@ -0,0 +1,348 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/stdlib"
+	"github.com/onrirr/lokum/token"
+)
+
+type definition struct {
+	ident  *parser.Ident
+	report bool
+	used   bool
+	module string
+}
+
+type function struct {
+	table *lokum.SymbolTable
+	loops int
+}
+
+type linter struct {
+	file     *parser.File
+	issues   []*Issue
+	table    *lokum.SymbolTable
+	funcs    []*function
+	defs     map[*lokum.Symbol]*definition
+	builtins map[string]bool
+}
+
+func newLinter(file *parser.File) *linter {
+	l := &linter{
+		file:     file,
+		table:    lokum.NewSymbolTable(),
+		defs:     make(map[*lokum.Symbol]*definition),
+		builtins: make(map[string]bool),
+	}
+	for idx, fn := range lokum.GetAllBuiltinFunctions() {
+		l.table.DefineBuiltin(idx, fn.Name)
+		l.builtins[fn.Name] = true
+	}
+	l.funcs = []*function{{table: l.table}}
+	return l
+}
+
+func (l *linter) run() {
+	l.stmts(l.file.Stmts)
+	l.reportUnused()
+}
+
+func (l *linter) report(
+	rule string,
+	pos parser.Pos,
+	format string,
+	args ...interface{},
+) {
+	p := l.file.InputFile.Set().Position(pos)
+	l.issues = append(l.issues, &Issue{
+		Rule:    rule,
+		Name:    FindRule(rule).Name,
+		File:    p.Filename,
+		Line:    p.Line,
+		Column:  p.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) reportUnused() {
+	for _, d := range l.defs {
+		if d.report && !d.used {
+			l.report(RuleUnused, d.ident.NamePos,
+				"'%s' tanımlandı ama hiç kullanılmadı", d.ident.Name)
+		}
+	}
+}
+
+func (l *linter) fn() *function {
+	return l.funcs[len(l.funcs)-1]
+}
+
+func (l *linter) openBlock() {
+	l.table = l.table.Fork(true)
+}
+
+func (l *linter) closeBlock() {
+	l.table = l.table.Parent(false)
+}
+
+func (l *linter) define(ident *parser.Ident, report bool) *definition {
+	if l.builtins[ident.Name] {
+		l.report(RuleShadow, ident.NamePos,
+			"'%s' yerleşik fonksiyonu gölgeliyor", ident.Name)
+	}
+	sym := l.table.Define(ident.Name)
+	d := &definition{ident: ident, report: report}
+	l.defs[sym] = d
+	return d
+}
+
+func (l *linter) resolve(name string) *definition {
+	sym, _, ok := l.table.Resolve(name, false)
+	if !ok {
+		return nil
+	}
+	for i := len(l.funcs) - 1; sym.Scope == lokum.ScopeFree && i > 0; i-- {
+		sym = l.funcs[i].table.FreeSymbols()[sym.Index]
+	}
+	return l.defs[sym]
+}
+
+func (l *linter) stmts(list []parser.Stmt) bool {
+	terminated, reported := false, false
+	for _, s := range list {
+		if _, ok := s.(*parser.EmptyStmt); ok {
+			continue
+		}
+		if terminated && !reported {
+			l.report(RuleUnreachable, s.Pos(), "erişilemez kod")
+			reported = true
+		}
+		if l.stmt(s) {
+			terminated = true
+		}
+	}
+	return terminated
+}
+
+func (l *linter) stmt(s parser.Stmt) bool {
+	switch s := s.(type) {
+	case *parser.ExprStmt:
+		l.expr(s.Expr)
+	case *parser.AssignStmt:
+		l.assign(s)
+	case *parser.IncDecStmt:
+		l.expr(s.Expr)
+	case *parser.BlockStmt:
+		l.openBlock()
+		defer l.closeBlock()
+		return l.stmts(s.Stmts)
+	case *parser.IfStmt:
+		l.openBlock()
+		defer l.closeBlock()
+		if s.Init != nil {
+			if a, ok := s.Init.(*parser.AssignStmt); ok &&
+				a.Token != token.Define {
+				l.report(RuleCondAssign, a.TokenPos,
+					"koşulda '%s' ile atama; '==' ya da ':=' mi kastedildi?",
+					a.Token)
+			}
+			l.stmt(s.Init)
+		}
+		l.expr(s.Cond)
+		then := l.stmt(s.Body)
+		if s.Else == nil {
+			return false
+		}
+		return l.stmt(s.Else) && then
+	case *parser.ForStmt:
+		l.openBlock()
+		defer l.closeBlock()
+		if s.Init != nil {
+			l.stmt(s.Init)
+		}
+		if s.Cond != nil {
+			l.expr(s.Cond)
+		}
+		l.loop(s.Body)
+		if s.Post != nil {
+			l.stmt(s.Post)
+		}
+	case *parser.ForInStmt:
+		l.expr(s.Iterable)
+		l.openBlock()
+		defer l.closeBlock()
+		if s.Key.Name != "_" {
+			l.define(s.Key, false)
+		}
+		if s.Value.Name != "_" {
+			l.define(s.Value, false)
+		}
+		l.loop(s.Body)
+	case *parser.ReturnStmt:
+		if s.Result != nil {
+			l.expr(s.Result)
+		}
+		return true
+	case *parser.ExportStmt:
+		l.expr(s.Result)
+		return true
+	case *parser.BranchStmt:
+		if l.fn().loops == 0 {
+			l.report(RuleBranch, s.TokenPos,
+				"'%s' döngü dışında kullanılamaz", s.Token)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+func (l *linter) loop(body *parser.BlockStmt) {
+	l.fn().loops++
+	l.stmt(body)
+	l.fn().loops--
+}
+
+func (l *linter) assign(s *parser.AssignStmt) {
+	if s.Token != token.Define {
+		for _, lhs := range s.LHS {
+			if ident, ok := lhs.(*parser.Ident); ok {
+				if s.Token == token.Assign {
+					l.resolve(ident.Name)
+				} else {
+					l.expr(ident)
+				}
+			} else {
+				l.expr(lhs)
+			}
+		}
+		for _, rhs := range s.RHS {
+			l.expr(rhs)
+		}
+		return
+	}
+
+	ident, ok := s.LHS[0].(*parser.Ident)
+	if !ok || len(s.RHS) != 1 {
+		for _, rhs := range s.RHS {
+			l.expr(rhs)
+		}
+		return
+	}
+	_, isFunc := s.RHS[0].(*parser.FuncLit)
+	report := l.table != l.funcs[0].table && ident.Name != "_"
+	var d *definition
+	if isFunc {
+		d = l.define(ident, report)
+	}
+	l.expr(s.RHS[0])
+	if !isFunc {
+		d = l.define(ident, report)
+	}
+	if sym, _, ok := l.table.Resolve(ident.Name, true); ok {
+		sym.LocalAssigned = true
+	}
+	if imp, ok := s.RHS[0].(*parser.ImportExpr); ok {
+		if _, ok := stdlib.BuiltinModules[imp.ModuleName]; ok {
+			d.module = imp.ModuleName
+		}
+	}
+}
+
+func (l *linter) expr(e parser.Expr) {
+	switch e := e.(type) {
+	case *parser.Ident:
+		if d := l.resolve(e.Name); d != nil {
+			d.used = true
+		}
+	case *parser.ArrayLit:
+		for _, elem := range e.Elements {
+			l.expr(elem)
+		}
+	case *parser.MapLit:
+		for _, elem := range e.Elements {
+			l.expr(elem.Value)
+		}
+	case *parser.BinaryExpr:
+		l.expr(e.LHS)
+		l.expr(e.RHS)
+	case *parser.UnaryExpr:
+		l.expr(e.Expr)
+	case *parser.ParenExpr:
+		l.expr(e.Expr)
+	case *parser.CallExpr:
+		l.expr(e.Func)
+		for _, arg := range e.Args {
+			l.expr(arg)
+		}
+	case *parser.CondExpr:
+		l.expr(e.Cond)
+		l.expr(e.True)
+		l.expr(e.False)
+	case *parser.ErrorExpr:
+		l.expr(e.Expr)
+	case *parser.ImmutableExpr:
+		l.expr(e.Expr)
+	case *parser.IndexExpr:
+		l.expr(e.Expr)
+		if e.Index != nil {
+			l.expr(e.Index)
+		}
+	case *parser.SliceExpr:
+		l.expr(e.Expr)
+		if e.Low != nil {
+			l.expr(e.Low)
+		}
+		if e.High != nil {
+			l.expr(e.High)
+		}
+	case *parser.SelectorExpr:
+		l.expr(e.Expr)
+		l.selector(e)
+	case *parser.SpawnExpr:
+		l.expr(e.Call)
+	case *parser.FuncLit:
+		l.funcLit(e)
+	}
+}
+
+func (l *linter) selector(e *parser.SelectorExpr) {
+	var module string
+	switch x := e.Expr.(type) {
+	case *parser.ImportExpr:
+		module = x.ModuleName
+	case *parser.Ident:
+		if d := l.resolve(x.Name); d != nil {
+			module = d.module
+		}
+	}
+	attrs, ok := stdlib.BuiltinModules[module]
+	if !ok {
+		return
+	}
+	sel, ok := e.Sel.(*parser.StringLit)
+	if !ok {
+		return
+	}
+	if _, ok := attrs[sel.Value]; !ok {
+		l.report(RuleModuleMember, sel.ValuePos,
+			"'%s' modülünde '%s' bulunmuyor", module, sel.Value)
+	}
+}
+
+func (l *linter) funcLit(e *parser.FuncLit) {
+	l.table = l.table.Fork(false)
+	l.funcs = append(l.funcs, &function{table: l.table})
+	for _, p := range e.Type.Params.List {
+		l.define(p, false)
+		if sym, _, ok := l.table.Resolve(p.Name, true); ok {
+			sym.LocalAssigned = true
+		}
+	}
+	l.stmt(e.Body)
+	l.funcs = l.funcs[:len(l.funcs)-1]
+	l.table = l.table.Parent(false)
+}
@@ -1,287 +1,363 @@
-package lokum
-
-import (
-	"errors"
-	"fmt"
-	"strconv"
-	"time"
-)
-
-var (
-	MaxStringLen = 2147483647
-
-	MaxBytesLen = 2147483647
-)
-
-const (
-	GlobalsSize = 1024
-
-	StackSize = 2048
-
-	MaxFrames = 1024
-
-	SourceFileExtDefault = ".lokum"
-)
-
-type CallableFunc = func(args ...Object) (ret Object, err error)
-
-func CountObjects(o Object) (c int) {
-	c = 1
-	switch o := o.(type) {
-	case *Array:
-		for _, v := range o.Value {
-			c += CountObjects(v)
-		}
-	case *ImmutableArray:
-		for _, v := range o.Value {
-			c += CountObjects(v)
-		}
-	case *Map:
-		for _, v := range o.Value {
-			c += CountObjects(v)
-		}
-	case *ImmutableMap:
-		for _, v := range o.Value {
-			c += CountObjects(v)
-		}
-	case *Error:
-		c += CountObjects(o.Value)
-	}
-	return
-}
-
-func ToString(o Object) (v string, ok bool) {
-	if o == UndefinedValue {
-		return
-	}
-	ok = true
-	if str, isStr := o.(*String); isStr {
-		v = str.Value
-	} else {
-		v = o.String()
-	}
-	return
-}
-
-func ToInt(o Object) (v int, ok bool) {
-	switch o := o.(type) {
-	case *Int:
-		v = int(o.Value)
-		ok = true
-	case *Float:
-		v = int(o.Value)
-		ok = true
-	case *Char:
-		v = int(o.Value)
-		ok = true
-	case *Bool:
-		if o == TrueValue {
-			v = 1
-		}
-		ok = true
-	case *String:
-		c, err := strconv.ParseInt(o.Value, 10, 64)
-		if err == nil {
-			v = int(c)
-			ok = true
-		}
-	}
-	return
-}
-
-func ToInt64(o Object) (v int64, ok bool) {
-	switch o := o.(type) {
-	case *Int:
-		v = o.Value
-		ok = true
-	case *Float:
-		v = int64(o.Value)
-		ok = true
-	case *Char:
-		v = int64(o.Value)
-		ok = true
-	case *Bool:
-		if o == TrueValue {
-			v = 1
-		}
-		ok = true
-	case *String:
-		c, err := strconv.ParseInt(o.Value, 10, 64)
-		if err == nil {
-			v = c
-			ok = true
-		}
-	}
-	return
-}
-
-func ToFloat64(o Object) (v float64, ok bool) {
-	switch o := o.(type) {
-	case *Int:
-		v = float64(o.Value)
-		ok = true
-	case *Float:
-		v = o.Value
-		ok = true
-	case *String:
-		c, err := strconv.ParseFloat(o.Value, 64)
-		if err == nil {
-			v = c
-			ok = true
-		}
-	}
-	return
-}
-
-func ToBool(o Object) (v bool, ok bool) {
-	ok = true
-	v = !o.IsFalsy()
-	return
-}
-
-func ToRune(o Object) (v rune, ok bool) {
-	switch o := o.(type) {
-	case *Int:
-		v = rune(o.Value)
-		ok = true
-	case *Char:
-		v = o.Value
-		ok = true
-	}
-	return
-}
-
-func ToByteSlice(o Object) (v []byte, ok bool) {
-	switch o := o.(type) {
-	case *Bytes:
-		v = o.Value
-		ok = true
-	case *String:
-		v = []byte(o.Value)
-		ok = true
-	}
-	return
-}
-
-func ToTime(o Object) (v time.Time, ok bool) {
-	switch o := o.(type) {
-	case *Time:
-		v = o.Value
-		ok = true
-	case *Int:
-		v = time.Unix(o.Value, 0)
-		ok = true
-	}
-	return
-}
-
-func ToInterface(o Object) (res interface{}) {
-	switch o := o.(type) {
-	case *Int:
-		res = o.Value
-	case *String:
-		res = o.Value
-	case *Float:
-		res = o.Value
-	case *Bool:
-		res = o == TrueValue
-	case *Char:
-		res = o.Value
-	case *Bytes:
-		res = o.Value
-	case *Array:
-		res = make([]interface{}, len(o.Value))
-		for i, val := range o.Value {
-			res.([]interface{})[i] = ToInterface(val)
-		}
-	case *ImmutableArray:
-		res = make([]interface{}, len(o.Value))
-		for i, val := range o.Value {
-			res.([]interface{})[i] = ToInterface(val)
-		}
-	case *Map:
-		res = make(map[string]interface{})
-		for key, v := range o.Value {
-			res.(map[string]interface{})[key] = ToInterface(v)
-		}
-	case *ImmutableMap:
-		res = make(map[string]interface{})
-		for key, v := range o.Value {
-			res.(map[string]interface{})[key] = ToInterface(v)
-		}
-	case *Time:
-		res = o.Value
-	case *Error:
-		res = errors.New(o.String())
-	case *Undefined:
-		res = nil
-	case Object:
-		return o
-	}
-	return
-}
-
-func FromInterface(v interface{}) (Object, error) {
-	switch v := v.(type) {
-	case nil:
-		return UndefinedValue, nil
-	case string:
-		if len(v) > MaxStringLen {
-			return nil, ErrStringLimit
-		}
-		return &String{Value: v}, nil
-	case int64:
-		return &Int{Value: v}, nil
-	case int:
-		return &Int{Value: int64(v)}, nil
-	case bool:
-		if v {
-			return TrueValue, nil
-		}
-		return FalseValue, nil
-	case rune:
-		return &Char{Value: v}, nil
-	case byte:
-		return &Char{Value: rune(v)}, nil
-	case float64:
-		return &Float{Value: v}, nil
-	case []byte:
-		if len(v) > MaxBytesLen {
-			return nil, ErrBytesLimit
-		}
-		return &Bytes{Value: v}, nil
-	case error:
-		return &Error{Value: &String{Value: v.Error()}}, nil
-	case map[string]Object:
-		return &Map{Value: v}, nil
-	case map[string]interface{}:
-		kv := make(map[string]Object)
-		for vk, vv := range v {
-			vo, err := FromInterface(vv)
-			if err != nil {
-				return nil, err
-			}
-			kv[vk] = vo
-		}
-		return &Map{Value: kv}, nil
-	case []Object:
-		return &Array{Value: v}, nil
-	case []interface{}:
-		arr := make([]Object, len(v))
-		for i, e := range v {
-			vo, err := FromInterface(e)
-			if err != nil {
-				return nil, err
-			}
-			arr[i] = vo
-		}
-		return &Array{Value: arr}, nil
-	case time.Time:
-		return &Time{Value: v}, nil
-	case Object:
-		return v, nil
-	case CallableFunc:
-		return &UserFunction{Value: v}, nil
-	}
-	return nil, fmt.Errorf("cannot convert to object: %T", v)
-}
+package lokum
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	MaxStringLen = 2147483647
+
+	MaxBytesLen = 2147483647
+)
+
+const (
+	GlobalsSize = 1024
+
+	StackSize = 2048
+
+	MaxFrames = 1024
+
+	SourceFileExtDefault = ".lokum"
+
+	Version = "beta"
+)
+
+type CallableFunc = func(args ...Object) (ret Object, err error)
+
+type Interop interface {
+	Call(fn Object, args ...Object) (Object, error)
+	Stdin() io.Reader
+	Stdout() io.Writer
+	Stderr() io.Writer
+}
+
+type InteropFunc = func(vm Interop, args ...Object) (ret Object, err error)
+
+func CountObjects(o Object) (c int) {
+	c = 1
+	switch o := o.(type) {
+	case *Array:
+		for _, v := range o.Value {
+			c += CountObjects(v)
+		}
+	case *ImmutableArray:
+		for _, v := range o.Value {
+			c += CountObjects(v)
+		}
+	case *Map:
+		o.Value.Range(func(_, v Object) bool {
+			c += CountObjects(v)
+			return true
+		})
+	case *ImmutableMap:
+		o.Value.Range(func(_, v Object) bool {
+			c += CountObjects(v)
+			return true
+		})
+	case *Set:
+		c += o.Value.Len()
+	case *ImmutableSet:
+		c += o.Value.Len()
+	case *Error:
+		c += CountObjects(o.Value)
+	}
+	return
+}
+
+func ToString(o Object) (v string, ok bool) {
+	if o == UndefinedValue {
+		return
+	}
+	ok = true
+	if str, isStr := o.(*String); isStr {
+		v = str.Value
+	} else {
+		v = o.String()
+	}
+	return
+}
+
+func ToInt(o Object) (v int, ok bool) {
+	switch o := o.(type) {
+	case *Int:
+		v = int(o.Value)
+		ok = true
+	case *Float:
+		v = int(o.Value)
+		ok = true
+	case *Char:
+		v = int(o.Value)
+		ok = true
+	case *Bool:
+		if o == TrueValue {
+			v = 1
+		}
+		ok = true
+	case *String:
+		c, err := strconv.ParseInt(o.Value, 10, 64)
+		if err == nil {
+			v = int(c)
+			ok = true
+		}
+	}
+	return
+}
+
+func ToInt64(o Object) (v int64, ok bool) {
+	switch o := o.(type) {
+	case *Int:
+		v = o.Value
+		ok = true
+	case *Float:
+		v = int64(o.Value)
+		ok = true
+	case *Char:
+		v = int64(o.Value)
+		ok = true
+	case *Bool:
+		if o == TrueValue {
+			v = 1
+		}
+		ok = true
+	case *String:
+		c, err := strconv.ParseInt(o.Value, 10, 64)
+		if err == nil {
+			v = c
+			ok = true
+		}
+	}
+	return
+}
+
+func ToFloat64(o Object) (v float64, ok bool) {
+	switch o := o.(type) {
+	case *Int:
+		v = float64(o.Value)
+		ok = true
+	case *Float:
+		v = o.Value
+		ok = true
+	case *String:
+		c, err := strconv.ParseFloat(o.Value, 64)
+		if err == nil {
+			v = c
+			ok = true
+		}
+	}
+	return
+}
+
+func ToBool(o Object) (v bool, ok bool) {
+	ok = true
+	v = !o.IsFalsy()
+	return
+}
+
+func ToRune(o Object) (v rune, ok bool) {
+	switch o := o.(type) {
+	case *Int:
+		v = rune(o.Value)
+		ok = true
+	case *Char:
+		v = o.Value
+		ok = true
+	}
+	return
+}
+
+func ToByteSlice(o Object) (v []byte, ok bool) {
+	switch o := o.(type) {
+	case *Bytes:
+		v = o.Value
+		ok = true
+	case *String:
+		v = []byte(o.Value)
+		ok = true
+	}
+	return
+}
+
+func ToTime(o Object) (v time.Time, ok bool) {
+	switch o := o.(type) {
+	case *Time:
+		v = o.Value
+		ok = true
+	case *Int:
+		v = time.Unix(o.Value, 0)
+		ok = true
+	}
+	return
+}
+
+func ToInterface(o Object) (res interface{}) {
+	switch o := o.(type) {
+	case *Int:
+		res = o.Value
+	case *String:
+		res = o.Value
+	case *Float:
+		res = o.Value
+	case *Bool:
+		res = o == TrueValue
+	case *Char:
+		res = o.Value
+	case *Bytes:
+		res = o.Value
+	case *Array:
+		res = make([]interface{}, len(o.Value))
+		for i, val := range o.Value {
+			res.([]interface{})[i] = ToInterface(val)
+		}
+	case *ImmutableArray:
+		res = make([]interface{}, len(o.Value))
+		for i, val := range o.Value {
+			res.([]interface{})[i] = ToInterface(val)
+		}
+	case *Map:
+		res = mapToInterface(o.Value)
+	case *ImmutableMap:
+		res = mapToInterface(o.Value)
+	case *Set:
+		res = setToInterface(o.Value)
+	case *ImmutableSet:
+		res = setToInterface(o.Value)
+	case *Time:
+		res = o.Value
+	case *Error:
+		res = errors.New(o.String())
+	case *Undefined:
+		res = nil
+	case *GoValue:
+		if o.Value.CanInterface() {
+			res = o.Value.Interface()
+		}
+	case Object:
+		return o
+	}
+	return
+}
+
+func FromInterface(v interface{}) (Object, error) {
+	switch v := v.(type) {
+	case nil:
+		return UndefinedValue, nil
+	case string:
+		if len(v) > MaxStringLen {
+			return nil, ErrStringLimit
+		}
+		return &String{Value: v}, nil
+	case int64:
+		return &Int{Value: v}, nil
+	case int:
+		return &Int{Value: int64(v)}, nil
+	case bool:
+		if v {
+			return TrueValue, nil
+		}
+		return FalseValue, nil
+	case rune:
+		return &Char{Value: v}, nil
+	case byte:
+		return &Char{Value: rune(v)}, nil
+	case float64:
+		return &Float{Value: v}, nil
+	case []byte:
+		if len(v) > MaxBytesLen {
+			return nil, ErrBytesLimit
+		}
+		return &Bytes{Value: v}, nil
+	case error:
+		return &Error{Value: &String{Value: v.Error()}}, nil
+	case map[string]Object:
+		return &Map{Value: orderedMapFromStrings(v)}, nil
+	case map[string]interface{}:
+		kv := make(map[string]Object, len(v))
+		for vk, vv := range v {
+			vo, err := FromInterface(vv)
+			if err != nil {
+				return nil, err
+			}
+			kv[vk] = vo
+		}
+		return &Map{Value: orderedMapFromStrings(kv)}, nil
+	case map[interface{}]interface{}:
+		keys := make([]Object, 0, len(v))
+		values := make(map[Object]Object, len(v))
+		for vk, vv := range v {
+			ko, err := FromInterface(vk)
+			if err != nil {
+				return nil, err
+			}
+			vo, err := FromInterface(vv)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, ko)
+			values[ko] = vo
+		}
+		sortKeys(keys)
+		kv := NewOrderedMap(len(keys))
+		for _, ko := range keys {
+			if err := kv.Set(ko, values[ko]); err != nil {
+				return nil, err
+			}
+		}
+		return &Map{Value: kv}, nil
+	case []Object:
+		return &Array{Value: v}, nil
+	case []interface{}:
+		arr := make([]Object, len(v))
+		for i, e := range v {
+			vo, err := FromInterface(e)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = vo
+		}
+		return &Array{Value: arr}, nil
+	case time.Time:
+		return &Time{Value: v}, nil
+	case Object:
+		return v, nil
+	case CallableFunc:
+		return &UserFunction{Value: v}, nil
+	case InteropFunc:
+		return &UserFunction{Interop: v}, nil
+	}
+	return fromValue(reflect.ValueOf(v))
+}
+
+func setToInterface(m *OrderedMap) []interface{} {
+	res := make([]interface{}, 0, m.Len())
+	m.Range(func(key, _ Object) bool {
+		res = append(res, ToInterface(key))
+		return true
+	})
+	return res
+}
+
+func mapToInterface(m *OrderedMap) interface{} {
+	allStrings := true
+	m.Range(func(k, _ Object) bool {
+		_, allStrings = k.(*String)
+		return allStrings
+	})
+	if allStrings {
+		res := make(map[string]interface{}, m.Len())
+		m.Range(func(k, v Object) bool {
+			res[k.(*String).Value] = ToInterface(v)
+			return true
+		})
+		return res
+	}
+	res := make(map[interface{}]interface{}, m.Len())
+	m.Range(func(k, v Object) bool {
+		res[ToInterface(k)] = ToInterface(v)
+		return true
+	})
+	return res
+}
@@ -0,0 +1,131 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+	codeNotInitialized = -32002
+)
+
+type Message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *ResponseError   `json:"error,omitempty"`
+}
+
+func (m *Message) IsRequest() bool {
+	return m.ID != nil && m.Method != ""
+}
+
+func (m *Message) IsNotification() bool {
+	return m.ID == nil && m.Method != ""
+}
+
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("lsp hatası %d: %s", e.Code, e.Message)
+}
+
+type Conn struct {
+	r  *textproto.Reader
+	w  io.Writer
+	mu sync.Mutex
+}
+
+func NewConn(r io.Reader, w io.Writer) *Conn {
+	return &Conn{r: textproto.NewReader(bufio.NewReader(r)), w: w}
+}
+
+func (c *Conn) Read() (*Message, error) {
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("geçersiz Content-Length: %q",
+			header.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		return nil, err
+	}
+	msg := &Message{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, &ResponseError{Code: codeParseError, Message: err.Error()}
+	}
+	return msg, nil
+}
+
+func (c *Conn) Write(msg *Message) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+func (c *Conn) Call(id int, method string, params interface{}) error {
+	raw := json.RawMessage(strconv.Itoa(id))
+	return c.send(&raw, method, params)
+}
+
+func (c *Conn) Notify(method string, params interface{}) error {
+	return c.send(nil, method, params)
+}
+
+func (c *Conn) send(id *json.RawMessage, method string, params interface{}) error {
+	msg := &Message{ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		msg.Params = data
+	}
+	return c.Write(msg)
+}
+
+func (c *Conn) reply(id *json.RawMessage, result interface{}, err error) error {
+	msg := &Message{ID: id}
+	if err != nil {
+		rerr, ok := err.(*ResponseError)
+		if !ok {
+			rerr = &ResponseError{Code: codeInternalError, Message: err.Error()}
+		}
+		msg.Error = rerr
+		return c.Write(msg)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	msg.Result = data
+	return c.Write(msg)
+}
@@ -0,0 +1,95 @@
+package lsp
+
+type doc struct {
+	signature string
+	text      string
+}
+
+var builtinDocs = map[string]doc{
+	"yazdır": {"yazdır(...değerler)",
+		"Değerleri aralarına boşluk koyarak yazdırır ve satırı bitirir."},
+	"uzunluk": {"uzunluk(x) -> int",
+		"Liste, yazı, bytes, harita ya da kümenin eleman sayısını döndürür."},
+	"kopyala": {"kopyala(x)",
+		"Değerin derin bir kopyasını döndürür."},
+	"ekle": {"ekle(liste, ...değerler) -> liste",
+		"Değerleri listenin sonuna ekleyerek yeni bir liste döndürür."},
+	"sil": {"sil(harita, anahtar)",
+		"Anahtarı haritadan siler."},
+	"birleştir": {"birleştir(liste, başlangıç?, silinecek?, ...yeni) -> liste",
+		"Listeden elemanları yerinde siler, yerlerine yenilerini koyar ve " +
+			"silinenleri döndürür."},
+	"yazı": {"yazı(x, varsayılan?) -> string",
+		"Değeri yazıya çevirir; çevrilemezse varsayılanı ya da tanımsız döndürür."},
+	"küme": {"küme(...değerler) -> küme",
+		"Verilen değerlerden bir küme oluşturur."},
+	"sayı": {"sayı(x, varsayılan?) -> int",
+		"Değeri tam sayıya çevirir; çevrilemezse varsayılanı ya da tanımsız döndürür."},
+	"mantıksal": {"mantıksal(x) -> bool",
+		"Değerin doğruluk değerini döndürür."},
+	"float": {"float(x, varsayılan?) -> float",
+		"Değeri ondalıklı sayıya çevirir; çevrilemezse varsayılanı ya da tanımsız döndürür."},
+	"karakter": {"karakter(x, varsayılan?) -> char",
+		"Değeri karaktere çevirir; çevrilemezse varsayılanı ya da tanımsız döndürür."},
+	"bytes": {"bytes(x, varsayılan?) -> bytes",
+		"Yazıyı bytes'a çevirir ya da verilen uzunlukta boş bytes oluşturur."},
+	"sayı_mı":      {"sayı_mı(x) -> bool", "Değer bir tam sayıysa doğru döndürür."},
+	"float_mı":     {"float_mı(x) -> bool", "Değer ondalıklı sayıysa doğru döndürür."},
+	"yazı_mı":      {"yazı_mı(x) -> bool", "Değer bir yazıysa doğru döndürür."},
+	"mantıksal_mı": {"mantıksal_mı(x) -> bool", "Değer mantıksalsa doğru döndürür."},
+	"liste_mi":     {"liste_mi(x) -> bool", "Değer bir listeyse doğru döndürür."},
+	"harita_mı":    {"harita_mı(x) -> bool", "Değer bir haritaysa doğru döndürür."},
+	"küme_mi":      {"küme_mi(x) -> bool", "Değer bir kümeyse doğru döndürür."},
+	"tanımsız_mı":  {"tanımsız_mı(x) -> bool", "Değer tanımsızsa doğru döndürür."},
+	"sınıf": {"sınıf(x) -> string",
+		"Değerin tip adını döndürür."},
+	"f": {"f(biçim, ...değerler) -> string",
+		"Değerleri biçim yazısına göre biçimlendirir."},
+	"aralık": {"aralık(başlangıç, bitiş, adım?) -> liste",
+		"Başlangıçtan bitişe (hariç) adım adım ilerleyen sayıların listesini döndürür."},
+	"kanal": {"kanal(boyut?) -> kanal",
+		"Verilen tampon boyutunda bir kanal oluşturur. Kapanmış ve boşalmış " +
+			"kanaldan al() \"kanal kapalı\" hata değeri döndürür."},
+	"bekle": {"bekle(...görevler)",
+		"Görevlerin bitmesini bekler ve sonuçlarını döndürür."},
+	"seç": {"seç(...kanallar) -> [indeks, değer]",
+		"Kanallardan ilk hazır olanı okur ve indeksiyle birlikte döndürür; " +
+			"kanal kapalıysa değer \"kanal kapalı\" hatasıdır."},
+}
+
+var moduleDocs = map[string]map[string]doc{
+	"io": {
+		"yazdır": {"yazdır(...değerler)",
+			"Değerleri aralarına boşluk koyarak yazdırır ve satırı bitirir."},
+		"yazdırnf": {"yazdırnf(...değerler)",
+			"Değerleri satır sonu eklemeden yazdırır."},
+		"yazdırf": {"yazdırf(biçim, ...değerler)",
+			"Değerleri biçim yazısına göre yazdırır."},
+		"hata_yaz": {"hata_yaz(...değerler)",
+			"Değerleri standart hataya yazdırır ve satırı bitirir."},
+		"sprintf": {"sprintf(biçim, ...değerler) -> string",
+			"Değerleri biçim yazısına göre biçimlendirip döndürür."},
+		"satır_oku": {"satır_oku() -> string",
+			"Standart girdiden bir satır okur."},
+		"tümünü_oku": {"tümünü_oku() -> string",
+			"Standart girdinin tamamını okur."},
+	},
+	"test": {
+		"eşit": {"eşit(beklenen, gerçek, ...mesaj)",
+			"Değerler eşit değilse testi başarısız sayar."},
+		"eşit_değil": {"eşit_değil(a, b, ...mesaj)",
+			"Değerler eşitse testi başarısız sayar."},
+		"doğru_mu": {"doğru_mu(değer, ...mesaj)",
+			"Değer yanlışsa testi başarısız sayar."},
+		"yanlış_mı": {"yanlış_mı(değer, ...mesaj)",
+			"Değer doğruysa testi başarısız sayar."},
+		"hata_bekle": {"hata_bekle(fn, içerir?) -> hata",
+			"Fonksiyonu çağırır; hata vermezse testi başarısız sayar."},
+		"tablo": {"tablo(vakalar, fn)",
+			"Fonksiyonu her vaka için çağırır ve başarısız vakayı adıyla bildirir."},
+		"başarısız": {"başarısız(...mesaj)",
+			"Testi verilen mesajla başarısız sayar."},
+		"atla": {"atla(...sebep)",
+			"Testi atlanmış olarak işaretler ve durdurur."},
+	},
+}
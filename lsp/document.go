@@ -0,0 +1,94 @@
+package lsp
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type document struct {
+	uri   string
+	path  string
+	text  string
+	lines []int
+	index *index
+}
+
+func newDocument(uri, text string) *document {
+	d := &document{uri: uri, path: uri}
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		d.path = u.Path
+	}
+	d.update(text)
+	return d
+}
+
+func (d *document) update(text string) {
+	d.text = text
+	d.lines = []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			d.lines = append(d.lines, i+1)
+		}
+	}
+}
+
+func (d *document) offset(pos Position) int {
+	if pos.Line < 0 {
+		return 0
+	}
+	if pos.Line >= len(d.lines) {
+		return len(d.text)
+	}
+	offset := d.lines[pos.Line]
+	for n := 0; n < pos.Character && offset < len(d.text); {
+		r, size := utf8.DecodeRuneInString(d.text[offset:])
+		if r == '\n' {
+			break
+		}
+		n += len(utf16.Encode([]rune{r}))
+		offset += size
+	}
+	return offset
+}
+
+func (d *document) position(offset int) Position {
+	if offset > len(d.text) {
+		offset = len(d.text)
+	}
+	line := sort.Search(len(d.lines), func(i int) bool {
+		return d.lines[i] > offset
+	}) - 1
+	start := d.lines[line]
+	var char int
+	for _, r := range d.text[start:offset] {
+		char += len(utf16.Encode([]rune{r}))
+	}
+	return Position{Line: line, Character: char}
+}
+
+func (d *document) rangeOf(s span) Range {
+	return Range{Start: d.position(s.start), End: d.position(s.end)}
+}
+
+func (d *document) word(offset int) span {
+	if offset > len(d.text) {
+		offset = len(d.text)
+	}
+	end := offset
+	for end < len(d.text) && isIdentByte(d.text[end]) {
+		end++
+	}
+	if end == offset && end < len(d.text) && !strings.ContainsRune("\r\n", rune(d.text[end])) {
+		_, size := utf8.DecodeRuneInString(d.text[end:])
+		end += size
+	}
+	return span{offset, end}
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= utf8.RuneSelf ||
+		'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9'
+}
@@ -0,0 +1,350 @@
+package lsp
+
+import (
+	"sort"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/stdlib"
+	"github.com/onrirr/lokum/token"
+)
+
+type symbolKind int
+
+const (
+	kindVariable symbolKind = iota
+	kindParameter
+	kindFunction
+	kindModule
+)
+
+type span struct {
+	start, end int
+}
+
+func (s span) contains(offset int) bool {
+	return s.start <= offset && offset <= s.end
+}
+
+type symbol struct {
+	name   string
+	kind   symbolKind
+	decl   span
+	refs   []span
+	module string
+	detail string
+}
+
+type occurrence struct {
+	span
+	sym     *symbol
+	builtin string
+	module  string
+	member  string
+}
+
+type scope struct {
+	span
+	parent *scope
+	syms   []*symbol
+}
+
+type index struct {
+	occs   []*occurrence
+	scopes []*scope
+}
+
+func (x *index) at(offset int) *occurrence {
+	i := sort.Search(len(x.occs), func(i int) bool {
+		return x.occs[i].end >= offset
+	})
+	if i < len(x.occs) && x.occs[i].contains(offset) {
+		return x.occs[i]
+	}
+	return nil
+}
+
+func (x *index) visible(offset int) []*symbol {
+	var inner *scope
+	for _, s := range x.scopes {
+		if s.contains(offset) && (inner == nil || s.start >= inner.start) {
+			inner = s
+		}
+	}
+	seen := make(map[string]bool)
+	var syms []*symbol
+	for s := inner; s != nil; s = s.parent {
+		for i := len(s.syms) - 1; i >= 0; i-- {
+			sym := s.syms[i]
+			if sym.decl.start < offset && !seen[sym.name] {
+				seen[sym.name] = true
+				syms = append(syms, sym)
+			}
+		}
+	}
+	return syms
+}
+
+type indexer struct {
+	file  *parser.File
+	index *index
+	table *lokum.SymbolTable
+	funcs []*lokum.SymbolTable
+	scope *scope
+	syms  map[*lokum.Symbol]*symbol
+}
+
+func newIndex(file *parser.File, size int) *index {
+	x := &indexer{
+		file:  file,
+		index: &index{},
+		table: lokum.NewSymbolTable(),
+		syms:  make(map[*lokum.Symbol]*symbol),
+	}
+	for idx, fn := range lokum.GetAllBuiltinFunctions() {
+		x.table.DefineBuiltin(idx, fn.Name)
+	}
+	x.funcs = []*lokum.SymbolTable{x.table}
+	x.scope = &scope{span: span{0, size}}
+	x.index.scopes = append(x.index.scopes, x.scope)
+
+	x.stmts(file.Stmts)
+	sort.Slice(x.index.occs, func(i, j int) bool {
+		return x.index.occs[i].start < x.index.occs[j].start
+	})
+	return x.index
+}
+
+func (x *indexer) offset(pos parser.Pos) int {
+	return x.file.InputFile.Offset(pos)
+}
+
+func (x *indexer) span(n parser.Node) span {
+	return span{x.offset(n.Pos()), x.offset(n.End())}
+}
+
+func (x *indexer) openScope(n parser.Node, block bool) {
+	x.scope = &scope{span: x.span(n), parent: x.scope}
+	x.index.scopes = append(x.index.scopes, x.scope)
+	x.table = x.table.Fork(block)
+}
+
+func (x *indexer) closeScope() {
+	x.scope = x.scope.parent
+	x.table = x.table.Parent(false)
+}
+
+func (x *indexer) define(ident *parser.Ident, kind symbolKind) *symbol {
+	sym := &symbol{name: ident.Name, kind: kind, decl: x.span(ident)}
+	x.syms[x.table.Define(ident.Name)] = sym
+	x.scope.syms = append(x.scope.syms, sym)
+	x.index.occs = append(x.index.occs, &occurrence{span: sym.decl, sym: sym})
+	if s, _, ok := x.table.Resolve(ident.Name, true); ok {
+		s.LocalAssigned = true
+	}
+	return sym
+}
+
+func (x *indexer) resolve(name string) (*lokum.Symbol, *symbol) {
+	s, _, ok := x.table.Resolve(name, false)
+	if !ok {
+		return nil, nil
+	}
+	for i := len(x.funcs) - 1; s.Scope == lokum.ScopeFree && i > 0; i-- {
+		s = x.funcs[i].FreeSymbols()[s.Index]
+	}
+	return s, x.syms[s]
+}
+
+func (x *indexer) use(ident *parser.Ident) *symbol {
+	s, sym := x.resolve(ident.Name)
+	if s == nil {
+		return nil
+	}
+	occ := &occurrence{span: x.span(ident), sym: sym}
+	if s.Scope == lokum.ScopeBuiltin {
+		occ.builtin = ident.Name
+	} else if sym != nil {
+		sym.refs = append(sym.refs, occ.span)
+	}
+	x.index.occs = append(x.index.occs, occ)
+	return sym
+}
+
+func (x *indexer) stmts(list []parser.Stmt) {
+	for _, s := range list {
+		x.stmt(s)
+	}
+}
+
+func (x *indexer) stmt(s parser.Stmt) {
+	switch s := s.(type) {
+	case *parser.ExprStmt:
+		x.expr(s.Expr)
+	case *parser.AssignStmt:
+		x.assign(s)
+	case *parser.IncDecStmt:
+		x.expr(s.Expr)
+	case *parser.BlockStmt:
+		x.openScope(s, true)
+		x.stmts(s.Stmts)
+		x.closeScope()
+	case *parser.IfStmt:
+		x.openScope(s, true)
+		if s.Init != nil {
+			x.stmt(s.Init)
+		}
+		x.expr(s.Cond)
+		x.stmt(s.Body)
+		if s.Else != nil {
+			x.stmt(s.Else)
+		}
+		x.closeScope()
+	case *parser.ForStmt:
+		x.openScope(s, true)
+		if s.Init != nil {
+			x.stmt(s.Init)
+		}
+		if s.Cond != nil {
+			x.expr(s.Cond)
+		}
+		x.stmt(s.Body)
+		if s.Post != nil {
+			x.stmt(s.Post)
+		}
+		x.closeScope()
+	case *parser.ForInStmt:
+		x.expr(s.Iterable)
+		x.openScope(s, true)
+		if s.Key.Name != "_" {
+			x.define(s.Key, kindVariable)
+		}
+		if s.Value.Name != "_" {
+			x.define(s.Value, kindVariable)
+		}
+		x.stmt(s.Body)
+		x.closeScope()
+	case *parser.ReturnStmt:
+		if s.Result != nil {
+			x.expr(s.Result)
+		}
+	case *parser.ExportStmt:
+		x.expr(s.Result)
+	}
+}
+
+func (x *indexer) assign(s *parser.AssignStmt) {
+	ident, ok := s.LHS[0].(*parser.Ident)
+	if s.Token != token.Define || !ok || len(s.RHS) != 1 {
+		for _, lhs := range s.LHS {
+			x.expr(lhs)
+		}
+		for _, rhs := range s.RHS {
+			x.expr(rhs)
+		}
+		return
+	}
+
+	var sym *symbol
+	fn, isFunc := s.RHS[0].(*parser.FuncLit)
+	if isFunc {
+		sym = x.define(ident, kindFunction)
+		sym.detail = fn.Type.String()
+	}
+	x.expr(s.RHS[0])
+	if !isFunc {
+		sym = x.define(ident, kindVariable)
+	}
+	if imp, ok := s.RHS[0].(*parser.ImportExpr); ok {
+		sym.kind = kindModule
+		sym.module = imp.ModuleName
+	}
+}
+
+func (x *indexer) expr(e parser.Expr) {
+	switch e := e.(type) {
+	case *parser.Ident:
+		x.use(e)
+	case *parser.ArrayLit:
+		for _, elem := range e.Elements {
+			x.expr(elem)
+		}
+	case *parser.MapLit:
+		for _, elem := range e.Elements {
+			x.expr(elem.Value)
+		}
+	case *parser.BinaryExpr:
+		x.expr(e.LHS)
+		x.expr(e.RHS)
+	case *parser.UnaryExpr:
+		x.expr(e.Expr)
+	case *parser.ParenExpr:
+		x.expr(e.Expr)
+	case *parser.CallExpr:
+		x.expr(e.Func)
+		for _, arg := range e.Args {
+			x.expr(arg)
+		}
+	case *parser.CondExpr:
+		x.expr(e.Cond)
+		x.expr(e.True)
+		x.expr(e.False)
+	case *parser.ErrorExpr:
+		x.expr(e.Expr)
+	case *parser.ImmutableExpr:
+		x.expr(e.Expr)
+	case *parser.IndexExpr:
+		x.expr(e.Expr)
+		if e.Index != nil {
+			x.expr(e.Index)
+		}
+	case *parser.SliceExpr:
+		x.expr(e.Expr)
+		if e.Low != nil {
+			x.expr(e.Low)
+		}
+		if e.High != nil {
+			x.expr(e.High)
+		}
+	case *parser.SelectorExpr:
+		x.selector(e)
+	case *parser.SpawnExpr:
+		x.expr(e.Call)
+	case *parser.FuncLit:
+		x.funcLit(e)
+	}
+}
+
+func (x *indexer) selector(e *parser.SelectorExpr) {
+	var module string
+	switch base := e.Expr.(type) {
+	case *parser.ImportExpr:
+		module = base.ModuleName
+	case *parser.Ident:
+		if sym := x.use(base); sym != nil {
+			module = sym.module
+		}
+	default:
+		x.expr(e.Expr)
+	}
+	sel, ok := e.Sel.(*parser.StringLit)
+	if _, builtin := stdlib.BuiltinModules[module]; !ok || !builtin {
+		return
+	}
+	x.index.occs = append(x.index.occs, &occurrence{
+		span:   x.span(sel),
+		module: module,
+		member: sel.Value,
+	})
+}
+
+func (x *indexer) funcLit(e *parser.FuncLit) {
+	x.openScope(e, false)
+	x.funcs = append(x.funcs, x.table)
+	for _, p := range e.Type.Params.List {
+		x.define(p, kindParameter)
+	}
+	x.stmt(e.Body)
+	x.funcs = x.funcs[:len(x.funcs)-1]
+	x.closeScope()
+}
@@ -0,0 +1,429 @@
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/format"
+	"github.com/onrirr/lokum/lint"
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/stdlib"
+	"github.com/onrirr/lokum/token"
+)
+
+var ErrExitWithoutShutdown = errors.New("lsp: shutdown isteği olmadan çıkıldı")
+
+type Server struct {
+	conn        *Conn
+	docs        map[string]*document
+	modules     *lokum.ModuleMap
+	initialized bool
+	shutdown    bool
+}
+
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		conn:    NewConn(in, out),
+		docs:    make(map[string]*document),
+		modules: stdlib.GetModuleMap(stdlib.AllModuleNames()...),
+	}
+}
+
+func (s *Server) Serve() error {
+	for {
+		msg, err := s.conn.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if rerr, ok := err.(*ResponseError); ok {
+			if err := s.conn.reply(nil, nil, rerr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+		if msg.Method == "" {
+			continue
+		}
+		result, err := s.dispatch(msg)
+		if msg.IsNotification() {
+			continue
+		}
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) dispatch(msg *Message) (interface{}, error) {
+	if !s.initialized && msg.Method != "initialize" {
+		return nil, &ResponseError{
+			Code:    codeNotInitialized,
+			Message: "sunucu henüz başlatılmadı",
+		}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.initialized = true
+		return &InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:           1,
+				HoverProvider:              true,
+				DefinitionProvider:         true,
+				ReferencesProvider:         true,
+				DocumentFormattingProvider: true,
+				CompletionProvider: &CompletionOptions{
+					TriggerCharacters: []string{"."},
+				},
+			},
+			ServerInfo: &ServerInfo{Name: "lokum", Version: lokum.Version},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		d := newDocument(params.TextDocument.URI, params.TextDocument.Text)
+		s.docs[d.uri] = d
+		return nil, s.publish(d)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		d := s.docs[params.TextDocument.URI]
+		if d == nil || len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		d.update(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		return nil, s.publish(d)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil, s.conn.Notify("textDocument/publishDiagnostics",
+			&PublishDiagnosticsParams{
+				URI:         params.TextDocument.URI,
+				Diagnostics: []Diagnostic{},
+			})
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		d, occ, err := s.lookup(msg.Params, &params)
+		if err != nil || occ == nil || occ.sym == nil {
+			return nil, err
+		}
+		return &Location{URI: d.uri, Range: d.rangeOf(occ.sym.decl)}, nil
+	case "textDocument/references":
+		var params ReferenceParams
+		d, occ, err := s.lookup(msg.Params, &params)
+		if err != nil || occ == nil || occ.sym == nil {
+			return []Location{}, err
+		}
+		locs := []Location{}
+		if params.Context.IncludeDeclaration {
+			locs = append(locs, Location{URI: d.uri, Range: d.rangeOf(occ.sym.decl)})
+		}
+		for _, ref := range occ.sym.refs {
+			locs = append(locs, Location{URI: d.uri, Range: d.rangeOf(ref)})
+		}
+		return locs, nil
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		d, occ, err := s.lookup(msg.Params, &params)
+		if err != nil || occ == nil {
+			return nil, err
+		}
+		text := hoverText(occ)
+		if text == "" {
+			return nil, nil
+		}
+		r := d.rangeOf(occ.span)
+		return &Hover{
+			Contents: MarkupContent{Kind: "markdown", Value: text},
+			Range:    &r,
+		}, nil
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		d := s.docs[params.TextDocument.URI]
+		if d == nil {
+			return nil, nil
+		}
+		return d.complete(d.offset(params.Position)), nil
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		d := s.docs[params.TextDocument.URI]
+		if d == nil {
+			return nil, nil
+		}
+		res, err := format.Source([]byte(d.text))
+		if err != nil {
+			return nil, nil
+		}
+		edits := []TextEdit{}
+		if string(res) != d.text {
+			edits = append(edits, TextEdit{
+				Range:   Range{End: d.position(len(d.text))},
+				NewText: string(res),
+			})
+		}
+		return edits, nil
+	}
+	if strings.HasPrefix(msg.Method, "$/") {
+		return nil, nil
+	}
+	return nil, &ResponseError{
+		Code:    codeMethodNotFound,
+		Message: "desteklenmeyen metot: " + msg.Method,
+	}
+}
+
+func decode(raw json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &ResponseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) lookup(
+	raw json.RawMessage,
+	params interface{},
+) (*document, *occurrence, error) {
+	if err := decode(raw, params); err != nil {
+		return nil, nil, err
+	}
+	var pos *TextDocumentPositionParams
+	switch p := params.(type) {
+	case *TextDocumentPositionParams:
+		pos = p
+	case *ReferenceParams:
+		pos = &p.TextDocumentPositionParams
+	}
+	d := s.docs[pos.TextDocument.URI]
+	if d == nil || d.index == nil {
+		return d, nil, nil
+	}
+	return d, d.index.at(d.offset(pos.Position)), nil
+}
+
+func (s *Server) publish(d *document) error {
+	return s.conn.Notify("textDocument/publishDiagnostics",
+		&PublishDiagnosticsParams{URI: d.uri, Diagnostics: s.diagnose(d)})
+}
+
+func (s *Server) diagnose(d *document) []Diagnostic {
+	diags := []Diagnostic{}
+	src := []byte(d.text)
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile(d.path, -1, len(src))
+	p := parser.NewParserWithMode(srcFile, src, nil, parser.ScanComments)
+	file, err := p.ParseFile()
+	if err != nil {
+		list, _ := err.(parser.ErrorList)
+		for _, e := range list {
+			diags = append(diags, Diagnostic{
+				Range:    d.rangeOf(d.word(e.Pos.Offset)),
+				Severity: SeverityError,
+				Source:   "lokum",
+				Message:  e.Msg,
+			})
+		}
+		return diags
+	}
+	d.index = newIndex(file, len(src))
+
+	symbolTable := lokum.NewSymbolTable()
+	for idx, fn := range lokum.GetAllBuiltinFunctions() {
+		symbolTable.DefineBuiltin(idx, fn.Name)
+	}
+	c := lokum.NewCompiler(srcFile, symbolTable, nil, s.modules, nil)
+	c.EnableFileImport(true)
+	c.SetImportDir(filepath.Dir(d.path))
+	if err := c.Compile(file); err != nil {
+		diag := Diagnostic{
+			Severity: SeverityError,
+			Source:   "lokum",
+			Message:  err.Error(),
+		}
+		if cerr, ok := err.(*lokum.CompilerError); ok {
+			diag.Message = cerr.Err.Error()
+			pos := cerr.FileSet.Position(cerr.Node.Pos())
+			if pos.Filename == d.path {
+				diag.Range = d.rangeOf(span{pos.Offset,
+					pos.Offset + int(cerr.Node.End()-cerr.Node.Pos())})
+			} else {
+				diag.Message += " (" + pos.String() + ")"
+			}
+		}
+		diags = append(diags, diag)
+	}
+
+	for _, i := range lint.File(file, src, nil) {
+		offset := d.lines[i.Line-1] + i.Column - 1
+		diags = append(diags, Diagnostic{
+			Range:    d.rangeOf(d.word(offset)),
+			Severity: SeverityWarning,
+			Code:     i.Rule,
+			Source:   "denetle",
+			Message:  i.Message,
+		})
+	}
+	return diags
+}
+
+func hoverText(occ *occurrence) string {
+	var sig, text string
+	switch {
+	case occ.builtin != "":
+		d, ok := builtinDocs[occ.builtin]
+		if !ok {
+			return "```lokum\n" + occ.builtin + "\n```\n\nYerleşik fonksiyon."
+		}
+		sig, text = d.signature, d.text
+	case occ.member != "":
+		d, ok := moduleDocs[occ.module][occ.member]
+		if !ok {
+			attr, found := stdlib.BuiltinModules[occ.module][occ.member]
+			if !found {
+				return ""
+			}
+			return "```lokum\n" + occ.module + "." + occ.member + "\n```\n\n" +
+				attr.TypeName()
+		}
+		sig, text = occ.module+"."+d.signature, d.text
+	case occ.sym != nil:
+		sym := occ.sym
+		switch sym.kind {
+		case kindParameter:
+			sig, text = sym.name, "Parametre."
+		case kindFunction:
+			sig, text = sym.name+" := "+sym.detail, "Fonksiyon."
+		case kindModule:
+			sig, text = sym.name+` := kullan("`+sym.module+`")`, "Modül."
+		default:
+			sig, text = sym.name, "Değişken."
+		}
+	default:
+		return ""
+	}
+	return "```lokum\n" + sig + "\n```\n\n" + text
+}
+
+func (d *document) complete(offset int) *CompletionList {
+	list := &CompletionList{Items: []CompletionItem{}}
+	if module := d.memberContext(offset); module != "" {
+		attrs := stdlib.BuiltinModules[module]
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			item := CompletionItem{
+				Label:  name,
+				Kind:   CompletionFunction,
+				Detail: attrs[name].TypeName(),
+			}
+			if doc, ok := moduleDocs[module][name]; ok {
+				item.Detail = doc.signature
+				item.Documentation = &MarkupContent{Kind: "markdown", Value: doc.text}
+			}
+			list.Items = append(list.Items, item)
+		}
+		return list
+	}
+
+	if d.index != nil {
+		for _, sym := range d.index.visible(offset) {
+			item := CompletionItem{Label: sym.name, Kind: CompletionVariable}
+			switch sym.kind {
+			case kindFunction:
+				item.Kind, item.Detail = CompletionFunction, sym.detail
+			case kindModule:
+				item.Kind, item.Detail = CompletionModule, `kullan("`+sym.module+`")`
+			}
+			list.Items = append(list.Items, item)
+		}
+	}
+	for _, fn := range lokum.GetAllBuiltinFunctions() {
+		item := CompletionItem{Label: fn.Name, Kind: CompletionFunction}
+		if doc, ok := builtinDocs[fn.Name]; ok {
+			item.Detail = doc.signature
+			item.Documentation = &MarkupContent{Kind: "markdown", Value: doc.text}
+		}
+		list.Items = append(list.Items, item)
+	}
+	for _, kw := range token.Keywords() {
+		list.Items = append(list.Items, CompletionItem{
+			Label: kw,
+			Kind:  CompletionKeyword,
+		})
+	}
+	return list
+}
+
+func (d *document) memberContext(offset int) string {
+	i := offset
+	for i > 0 && isIdentByte(d.text[i-1]) {
+		i--
+	}
+	if i == 0 || d.text[i-1] != '.' {
+		return ""
+	}
+	i--
+	if strings.HasSuffix(d.text[:i], ")") {
+		start := strings.LastIndex(d.text[:i], token.Import.String()+"(")
+		if start < 0 {
+			return ""
+		}
+		arg := strings.Trim(d.text[start+len(token.Import.String())+1:i-1], "\"` ")
+		if _, ok := stdlib.BuiltinModules[arg]; ok {
+			return arg
+		}
+		return ""
+	}
+	end := i
+	for i > 0 && isIdentByte(d.text[i-1]) {
+		i--
+	}
+	name := d.text[i:end]
+	if d.index == nil || name == "" {
+		return ""
+	}
+	for _, sym := range d.index.visible(offset) {
+		if sym.name == name {
+			if _, ok := stdlib.BuiltinModules[sym.module]; ok {
+				return sym.module
+			}
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,259 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testURI = "file:///proje/ana.lokum"
+
+const testSource = `io := kullan("io")
+topla := fn(a, b) {
+	dön a + b
+}
+x := topla(1, 2)
+io.yazdır(x)
+`
+
+type testClient struct {
+	t        *testing.T
+	conn     *Conn
+	messages chan *Message
+	queue    []*Message
+	id       int
+	done     chan error
+}
+
+func newTestClient(t *testing.T) *testClient {
+	cr, cw := io.Pipe()
+	sr, sw := io.Pipe()
+	c := &testClient{
+		t:        t,
+		conn:     NewConn(sr, cw),
+		messages: make(chan *Message, 100),
+		done:     make(chan error, 1),
+	}
+	go func() {
+		c.done <- NewServer(cr, sw).Serve()
+		_ = sw.Close()
+	}()
+	go func() {
+		defer close(c.messages)
+		for {
+			msg, err := c.conn.Read()
+			if err != nil {
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+	return c
+}
+
+func (c *testClient) next(match func(*Message) bool) *Message {
+	c.t.Helper()
+	for i, msg := range c.queue {
+		if match(msg) {
+			c.queue = append(c.queue[:i], c.queue[i+1:]...)
+			return msg
+		}
+	}
+	for {
+		select {
+		case msg, ok := <-c.messages:
+			if !ok {
+				c.t.Fatal("yanıt beklenirken bağlantı kapandı")
+			}
+			if match(msg) {
+				return msg
+			}
+			c.queue = append(c.queue, msg)
+		case <-time.After(5 * time.Second):
+			c.t.Fatal("yanıt için zaman aşımı")
+		}
+	}
+}
+
+func (c *testClient) call(method string, params interface{}, result interface{}) *ResponseError {
+	c.t.Helper()
+	c.id++
+	if err := c.conn.Call(c.id, method, params); err != nil {
+		c.t.Fatal(err)
+	}
+	want := string(mustJSON(c.t, c.id))
+	msg := c.next(func(m *Message) bool {
+		return m.ID != nil && string(*m.ID) == want && m.Method == ""
+	})
+	if msg.Error != nil {
+		return msg.Error
+	}
+	if result != nil {
+		if err := json.Unmarshal(msg.Result, result); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	return nil
+}
+
+func (c *testClient) mustCall(method string, params interface{}, result interface{}) {
+	c.t.Helper()
+	if err := c.call(method, params, result); err != nil {
+		c.t.Fatalf("%s: %v", method, err)
+	}
+}
+
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	if err := c.conn.Notify(method, params); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) diagnostics() []Diagnostic {
+	c.t.Helper()
+	msg := c.next(func(m *Message) bool {
+		return m.Method == "textDocument/publishDiagnostics"
+	})
+	var params PublishDiagnosticsParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		c.t.Fatal(err)
+	}
+	if params.URI != testURI {
+		c.t.Fatalf("tanı adresi %s", params.URI)
+	}
+	return params.Diagnostics
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func at(line, char int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: testURI},
+		Position:     Position{Line: line, Character: char},
+	}
+}
+
+func TestServerSession(t *testing.T) {
+	c := newTestClient(t)
+
+	pos := at(4, 6)
+	if err := c.call("textDocument/hover", &pos, nil); err == nil || err.Code != codeNotInitialized {
+		t.Fatalf("başlatılmadan önce hata %v, beklenen %d", err, codeNotInitialized)
+	}
+
+	var init InitializeResult
+	c.mustCall("initialize", &InitializeParams{}, &init)
+	if !init.Capabilities.HoverProvider || init.Capabilities.CompletionProvider == nil {
+		t.Fatalf("yetenekler %+v", init.Capabilities)
+	}
+	c.notify("initialized", struct{}{})
+
+	c.notify("textDocument/didOpen", &DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: testURI, LanguageID: "lokum", Text: testSource},
+	})
+	if diags := c.diagnostics(); len(diags) != 0 {
+		t.Fatalf("beklenmeyen tanılar: %+v", diags)
+	}
+
+	var def Location
+	c.mustCall("textDocument/definition", &pos, &def)
+	if want := (Range{Position{1, 0}, Position{1, 5}}); def.URI != testURI || def.Range != want {
+		t.Fatalf("tanım %+v, beklenen %+v", def, want)
+	}
+
+	refs := &ReferenceParams{TextDocumentPositionParams: at(1, 2)}
+	refs.Context.IncludeDeclaration = true
+	var locs []Location
+	c.mustCall("textDocument/references", refs, &locs)
+	if len(locs) != 2 || locs[0].Range.Start.Line != 1 || locs[1].Range.Start.Line != 4 {
+		t.Fatalf("referanslar %+v", locs)
+	}
+
+	var hover Hover
+	c.mustCall("textDocument/hover", &pos, &hover)
+	if !strings.Contains(hover.Contents.Value, "topla := fn(a, b)") {
+		t.Fatalf("üzerine gelme %q", hover.Contents.Value)
+	}
+	member := at(5, 5)
+	c.mustCall("textDocument/hover", &member, &hover)
+	if !strings.Contains(hover.Contents.Value, "io.yazdır(") {
+		t.Fatalf("üye üzerine gelme %q", hover.Contents.Value)
+	}
+
+	var list CompletionList
+	complete := at(5, 3)
+	c.mustCall("textDocument/completion", &complete, &list)
+	labels := make(map[string]int)
+	for _, item := range list.Items {
+		labels[item.Label] = item.Kind
+	}
+	if labels["yazdırf"] != CompletionFunction || labels["topla"] != 0 {
+		t.Fatalf("üye tamamlama %v", labels)
+	}
+	complete = at(5, 0)
+	c.mustCall("textDocument/completion", &complete, &list)
+	labels = make(map[string]int)
+	for _, item := range list.Items {
+		labels[item.Label] = item.Kind
+	}
+	if labels["topla"] != CompletionFunction || labels["io"] != CompletionModule ||
+		labels["uzunluk"] != CompletionFunction || labels["eğer"] != CompletionKeyword {
+		t.Fatalf("tamamlama %v", labels)
+	}
+
+	c.notify("textDocument/didChange", &DidChangeTextDocumentParams{
+		TextDocument:   TextDocumentIdentifier{URI: testURI},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "x:=1\n"}},
+	})
+	if diags := c.diagnostics(); len(diags) != 0 {
+		t.Fatalf("beklenmeyen tanılar: %+v", diags)
+	}
+	var edits []TextEdit
+	c.mustCall("textDocument/formatting", &DocumentFormattingParams{
+		TextDocument: TextDocumentIdentifier{URI: testURI},
+	}, &edits)
+	if len(edits) != 1 || edits[0].NewText != "x := 1\n" {
+		t.Fatalf("biçimlendirme %+v", edits)
+	}
+
+	c.notify("textDocument/didChange", &DidChangeTextDocumentParams{
+		TextDocument:   TextDocumentIdentifier{URI: testURI},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "x := (1\n"}},
+	})
+	diags := c.diagnostics()
+	if len(diags) == 0 || diags[0].Severity != SeverityError {
+		t.Fatalf("ayrıştırma hatası bekleniyordu: %+v", diags)
+	}
+
+	c.notify("textDocument/didClose", &DidCloseTextDocumentParams{
+		TextDocument: TextDocumentIdentifier{URI: testURI},
+	})
+	if diags := c.diagnostics(); len(diags) != 0 {
+		t.Fatalf("kapatınca tanılar temizlenmedi: %+v", diags)
+	}
+
+	c.mustCall("shutdown", nil, nil)
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerExitWithoutShutdown(t *testing.T) {
+	c := newTestClient(t)
+	c.mustCall("initialize", &InitializeParams{}, nil)
+	c.notify("exit", nil)
+	if err := <-c.done; err != ErrExitWithoutShutdown {
+		t.Fatalf("hata %v, beklenen %v", err, ErrExitWithoutShutdown)
+	}
+}
@@ -1,26 +1,44 @@
-package parser
-
-import (
-	"strings"
-)
-
-type File struct {
-	InputFile *SourceFile
-	Stmts     []Stmt
-}
-
-func (n *File) Pos() Pos {
-	return Pos(n.InputFile.Base)
-}
-
-func (n *File) End() Pos {
-	return Pos(n.InputFile.Base + n.InputFile.Size)
-}
-
-func (n *File) String() string {
-	var stmts []string
-	for _, e := range n.Stmts {
-		stmts = append(stmts, e.String())
-	}
-	return strings.Join(stmts, "; ")
-}
+package parser
+
+import (
+	"strings"
+)
+
+type File struct {
+	InputFile *SourceFile
+	Stmts     []Stmt
+	Comments  []*Comment
+}
+
+func (n *File) Pos() Pos {
+	return Pos(n.InputFile.Base)
+}
+
+func (n *File) End() Pos {
+	return Pos(n.InputFile.Base + n.InputFile.Size)
+}
+
+func (n *File) String() string {
+	var stmts []string
+	for _, e := range n.Stmts {
+		stmts = append(stmts, e.String())
+	}
+	return strings.Join(stmts, "; ")
+}
+
+type Comment struct {
+	Slash Pos
+	Text  string
+}
+
+func (c *Comment) Pos() Pos {
+	return c.Slash
+}
+
+func (c *Comment) End() Pos {
+	return Pos(int(c.Slash) + len(c.Text))
+}
+
+func (c *Comment) String() string {
+	return c.Text
+}
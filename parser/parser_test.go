@@ -0,0 +1,72 @@
+package parser
+
+import "testing"
+
+func parseSource(src string) (*File, error) {
+	fileSet := NewFileSet()
+	srcFile := fileSet.AddFile("test", -1, len(src))
+	return NewParser(srcFile, []byte(src), nil).ParseFile()
+}
+
+func TestParseAnnotations(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"x: sayı := 1", "x: sayı := 1"},
+		{"x: sayı|yazı|tanımsız := 1", "x: sayı|yazı|tanımsız := 1"},
+		{"g := fn(a: sayı, b) -> yazı { dön b }",
+			"g := fn(a: sayı, b) -> yazı {dön b}"},
+		{"g := fn(a, ...r: liste) {}", "g := fn(a, ...r: liste) {}"},
+		{"g := fn() -> sayı|tanımsız {}", "g := fn() -> sayı|tanımsız {}"},
+	}
+	for _, tt := range tests {
+		file, err := parseSource(tt.src)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		if got := file.Stmts[0].String(); got != tt.want {
+			t.Fatalf("%s: %q, beklenen %q", tt.src, got, tt.want)
+		}
+	}
+
+	file, err := parseSource("g := fn(a: sayı, b) -> yazı {}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ft := file.Stmts[0].(*AssignStmt).RHS[0].(*FuncLit).Type
+	if typ := ft.Params.Type(0); typ == nil || typ.String() != "sayı" {
+		t.Fatalf("a tipi %v", typ)
+	}
+	if typ := ft.Params.Type(1); typ != nil {
+		t.Fatalf("b tipi %v, beklenen yok", typ)
+	}
+	if ft.Result == nil || ft.Result.String() != "yazı" {
+		t.Fatalf("dönüş tipi %v", ft.Result)
+	}
+}
+
+func TestParseAnnotationErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		msg string
+		pos string
+	}{
+		{"x: := 1", "tip beklenildi':=' bulundu", "test:1:4"},
+		{"x: sayı = 1", "':=' beklenildi'=' bulundu", "test:1:10"},
+		{"x: sayı| := 1", "tip beklenildi':=' bulundu", "test:1:11"},
+		{"g := fn(a:) {}", "tip beklenildi')' bulundu", "test:1:11"},
+		{"g := fn() -> {}", "tip beklenildi'{' bulundu", "test:1:14"},
+	}
+	for _, tt := range tests {
+		_, err := parseSource(tt.src)
+		list, ok := err.(ErrorList)
+		if !ok || len(list) == 0 {
+			t.Fatalf("%s: hata %v", tt.src, err)
+		}
+		if list[0].Msg != tt.msg || list[0].Pos.String() != tt.pos {
+			t.Fatalf("%s: %q %s, beklenen %q %s",
+				tt.src, list[0].Msg, list[0].Pos, tt.msg, tt.pos)
+		}
+	}
+}
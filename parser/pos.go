@@ -1,9 +1,9 @@
-package parser
-
-type Pos int
-
-const NoPos Pos = 0
-
-func (p Pos) IsValid() bool {
-	return p != NoPos
-}
+package parser
+
+type Pos int
+
+const NoPos Pos = 0
+
+func (p Pos) IsValid() bool {
+	return p != NoPos
+}
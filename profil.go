@@ -0,0 +1,232 @@
+package lokum
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+const DefaultProfilePeriod = time.Millisecond
+
+// Profiler, VM'in limit denetimlerinde ve yerel çağrılardan sonra saate
+// bakar; son örnekten beri bir periyot geçmişse o anki yığını, geçen süreyi
+// ve bu arada yapılan ayırmaları tek bir örneğe yazar.
+type Profiler struct {
+	period   time.Duration
+	lock     sync.Mutex
+	locs     map[profileKey]int
+	frames   []ProfileFrame
+	frameIDs map[ProfileFrame]int
+	funcs    map[*byte]profileFunc
+	samples  map[string]*ProfileSample
+	key      []byte
+	buf      [binary.MaxVarintLen64]byte
+	ids      []int
+	start    time.Time
+	duration time.Duration
+	running  bool
+}
+
+type ProfileFrame struct {
+	Function  string
+	File      string
+	Line      int
+	StartLine int
+}
+
+type ProfileSample struct {
+	Stack      []ProfileFrame
+	Count      int64
+	CPU        time.Duration
+	Allocs     int64
+	AllocBytes int64
+}
+
+type profileKey struct {
+	code *byte
+	ip   int
+}
+
+type profileFunc struct {
+	name      string
+	file      string
+	startLine int
+}
+
+func NewProfiler(period time.Duration) *Profiler {
+	if period <= 0 {
+		period = DefaultProfilePeriod
+	}
+	return &Profiler{
+		period:   period,
+		locs:     make(map[profileKey]int),
+		frameIDs: make(map[ProfileFrame]int),
+		funcs:    make(map[*byte]profileFunc),
+		samples:  make(map[string]*ProfileSample),
+	}
+}
+
+func (p *Profiler) Period() time.Duration {
+	return p.period
+}
+
+func (p *Profiler) Start() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.running {
+		p.running = true
+		p.start = time.Now()
+	}
+}
+
+func (p *Profiler) Stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.running {
+		p.running = false
+		p.duration += time.Since(p.start)
+	}
+}
+
+func (p *Profiler) Samples() []*ProfileSample {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	samples := make([]*ProfileSample, 0, len(p.samples))
+	for _, s := range p.samples {
+		c := *s
+		c.Stack = append([]ProfileFrame{}, s.Stack...)
+		samples = append(samples, &c)
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i], samples[j]
+		if a.CPU != b.CPU {
+			return a.CPU > b.CPU
+		}
+		if a.AllocBytes != b.AllocBytes {
+			return a.AllocBytes > b.AllocBytes
+		}
+		return fmt.Sprint(a.Stack) < fmt.Sprint(b.Stack)
+	})
+	return samples
+}
+
+func (p *Profiler) sample(v *VM) {
+	now := time.Now()
+	cpu := now.Sub(v.profLast)
+	if cpu < p.period {
+		return
+	}
+	v.profLast = now
+	allocs := v.profAllocs - v.allocs
+	allocBytes := v.allocBytes - v.profAllocBytes
+	v.profAllocs, v.profAllocBytes = v.allocs, v.allocBytes
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.running {
+		return
+	}
+	p.key = p.key[:0]
+	p.ids = p.ids[:0]
+	skip := 0
+	if v.child {
+		skip = 1
+	}
+	for i := v.framesIndex - 1; i >= skip; i-- {
+		f := &v.frames[i]
+		ip := f.ip
+		if i == v.framesIndex-1 {
+			ip = v.ip
+		}
+		if ip < 0 {
+			ip = 0
+		}
+		if len(f.fn.Instructions) == 0 {
+			continue
+		}
+		id := p.location(v, f.fn, ip)
+		p.ids = append(p.ids, id)
+		n := binary.PutUvarint(p.buf[:], uint64(id))
+		p.key = append(p.key, p.buf[:n]...)
+	}
+
+	s, ok := p.samples[string(p.key)]
+	if !ok {
+		s = &ProfileSample{}
+		for _, id := range p.ids {
+			s.Stack = append(s.Stack, p.frames[id])
+		}
+		p.samples[string(p.key)] = s
+	}
+	s.Count++
+	s.CPU += cpu
+	if allocs > 0 {
+		s.Allocs += allocs
+	}
+	if allocBytes > 0 {
+		s.AllocBytes += allocBytes
+	}
+}
+
+func (p *Profiler) location(v *VM, fn *CompiledFunction, ip int) int {
+	key := profileKey{code: &fn.Instructions[0], ip: ip}
+	if id, ok := p.locs[key]; ok {
+		return id
+	}
+	info, ok := p.funcs[key.code]
+	if !ok {
+		info = profileFunction(v, fn)
+		p.funcs[key.code] = info
+	}
+	pos := v.fileSet.Position(fn.SourcePos(ip))
+	frame := ProfileFrame{
+		Function:  info.name,
+		File:      info.file,
+		Line:      pos.Line,
+		StartLine: info.startLine,
+	}
+	id, ok := p.frameIDs[frame]
+	if !ok {
+		id = len(p.frames)
+		p.frames = append(p.frames, frame)
+		p.frameIDs[frame] = id
+	}
+	p.locs[key] = id
+	return id
+}
+
+func profileFunction(v *VM, fn *CompiledFunction) profileFunc {
+	var info profileFunc
+	first := -1
+	for _, pos := range fn.SourceMap {
+		if pos.IsValid() && (first < 0 || int(pos) < first) {
+			first = int(pos)
+		}
+	}
+	if first >= 0 {
+		pos := v.fileSet.Position(parser.Pos(first))
+		info.file, info.startLine = pos.Filename, pos.Line
+	}
+	switch {
+	case fn.Debug != nil && fn.Debug.Name != "":
+		info.name = fn.Debug.Name
+	case fn == v.frames[0].fn && !v.child:
+		info.name = "(ana)"
+	default:
+		info.name = fmt.Sprintf("fn@%s:%d", info.file, info.startLine)
+	}
+	return info
+}
+
+func (v *VM) SetProfiler(p *Profiler) {
+	v.profiler = p
+}
+
+func (v *VM) markProfile() {
+	v.profAllocs, v.profAllocBytes = v.allocs, v.allocBytes
+	v.profLast = time.Now()
+}
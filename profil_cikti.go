@@ -0,0 +1,249 @@
+package lokum
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+func (p *Profiler) WritePprof(w io.Writer) error {
+	samples := p.Samples()
+	p.lock.Lock()
+	start, duration := p.start, p.duration
+	p.lock.Unlock()
+
+	b := &protoBuffer{strings: map[string]int64{"": 0}, table: []string{""}}
+	valueType := func(typ, unit string) []byte {
+		var t protoBuffer
+		t.int(1, b.str(typ))
+		t.int(2, b.str(unit))
+		return t.data
+	}
+	b.bytes(1, valueType("samples", "count"))
+	b.bytes(1, valueType("cpu", "nanoseconds"))
+	b.bytes(1, valueType("alloc_objects", "count"))
+	b.bytes(1, valueType("alloc_space", "bytes"))
+
+	type funcKey struct {
+		name, file string
+		startLine  int
+	}
+	funcs := make(map[funcKey]uint64)
+	locs := make(map[ProfileFrame]uint64)
+	var funcData, locData [][]byte
+	locationID := func(f ProfileFrame) uint64 {
+		if id, ok := locs[f]; ok {
+			return id
+		}
+		fk := funcKey{f.Function, f.File, f.StartLine}
+		fid, ok := funcs[fk]
+		if !ok {
+			fid = uint64(len(funcs) + 1)
+			funcs[fk] = fid
+			var fn protoBuffer
+			fn.uint(1, fid)
+			fn.int(2, b.str(f.Function))
+			fn.int(3, b.str(f.Function))
+			fn.int(4, b.str(f.File))
+			fn.int(5, int64(f.StartLine))
+			funcData = append(funcData, fn.data)
+		}
+		id := uint64(len(locs) + 1)
+		locs[f] = id
+		var line, loc protoBuffer
+		line.uint(1, fid)
+		line.int(2, int64(f.Line))
+		loc.uint(1, id)
+		loc.bytes(4, line.data)
+		locData = append(locData, loc.data)
+		return id
+	}
+
+	for _, s := range samples {
+		ids := make([]uint64, len(s.Stack))
+		for i, f := range s.Stack {
+			ids[i] = locationID(f)
+		}
+		var sample protoBuffer
+		sample.packed(1, ids)
+		sample.packed(2, []uint64{
+			uint64(s.Count),
+			uint64(s.CPU.Nanoseconds()),
+			uint64(s.Allocs),
+			uint64(s.AllocBytes),
+		})
+		b.bytes(2, sample.data)
+	}
+	for _, loc := range locData {
+		b.bytes(4, loc)
+	}
+	for _, fn := range funcData {
+		b.bytes(5, fn)
+	}
+
+	periodType := valueType("cpu", "nanoseconds")
+	defaultType := b.str("cpu")
+	for _, s := range b.table {
+		b.bytes(6, []byte(s))
+	}
+	if !start.IsZero() {
+		b.int(9, start.UnixNano())
+	}
+	b.int(10, duration.Nanoseconds())
+	b.bytes(11, periodType)
+	b.int(12, p.period.Nanoseconds())
+	b.int(14, defaultType)
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.data); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+type protoBuffer struct {
+	data    []byte
+	strings map[string]int64
+	table   []string
+}
+
+func (b *protoBuffer) str(s string) int64 {
+	if i, ok := b.strings[s]; ok {
+		return i
+	}
+	i := int64(len(b.table))
+	b.strings[s] = i
+	b.table = append(b.table, s)
+	return i
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+	b.data = append(b.data, byte(x))
+}
+
+func (b *protoBuffer) uint(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+	b.varint(uint64(field) << 3)
+	b.varint(x)
+}
+
+func (b *protoBuffer) int(field int, x int64) {
+	b.uint(field, uint64(x))
+}
+
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.varint(uint64(field)<<3 | 2)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+func (b *protoBuffer) packed(field int, xs []uint64) {
+	var p protoBuffer
+	for _, x := range xs {
+		p.varint(x)
+	}
+	b.bytes(field, p.data)
+}
+
+type profileEntry struct {
+	name       string
+	flat, cum  time.Duration
+	allocs     int64
+	allocBytes int64
+}
+
+func (p *Profiler) WriteReport(w io.Writer, n int, byLine bool) error {
+	samples := p.Samples()
+	p.lock.Lock()
+	duration := p.duration
+	p.lock.Unlock()
+
+	label := func(f ProfileFrame) string {
+		if byLine {
+			return fmt.Sprintf("%s:%d (%s)", f.File, f.Line, f.Function)
+		}
+		return f.Function
+	}
+	entries := make(map[string]*profileEntry)
+	entry := func(name string) *profileEntry {
+		e, ok := entries[name]
+		if !ok {
+			e = &profileEntry{name: name}
+			entries[name] = e
+		}
+		return e
+	}
+	var total time.Duration
+	var count int64
+	for _, s := range samples {
+		if len(s.Stack) == 0 {
+			continue
+		}
+		total += s.CPU
+		count += s.Count
+		leaf := entry(label(s.Stack[0]))
+		leaf.flat += s.CPU
+		leaf.allocs += s.Allocs
+		leaf.allocBytes += s.AllocBytes
+		seen := make(map[string]bool, len(s.Stack))
+		for _, f := range s.Stack {
+			name := label(f)
+			if !seen[name] {
+				seen[name] = true
+				entry(name).cum += s.CPU
+			}
+		}
+	}
+
+	list := make([]*profileEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.flat != b.flat {
+			return a.flat > b.flat
+		}
+		if a.cum != b.cum {
+			return a.cum > b.cum
+		}
+		if a.allocBytes != b.allocBytes {
+			return a.allocBytes > b.allocBytes
+		}
+		return a.name < b.name
+	})
+	if n > 0 && len(list) > n {
+		list = list[:n]
+	}
+
+	percent := func(d time.Duration) string {
+		if total == 0 {
+			return "0.00%"
+		}
+		return fmt.Sprintf("%.2f%%", 100*float64(d)/float64(total))
+	}
+	_, err := fmt.Fprintf(w, "Süre: %s, örnek: %d (%s aralıkla), toplam: %s\n",
+		duration.Round(time.Millisecond), count, p.period,
+		total.Round(time.Millisecond))
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "düz\tdüz%\tküm\tküm%\tayırma\tbayt\t")
+	for _, e := range list {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t  %s\n",
+			e.flat.Round(10*time.Microsecond), percent(e.flat),
+			e.cum.Round(10*time.Microsecond), percent(e.cum),
+			e.allocs, e.allocBytes, e.name)
+	}
+	return tw.Flush()
+}
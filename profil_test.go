@@ -0,0 +1,239 @@
+package lokum
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+type protoField struct {
+	num   int
+	value uint64
+	data  []byte
+}
+
+func decodeProto(t *testing.T, data []byte) []protoField {
+	t.Helper()
+	var fields []protoField
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("geçersiz anahtar")
+		}
+		data = data[n:]
+		f := protoField{num: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			f.value, n = binary.Uvarint(data)
+			if n <= 0 {
+				t.Fatalf("%d alanında geçersiz varint", f.num)
+			}
+			data = data[n:]
+		case 2:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				t.Fatalf("%d alanında geçersiz uzunluk", f.num)
+			}
+			f.data = data[n : n+int(size)]
+			data = data[n+int(size):]
+		default:
+			t.Fatalf("%d alanında beklenmeyen tel tipi %d", f.num, key&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func decodePacked(t *testing.T, data []byte) []uint64 {
+	t.Helper()
+	var xs []uint64
+	for len(data) > 0 {
+		x, n := binary.Uvarint(data)
+		if n <= 0 {
+			t.Fatalf("geçersiz paketlenmiş varint")
+		}
+		xs = append(xs, x)
+		data = data[n:]
+	}
+	return xs
+}
+
+func fieldValues(t *testing.T, data []byte) map[int]uint64 {
+	t.Helper()
+	values := make(map[int]uint64)
+	for _, f := range decodeProto(t, data) {
+		if f.data == nil {
+			values[f.num] = f.value
+		}
+	}
+	return values
+}
+
+func TestWritePprof(t *testing.T) {
+	src := `topla := fn(n) {
+	s := []
+	tekrarla i := 0; i < n; i++ {
+		s = ekle(s, yazı(i))
+	}
+	dön uzunluk(s)
+}
+x := topla(2000)
+`
+	c, err := NewScript([]byte(src)).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewProfiler(time.Microsecond)
+	c.SetProfiler(p)
+	p.Start()
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	p.Stop()
+
+	var buf bytes.Buffer
+	if err := p.WritePprof(&buf); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var strs []string
+	var sampleTypes, samples, locations, functions []protoField
+	var period, periodType, defaultType uint64
+	var periodTypeData []byte
+	for _, f := range decodeProto(t, data) {
+		switch f.num {
+		case 1:
+			sampleTypes = append(sampleTypes, f)
+		case 2:
+			samples = append(samples, f)
+		case 4:
+			locations = append(locations, f)
+		case 5:
+			functions = append(functions, f)
+		case 6:
+			strs = append(strs, string(f.data))
+		case 11:
+			periodTypeData = f.data
+		case 12:
+			period = f.value
+		case 14:
+			defaultType = f.value
+		}
+	}
+	str := func(i uint64) string {
+		if i >= uint64(len(strs)) {
+			t.Fatalf("dizin %d dizgi tablosunun dışında", i)
+		}
+		return strs[i]
+	}
+	if len(strs) == 0 || strs[0] != "" {
+		t.Fatalf("dizgi tablosu boş dizgiyle başlamıyor: %q", strs)
+	}
+
+	wantTypes := [][2]string{
+		{"samples", "count"},
+		{"cpu", "nanoseconds"},
+		{"alloc_objects", "count"},
+		{"alloc_space", "bytes"},
+	}
+	if len(sampleTypes) != len(wantTypes) {
+		t.Fatalf("%d örnek tipi, beklenen %d", len(sampleTypes), len(wantTypes))
+	}
+	for i, f := range sampleTypes {
+		v := fieldValues(t, f.data)
+		if got := [2]string{str(v[1]), str(v[2])}; got != wantTypes[i] {
+			t.Fatalf("örnek tipi %d: %v, beklenen %v", i, got, wantTypes[i])
+		}
+	}
+	periodType = fieldValues(t, periodTypeData)[1]
+	if str(periodType) != "cpu" || str(defaultType) != "cpu" {
+		t.Fatalf("periyot tipi %q, varsayılan tip %q", str(periodType), str(defaultType))
+	}
+	if period != uint64(time.Microsecond) {
+		t.Fatalf("periyot %d", period)
+	}
+
+	funcNames := make(map[uint64]string)
+	for _, f := range functions {
+		v := fieldValues(t, f.data)
+		funcNames[v[1]] = str(v[2])
+		if str(v[4]) != "(main)" {
+			t.Fatalf("fonksiyon %s dosyası %q", str(v[2]), str(v[4]))
+		}
+	}
+	locFuncs := make(map[uint64]string)
+	for _, f := range locations {
+		var id uint64
+		var line []byte
+		for _, lf := range decodeProto(t, f.data) {
+			switch lf.num {
+			case 1:
+				id = lf.value
+			case 4:
+				line = lf.data
+			}
+		}
+		v := fieldValues(t, line)
+		name, ok := funcNames[v[1]]
+		if !ok {
+			t.Fatalf("konum %d bilinmeyen fonksiyona bağlı: %d", id, v[1])
+		}
+		if v[2] < 1 || v[2] > 8 {
+			t.Fatalf("konum %d satırı %d", id, v[2])
+		}
+		locFuncs[id] = name
+	}
+
+	var count, allocs, allocBytes uint64
+	leaves := make(map[string]bool)
+	for _, f := range samples {
+		var ids, values []uint64
+		for _, sf := range decodeProto(t, f.data) {
+			switch sf.num {
+			case 1:
+				ids = decodePacked(t, sf.data)
+			case 2:
+				values = decodePacked(t, sf.data)
+			}
+		}
+		if len(values) != len(wantTypes) || len(ids) == 0 {
+			t.Fatalf("örnek %d konum, %d değer içeriyor", len(ids), len(values))
+		}
+		for _, id := range ids {
+			if _, ok := locFuncs[id]; !ok {
+				t.Fatalf("örnek bilinmeyen konuma bağlı: %d", id)
+			}
+		}
+		leaves[locFuncs[ids[0]]] = true
+		count += values[0]
+		allocs += values[2]
+		allocBytes += values[3]
+	}
+
+	var wantCount, wantAllocs, wantBytes int64
+	for _, s := range p.Samples() {
+		wantCount += s.Count
+		wantAllocs += s.Allocs
+		wantBytes += s.AllocBytes
+	}
+	if len(samples) != len(p.Samples()) || count != uint64(wantCount) ||
+		allocs != uint64(wantAllocs) || allocBytes != uint64(wantBytes) {
+		t.Fatalf("örnekler %d/%d/%d/%d, beklenen %d/%d/%d/%d",
+			len(samples), count, allocs, allocBytes,
+			len(p.Samples()), wantCount, wantAllocs, wantBytes)
+	}
+	if !leaves["topla"] {
+		t.Fatalf("topla fonksiyonu için örnek yok: %v", leaves)
+	}
+}
@@ -1,303 +1,685 @@
-package lokum
-
-import (
-	"context"
-	"fmt"
-	"path/filepath"
-	"sync"
-
-	"github.com/onrirr/lokum/parser"
-)
-
-type Script struct {
-	variables        map[string]*Variable
-	modules          ModuleGetter
-	input            []byte
-	maxAllocs        int64
-	maxConstObjects  int
-	enableFileImport bool
-	importDir        string
-}
-
-func NewScript(input []byte) *Script {
-	return &Script{
-		variables:       make(map[string]*Variable),
-		input:           input,
-		maxAllocs:       -1,
-		maxConstObjects: -1,
-	}
-}
-
-func (s *Script) Add(name string, value interface{}) error {
-	obj, err := FromInterface(value)
-	if err != nil {
-		return err
-	}
-	s.variables[name] = &Variable{
-		name:  name,
-		value: obj,
-	}
-	return nil
-}
-
-func (s *Script) Remove(name string) bool {
-	if _, ok := s.variables[name]; !ok {
-		return false
-	}
-	delete(s.variables, name)
-	return true
-}
-
-func (s *Script) SetImports(modules ModuleGetter) {
-	s.modules = modules
-}
-
-func (s *Script) SetImportDir(dir string) error {
-	dir, err := filepath.Abs(dir)
-	if err != nil {
-		return err
-	}
-	s.importDir = dir
-	return nil
-}
-
-func (s *Script) SetMaxAllocs(n int64) {
-	s.maxAllocs = n
-}
-
-func (s *Script) SetMaxConstObjects(n int) {
-	s.maxConstObjects = n
-}
-
-func (s *Script) EnableFileImport(enable bool) {
-	s.enableFileImport = enable
-}
-
-func (s *Script) Compile() (*Compiled, error) {
-	symbolTable, globals, err := s.prepCompile()
-	if err != nil {
-		return nil, err
-	}
-
-	fileSet := parser.NewFileSet()
-	srcFile := fileSet.AddFile("(main)", -1, len(s.input))
-	p := parser.NewParser(srcFile, s.input, nil)
-	file, err := p.ParseFile()
-	if err != nil {
-		return nil, err
-	}
-
-	c := NewCompiler(srcFile, symbolTable, nil, s.modules, nil)
-	c.EnableFileImport(s.enableFileImport)
-	c.SetImportDir(s.importDir)
-	if err := c.Compile(file); err != nil {
-		return nil, err
-	}
-
-	globals = globals[:symbolTable.MaxSymbols()+1]
-
-	globalIndexes := make(map[string]int, len(globals))
-	for _, name := range symbolTable.Names() {
-		symbol, _, _ := symbolTable.Resolve(name, false)
-		if symbol.Scope == ScopeGlobal {
-			globalIndexes[name] = symbol.Index
-		}
-	}
-
-	bytecode := c.Bytecode()
-	bytecode.RemoveDuplicates()
-
-	if s.maxConstObjects >= 0 {
-		cnt := bytecode.CountObjects()
-		if cnt > s.maxConstObjects {
-			return nil, fmt.Errorf("sabit obje limiti geçildi: %d", cnt)
-		}
-	}
-	return &Compiled{
-		globalIndexes: globalIndexes,
-		bytecode:      bytecode,
-		globals:       globals,
-		maxAllocs:     s.maxAllocs,
-	}, nil
-}
-
-func (s *Script) Run() (compiled *Compiled, err error) {
-	compiled, err = s.Compile()
-	if err != nil {
-		return
-	}
-	err = compiled.Run()
-	return
-}
-
-func (s *Script) RunContext(
-	ctx context.Context,
-) (compiled *Compiled, err error) {
-	compiled, err = s.Compile()
-	if err != nil {
-		return
-	}
-	err = compiled.RunContext(ctx)
-	return
-}
-
-func (s *Script) prepCompile() (
-	symbolTable *SymbolTable,
-	globals []Object,
-	err error,
-) {
-	var names []string
-	for name := range s.variables {
-		names = append(names, name)
-	}
-
-	symbolTable = NewSymbolTable()
-	for idx, fn := range builtinFuncs {
-		symbolTable.DefineBuiltin(idx, fn.Name)
-	}
-
-	globals = make([]Object, GlobalsSize)
-
-	for idx, name := range names {
-		symbol := symbolTable.Define(name)
-		if symbol.Index != idx {
-			panic(fmt.Errorf("yanlış sembol dizini: %d != %d",
-				idx, symbol.Index))
-		}
-		globals[symbol.Index] = s.variables[name].value
-	}
-	return
-}
-
-type Compiled struct {
-	globalIndexes map[string]int
-	bytecode      *Bytecode
-	globals       []Object
-	maxAllocs     int64
-	lock          sync.RWMutex
-}
-
-func (c *Compiled) Run() error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	v := NewVM(c.bytecode, c.globals, c.maxAllocs)
-	return v.Run()
-}
-
-func (c *Compiled) RunContext(ctx context.Context) (err error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	v := NewVM(c.bytecode, c.globals, c.maxAllocs)
-	ch := make(chan error, 1)
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				switch e := r.(type) {
-				case string:
-					ch <- fmt.Errorf(e)
-				case error:
-					ch <- e
-				default:
-					ch <- fmt.Errorf("panik: %v", e)
-				}
-			}
-		}()
-		ch <- v.Run()
-	}()
-
-	select {
-	case <-ctx.Done():
-		v.Abort()
-		<-ch
-		err = ctx.Err()
-	case err = <-ch:
-	}
-	return
-}
-
-func (c *Compiled) Clone() *Compiled {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
-	clone := &Compiled{
-		globalIndexes: c.globalIndexes,
-		bytecode:      c.bytecode,
-		globals:       make([]Object, len(c.globals)),
-		maxAllocs:     c.maxAllocs,
-	}
-
-	for idx, g := range c.globals {
-		if g != nil {
-			clone.globals[idx] = g.Copy()
-		}
-	}
-	return clone
-}
-
-func (c *Compiled) IsDefined(name string) bool {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
-	idx, ok := c.globalIndexes[name]
-	if !ok {
-		return false
-	}
-	v := c.globals[idx]
-	if v == nil {
-		return false
-	}
-	return v != UndefinedValue
-}
-
-func (c *Compiled) Get(name string) *Variable {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
-	value := UndefinedValue
-	if idx, ok := c.globalIndexes[name]; ok {
-		value = c.globals[idx]
-		if value == nil {
-			value = UndefinedValue
-		}
-	}
-	return &Variable{
-		name:  name,
-		value: value,
-	}
-}
-
-func (c *Compiled) GetAll() []*Variable {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-
-	var vars []*Variable
-	for name, idx := range c.globalIndexes {
-		value := c.globals[idx]
-		if value == nil {
-			value = UndefinedValue
-		}
-		vars = append(vars, &Variable{
-			name:  name,
-			value: value,
-		})
-	}
-	return vars
-}
-
-func (c *Compiled) Set(name string, value interface{}) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-
-	obj, err := FromInterface(value)
-	if err != nil {
-		return err
-	}
-	idx, ok := c.globalIndexes[name]
-	if !ok {
-		return fmt.Errorf("'%s' tanımlı değil", name)
-	}
-	c.globals[idx] = obj
-	return nil
-}
+package lokum
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onrirr/lokum/parser"
+)
+
+type Script struct {
+	variables        map[string]*Variable
+	modules          ModuleGetter
+	input            []byte
+	maxAllocs        int64
+	maxConstObjects  int
+	enableFileImport bool
+	importDir        string
+	importPaths      []string
+	importFS         fs.FS
+	stdin            io.Reader
+	stdout           io.Writer
+	stderr           io.Writer
+	maxInstructions  int64
+	maxDuration      time.Duration
+	maxAllocBytes    int64
+	maxStringLen     int
+	maxBytesLen      int
+	hooks            Hooks
+}
+
+func NewScript(input []byte) *Script {
+	return &Script{
+		variables:       make(map[string]*Variable),
+		input:           input,
+		maxAllocs:       -1,
+		maxConstObjects: -1,
+		maxInstructions: -1,
+		maxAllocBytes:   -1,
+	}
+}
+
+func (s *Script) Add(name string, value interface{}) error {
+	obj, err := FromInterface(value)
+	if err != nil {
+		return err
+	}
+	s.variables[name] = &Variable{
+		name:  name,
+		value: obj,
+	}
+	return nil
+}
+
+func (s *Script) Remove(name string) bool {
+	if _, ok := s.variables[name]; !ok {
+		return false
+	}
+	delete(s.variables, name)
+	return true
+}
+
+func (s *Script) SetImports(modules ModuleGetter) {
+	s.modules = modules
+}
+
+func (s *Script) SetImportDir(dir string) error {
+	s.importDir = dir
+	return nil
+}
+
+func (s *Script) SetImportFS(fsys fs.FS) {
+	s.importFS = fsys
+}
+
+func (s *Script) SetImportPaths(paths ...string) {
+	s.importPaths = paths
+}
+
+func (s *Script) SetMaxAllocs(n int64) {
+	s.maxAllocs = n
+}
+
+func (s *Script) SetMaxInstructions(n int64) {
+	s.maxInstructions = n
+}
+
+func (s *Script) SetMaxDuration(d time.Duration) {
+	s.maxDuration = d
+}
+
+// SetMaxAllocBytes, VM.SetMaxAllocBytes gibi anlık bellek kullanımını değil
+// çalışma boyunca ayrılan bayt miktarını sınırlar.
+func (s *Script) SetMaxAllocBytes(n int64) {
+	s.maxAllocBytes = n
+}
+
+func (s *Script) SetMaxStringLen(n int) {
+	s.maxStringLen = n
+}
+
+func (s *Script) SetMaxBytesLen(n int) {
+	s.maxBytesLen = n
+}
+
+func (s *Script) SetMaxConstObjects(n int) {
+	s.maxConstObjects = n
+}
+
+func (s *Script) SetHooks(h Hooks) {
+	s.hooks = h
+}
+
+func (s *Script) SetStdin(r io.Reader) {
+	if _, ok := r.(*bufio.Reader); !ok && r != nil {
+		r = bufio.NewReader(r)
+	}
+	s.stdin = r
+}
+
+func (s *Script) SetStdout(w io.Writer) {
+	s.stdout = w
+}
+
+func (s *Script) SetStderr(w io.Writer) {
+	s.stderr = w
+}
+
+func (s *Script) EnableFileImport(enable bool) {
+	s.enableFileImport = enable
+}
+
+func (s *Script) Compile() (*Compiled, error) {
+	symbolTable, globals, err := s.prepCompile()
+	if err != nil {
+		return nil, err
+	}
+
+	fileSet := parser.NewFileSet()
+	srcFile := fileSet.AddFile("(main)", -1, len(s.input))
+	p := parser.NewParser(srcFile, s.input, nil)
+	file, err := p.ParseFile()
+	if err != nil {
+		return nil, err
+	}
+
+	importDir := s.importDir
+	if s.importFS == nil {
+		// dizin, SetImportFS ile SetImportDir'in çağrılma sırasından
+		// bağımsız olarak derleme anında çözülür
+		if importDir, err = filepath.Abs(importDir); err != nil {
+			return nil, err
+		}
+	}
+
+	c := NewCompiler(srcFile, symbolTable, nil, s.modules, nil)
+	c.EnableFileImport(s.enableFileImport)
+	c.SetImportDir(importDir)
+	c.SetImportPaths(s.importPaths...)
+	c.SetImportFS(s.importFS)
+	if err := c.Compile(file); err != nil {
+		return nil, err
+	}
+
+	globals = globals[:symbolTable.MaxSymbols()+1]
+
+	globalIndexes := make(map[string]int, len(globals))
+	for _, name := range symbolTable.Names() {
+		symbol, _, _ := symbolTable.Resolve(name, false)
+		if symbol.Scope == ScopeGlobal {
+			globalIndexes[name] = symbol.Index
+		}
+	}
+
+	bytecode := c.Bytecode()
+	bytecode.RemoveDuplicates()
+
+	if s.maxConstObjects >= 0 {
+		cnt := bytecode.CountObjects()
+		if cnt > s.maxConstObjects {
+			return nil, fmt.Errorf("sabit obje limiti geçildi: %d", cnt)
+		}
+	}
+	return &Compiled{
+		globalIndexes:   globalIndexes,
+		bytecode:        bytecode,
+		globals:         globals,
+		maxAllocs:       s.maxAllocs,
+		stdin:           s.stdin,
+		stdout:          s.stdout,
+		stderr:          s.stderr,
+		maxInstructions: s.maxInstructions,
+		maxDuration:     s.maxDuration,
+		maxAllocBytes:   s.maxAllocBytes,
+		hooks:           s.hooks,
+		maxStringLen:    s.maxStringLen,
+		maxBytesLen:     s.maxBytesLen,
+	}, nil
+}
+
+func (s *Script) Run() (compiled *Compiled, err error) {
+	compiled, err = s.Compile()
+	if err != nil {
+		return
+	}
+	err = compiled.Run()
+	return
+}
+
+func (s *Script) RunContext(
+	ctx context.Context,
+) (compiled *Compiled, err error) {
+	compiled, err = s.Compile()
+	if err != nil {
+		return
+	}
+	err = compiled.RunContext(ctx)
+	return
+}
+
+func (s *Script) prepCompile() (
+	symbolTable *SymbolTable,
+	globals []Object,
+	err error,
+) {
+	var names []string
+	for name := range s.variables {
+		names = append(names, name)
+	}
+
+	symbolTable = NewSymbolTable()
+	for idx, fn := range builtinFuncs {
+		symbolTable.DefineBuiltin(idx, fn.Name)
+	}
+
+	globals = make([]Object, GlobalsSize)
+
+	for idx, name := range names {
+		symbol := symbolTable.Define(name)
+		if symbol.Index != idx {
+			panic(fmt.Errorf("yanlış sembol dizini: %d != %d",
+				idx, symbol.Index))
+		}
+		globals[symbol.Index] = s.variables[name].value
+	}
+	return
+}
+
+type Compiled struct {
+	globalIndexes   map[string]int
+	bytecode        *Bytecode
+	globals         []Object
+	maxAllocs       int64
+	stdin           io.Reader
+	stdout          io.Writer
+	stderr          io.Writer
+	maxInstructions int64
+	maxDuration     time.Duration
+	maxAllocBytes   int64
+	maxStringLen    int
+	maxBytesLen     int
+	hooks           Hooks
+	debugger        *Debugger
+	profiler        *Profiler
+	coverage        *Coverage
+	stats           RunStats
+	lock            sync.RWMutex
+	shared          *sharedGlobals
+	shareLock       sync.Mutex
+}
+
+func (c *Compiled) newVM(globals []Object) *VM {
+	v := NewVM(c.bytecode, globals, c.maxAllocs)
+	v.SetStdin(c.stdin)
+	v.SetStdout(c.stdout)
+	v.SetStderr(c.stderr)
+	v.SetMaxInstructions(c.maxInstructions)
+	v.SetMaxDuration(c.maxDuration)
+	v.SetMaxAllocBytes(c.maxAllocBytes)
+	v.SetMaxStringLen(c.maxStringLen)
+	v.SetMaxBytesLen(c.maxBytesLen)
+	v.SetHooks(c.hooks)
+	v.SetDebugger(c.debugger)
+	v.SetProfiler(c.profiler)
+	v.SetCoverage(c.coverage)
+	return v
+}
+
+func (c *Compiled) Debug(handler DebugHandler) *Debugger {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.debugger = NewDebugger(c.bytecode, handler)
+	return c.debugger
+}
+
+func (c *Compiled) SetProfiler(p *Profiler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.profiler = p
+}
+
+func (c *Compiled) SetCoverage(cov *Coverage) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.coverage = cov
+}
+
+func (c *Compiled) Stats() RunStats {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.stats
+}
+
+func (c *Compiled) Run() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.shared = nil
+	v := c.newVM(c.globals)
+	err := v.Run()
+	c.stats = v.Stats()
+	return err
+}
+
+func (c *Compiled) RunContext(ctx context.Context) (err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.shared = nil
+	v := c.newVM(c.globals)
+	err = runContext(ctx, v, v.Run)
+	c.stats = v.Stats()
+	return err
+}
+
+func (c *Compiled) Call(
+	name string,
+	args ...interface{},
+) (*Variable, error) {
+	return c.CallContext(context.Background(), name, args...)
+}
+
+func (c *Compiled) CallContext(
+	ctx context.Context,
+	name string,
+	args ...interface{},
+) (*Variable, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.shared = nil
+	v := c.newVM(c.globals)
+	res, err := c.call(ctx, v, name, args)
+	c.stats = v.Stats()
+	return res, err
+}
+
+func (c *Compiled) call(
+	ctx context.Context,
+	v *VM,
+	name string,
+	args []interface{},
+) (*Variable, error) {
+	idx, ok := c.globalIndexes[name]
+	if !ok || v.globals[idx] == nil {
+		return nil, fmt.Errorf("tanımlanmamış değişken: %s", name)
+	}
+	fn := v.global(idx)
+	objs := make([]Object, len(args))
+	for i, arg := range args {
+		obj, err := FromInterface(arg)
+		if err != nil {
+			return nil, err
+		}
+		objs[i] = obj
+	}
+
+	var ret Object
+	err := runContext(ctx, v, func() (err error) {
+		ret, err = v.Call(fn, objs...)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Variable{name: name, value: ret}, nil
+}
+
+func runContext(ctx context.Context, v *VM, run func() error) (err error) {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- runRecover(run)
+	}()
+
+	select {
+	case <-ctx.Done():
+		v.Abort()
+		<-ch
+		err = ctx.Err()
+	case err = <-ch:
+	}
+	return
+}
+
+func runRecover(run func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch e := r.(type) {
+			case string:
+				err = fmt.Errorf(e)
+			case error:
+				err = e
+			default:
+				err = fmt.Errorf("panik: %v", e)
+			}
+		}
+	}()
+	return run()
+}
+
+func (c *Compiled) Clone() *Compiled {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	clone := &Compiled{
+		globalIndexes:   c.globalIndexes,
+		bytecode:        c.bytecode,
+		globals:         make([]Object, len(c.globals)),
+		maxAllocs:       c.maxAllocs,
+		stdin:           c.stdin,
+		stdout:          c.stdout,
+		stderr:          c.stderr,
+		maxInstructions: c.maxInstructions,
+		maxDuration:     c.maxDuration,
+		maxAllocBytes:   c.maxAllocBytes,
+		hooks:           c.hooks,
+		profiler:        c.profiler,
+		coverage:        c.coverage,
+		maxStringLen:    c.maxStringLen,
+		maxBytesLen:     c.maxBytesLen,
+	}
+
+	for idx, g := range c.globals {
+		if g != nil {
+			clone.globals[idx] = g.Copy()
+		}
+	}
+	return clone
+}
+
+func (c *Compiled) IsDefined(name string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	idx, ok := c.globalIndexes[name]
+	if !ok {
+		return false
+	}
+	v := c.globals[idx]
+	if v == nil {
+		return false
+	}
+	return v != UndefinedValue
+}
+
+func (c *Compiled) Get(name string) *Variable {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	value := UndefinedValue
+	if idx, ok := c.globalIndexes[name]; ok {
+		value = c.globals[idx]
+		if value == nil {
+			value = UndefinedValue
+		}
+	}
+	return &Variable{
+		name:  name,
+		value: value,
+	}
+}
+
+func (c *Compiled) GetAll() []*Variable {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	names := make([]string, 0, len(c.globalIndexes))
+	for name := range c.globalIndexes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return c.globalIndexes[names[i]] < c.globalIndexes[names[j]]
+	})
+
+	var vars []*Variable
+	for _, name := range names {
+		value := c.globals[c.globalIndexes[name]]
+		if value == nil {
+			value = UndefinedValue
+		}
+		vars = append(vars, &Variable{
+			name:  name,
+			value: value,
+		})
+	}
+	return vars
+}
+
+func (c *Compiled) Set(name string, value interface{}) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	obj, err := FromInterface(value)
+	if err != nil {
+		return err
+	}
+	idx, ok := c.globalIndexes[name]
+	if !ok {
+		return fmt.Errorf("'%s' tanımlı değil", name)
+	}
+	c.globals[idx] = obj
+	c.shared = nil
+	return nil
+}
+
+type Run struct {
+	compiled *Compiled
+	globals  []Object
+	injected *injectedGlobals
+	stats    RunStats
+}
+
+type sharedGlobals struct {
+	copyOnRead []bool
+}
+
+type injectedGlobals struct {
+	*sharedGlobals
+	lazy   []bool
+	copies map[Object]Object
+}
+
+func newSharedGlobals(globals []Object) *sharedGlobals {
+	s := &sharedGlobals{copyOnRead: make([]bool, len(globals))}
+	for idx, g := range globals {
+		s.copyOnRead[idx] = g != nil && !readOnly(g)
+	}
+	return s
+}
+
+func readOnly(o Object) bool {
+	switch o := o.(type) {
+	case *Int, *Float, *String, *Bool, *Char, *Undefined, *Bytes,
+		*BuiltinFunction, *UserFunction, *ImmutableSet, *Time:
+		return true
+	case *ImmutableArray:
+		for _, e := range o.Value {
+			if !readOnly(e) {
+				return false
+			}
+		}
+		return true
+	case *ImmutableMap:
+		ok := true
+		o.Value.Range(func(_, value Object) bool {
+			ok = readOnly(value)
+			return ok
+		})
+		return ok
+	case *Error:
+		return readOnly(o.Value)
+	case *CompiledFunction:
+		return len(o.Free) == 0
+	}
+	return false
+}
+
+func (c *Compiled) sharedGlobals() *sharedGlobals {
+	c.shareLock.Lock()
+	defer c.shareLock.Unlock()
+	if c.shared == nil {
+		c.shared = newSharedGlobals(c.globals)
+	}
+	return c.shared
+}
+
+func (c *Compiled) NewRun() *Run {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	r := &Run{
+		compiled: c,
+		globals:  make([]Object, len(c.globals)),
+		injected: &injectedGlobals{
+			sharedGlobals: c.sharedGlobals(),
+			lazy:          make([]bool, len(c.globals)),
+			copies:        make(map[Object]Object),
+		},
+	}
+	copy(r.globals, c.globals)
+	copy(r.injected.lazy, r.injected.copyOnRead)
+	return r
+}
+
+func (r *Run) newVM() *VM {
+	v := r.compiled.newVM(r.globals)
+	v.injected = r.injected
+	return v
+}
+
+func (r *Run) Run() error {
+	v := r.newVM()
+	err := v.Run()
+	r.stats = v.Stats()
+	return err
+}
+
+func (r *Run) RunContext(ctx context.Context) error {
+	v := r.newVM()
+	err := runContext(ctx, v, v.Run)
+	r.stats = v.Stats()
+	return err
+}
+
+func (r *Run) Call(name string, args ...interface{}) (*Variable, error) {
+	return r.CallContext(context.Background(), name, args...)
+}
+
+func (r *Run) CallContext(
+	ctx context.Context,
+	name string,
+	args ...interface{},
+) (*Variable, error) {
+	v := r.newVM()
+	res, err := r.compiled.call(ctx, v, name, args)
+	r.stats = v.Stats()
+	return res, err
+}
+
+func (r *Run) Stats() RunStats {
+	return r.stats
+}
+
+func (r *Run) IsDefined(name string) bool {
+	idx, ok := r.compiled.globalIndexes[name]
+	if !ok {
+		return false
+	}
+	v := r.globals[idx]
+	return v != nil && v != UndefinedValue
+}
+
+func (r *Run) Get(name string) *Variable {
+	value := UndefinedValue
+	if idx, ok := r.compiled.globalIndexes[name]; ok {
+		value = r.globals[idx]
+		if value == nil {
+			value = UndefinedValue
+		}
+	}
+	return &Variable{
+		name:  name,
+		value: value,
+	}
+}
+
+func (r *Run) Set(name string, value interface{}) error {
+	obj, err := FromInterface(value)
+	if err != nil {
+		return err
+	}
+	idx, ok := r.compiled.globalIndexes[name]
+	if !ok {
+		return fmt.Errorf("'%s' tanımlı değil", name)
+	}
+	r.globals[idx] = obj
+	r.injected.lazy[idx] = false
+	return nil
+}
@@ -0,0 +1,311 @@
+package lokum
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRunIsolatesInjectedGlobals(t *testing.T) {
+	s := NewScript([]byte(`
+okunan := ayar.ad + yazı(uzunluk(ayar.liste))
+ayar.liste[0] = 10
+ayar.iç.x = 2
+m := ayar.iç
+m.y = 3
+l := liste
+l[1] = "b"
+sil(harita, "a")
+k.ekle(4)
+sonuç := [ayar.liste[0], ayar.iç.x, ayar.iç.y, liste[1], uzunluk(harita), k.içerir(4)]
+`))
+	for name, value := range map[string]interface{}{
+		"ayar": map[string]interface{}{
+			"ad":    "a",
+			"liste": []interface{}{1, 2},
+			"iç":    map[string]interface{}{"x": 1},
+		},
+		"liste":  []interface{}{"a", "a"},
+		"harita": map[string]interface{}{"a": 1},
+		"k":      &Set{Value: NewOrderedMap(0)},
+	} {
+		if err := s.Add(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := c.Get("ayar").Map()
+
+	for i := 0; i < 2; i++ {
+		r := c.NewRun()
+		if err := r.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Get("okunan").String(); got != "a2" {
+			t.Fatalf("okunan %q", got)
+		}
+		got := r.Get("sonuç").Array()
+		want := []interface{}{int64(10), int64(2), int64(3), "b", int64(0), true}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%v, beklenen %v", got, want)
+		}
+	}
+
+	if after := c.Get("ayar").Map(); !reflect.DeepEqual(before, after) {
+		t.Fatalf("enjekte değer değişti: %v", after)
+	}
+	if got := c.Get("liste").Array(); got[1] != "a" {
+		t.Fatalf("liste değişti: %v", got)
+	}
+	if got := c.Get("harita").Map(); len(got) != 1 {
+		t.Fatalf("harita değişti: %v", got)
+	}
+	if got := c.Get("k").Object().(*Set).Value.Len(); got != 0 {
+		t.Fatalf("küme değişti: %d", got)
+	}
+}
+
+func TestRunInjectedAliases(t *testing.T) {
+	s := NewScript([]byte(`
+x := m
+y := m
+x.a = 5
+z := n
+sil(z, "a")
+p.x = 2
+sonuç := [x.a, y.a, m.a, uzunluk(z), uzunluk(n), q.x]
+`))
+	shared := &Map{Value: orderedMapFromStrings(map[string]Object{"x": &Int{Value: 1}})}
+	for name, value := range map[string]interface{}{
+		"m": map[string]interface{}{"a": 1},
+		"n": map[string]interface{}{"a": 1},
+		"p": shared,
+		"q": shared,
+	} {
+		if err := s.Add(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{int64(5), int64(5), int64(5), int64(0), int64(0), int64(2)}
+
+	r := c.NewRun()
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Get("sonuç").Array(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("NewRun: %v, beklenen %v", got, want)
+	}
+	if got := c.Get("m").Map()["a"]; got != int64(1) {
+		t.Fatalf("enjekte değer değişti: %v", got)
+	}
+
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Get("sonuç").Array(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Run: %v, beklenen %v", got, want)
+	}
+}
+
+func TestRunConcurrentAppend(t *testing.T) {
+	s := NewScript([]byte(`x := ekle(liste, i); y := x[1]`))
+	if err := s.Add("liste", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("i", 0); err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr := make([]Object, 1, 16)
+	arr[0] = &Int{Value: 0}
+	if err := c.Set("liste", &Array{Value: arr}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := c.NewRun()
+			if err := r.Set("i", i); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := r.Run(); err != nil {
+				t.Error(err)
+				return
+			}
+			if got := r.Get("y").Int(); got != i {
+				t.Errorf("%d, beklenen %d", got, i)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if arr[:2][1] != nil {
+		t.Fatalf("enjekte listenin kapasitesine yazıldı: %v", arr[:2][1])
+	}
+}
+
+func BenchmarkRunInjectedRead(b *testing.B) {
+	data := make([]interface{}, 10000)
+	for i := range data {
+		data[i] = map[string]interface{}{"x": i}
+	}
+	s := NewScript([]byte(`x := veri[0].x`))
+	if err := s.Add("veri", data); err != nil {
+		b.Fatal(err)
+	}
+	c, err := s.Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := c.NewRun().Run(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSetImportDirWithFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"lib/yardım.lokum": &fstest.MapFile{Data: []byte(`paylaş 42`)},
+	}
+	for _, dirFirst := range []bool{true, false} {
+		s := NewScript([]byte(`x := kullan("yardım")`))
+		s.EnableFileImport(true)
+		if dirFirst {
+			if err := s.SetImportDir("lib"); err != nil {
+				t.Fatal(err)
+			}
+			s.SetImportFS(fsys)
+		} else {
+			s.SetImportFS(fsys)
+			if err := s.SetImportDir("lib"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		c, err := s.Run()
+		if err != nil {
+			t.Fatalf("önce dizin=%t: %v", dirFirst, err)
+		}
+		if x := c.Get("x").Int(); x != 42 {
+			t.Fatalf("önce dizin=%t: %d, beklenen 42", dirFirst, x)
+		}
+	}
+}
+
+const callScript = `
+sayaç := 0
+topla := fn(a, b) { sayaç++; dön a + b }
+döngü := fn() { tekrarla {} }
+bekleyen := fn() { k := kanal(); dön k.al() }
+x := 1
+`
+
+func TestCompiledCall(t *testing.T) {
+	c, err := NewScript([]byte(callScript)).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Call("topla", 1, 2); err == nil ||
+		err.Error() != "tanımlanmamış değişken: topla" {
+		t.Fatalf("çalıştırılmamış betik: %v", err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.Call("topla", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Int() != 3 || res.Name() != "topla" {
+		t.Fatalf("topla(1, 2) = %v", res.Value())
+	}
+	if res, err = c.Call("topla", "a", "b"); err != nil || res.String() != "ab" {
+		t.Fatalf("topla(a, b) = %v, %v", res, err)
+	}
+	if n := c.Get("sayaç").Int(); n != 2 {
+		t.Fatalf("sayaç %d, beklenen 2", n)
+	}
+
+	tests := []struct {
+		name string
+		args []interface{}
+		err  string
+	}{
+		{"yok", nil, "tanımlanmamış değişken: yok"},
+		{"x", nil, "çağrılamaz: int"},
+		{"topla", []interface{}{1}, "yanlış argüman sayısı: want=2, got=1"},
+		{"topla", []interface{}{make(chan int), 1}, "cannot convert to object: chan int"},
+	}
+	for _, tt := range tests {
+		_, err := c.Call(tt.name, tt.args...)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatalf("%s%v: hata %v, beklenen %q", tt.name, tt.args, err, tt.err)
+		}
+	}
+}
+
+func TestCompiledCallLimits(t *testing.T) {
+	s := NewScript([]byte(callScript))
+	s.SetMaxInstructions(1000)
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Call("döngü"); !errors.Is(err, ErrInstructionLimit) {
+		t.Fatalf("hata %v, beklenen %v", err, ErrInstructionLimit)
+	}
+	if n := c.Stats().Instructions; n != 1000 {
+		t.Fatalf("%d komut, beklenen 1000", n)
+	}
+	if res, err := c.Call("topla", 1, 2); err != nil || res.Int() != 3 {
+		t.Fatalf("limitten sonra çağrı: %v, %v", res, err)
+	}
+}
+
+func TestCompiledCallContext(t *testing.T) {
+	c, err := NewScript([]byte(callScript)).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"döngü", "bekleyen"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		_, err := c.CallContext(ctx, name)
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Fatalf("%s: hata %v, beklenen %v", name, err, context.DeadlineExceeded)
+		}
+		if d := time.Since(start); d > 2*time.Second {
+			t.Fatalf("%s: %v sürdü", name, d)
+		}
+	}
+	if res, err := c.Call("topla", 1, 2); err != nil || res.Int() != 3 {
+		t.Fatalf("iptalden sonra çağrı: %v, %v", res, err)
+	}
+}
@@ -0,0 +1,340 @@
+package lokum
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const limitCheckInterval = 1024
+
+type RunStats struct {
+	Instructions int64
+	Allocs       int64
+	AllocBytes   int64
+}
+
+type runBudget struct {
+	instructions int64
+	allocs       int64
+	allocBytes   int64
+	shared       int32
+	root         *VM
+	lock         sync.Mutex
+	err          error
+}
+
+type budgetShare struct {
+	instructions int64
+	allocs       int64
+	allocBytes   int64
+	otherAllocs  int64
+}
+
+func (b *runBudget) fail(err error) {
+	b.lock.Lock()
+	if b.err == nil {
+		b.err = err
+	}
+	b.lock.Unlock()
+	b.root.Abort()
+}
+
+func (b *runBudget) failure() error {
+	if b == nil {
+		return nil
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.err
+}
+
+func isBudgetError(err error) bool {
+	return errors.Is(err, ErrInstructionLimit) ||
+		errors.Is(err, ErrObjectAllocLimit) ||
+		errors.Is(err, ErrAllocBytesLimit) ||
+		errors.Is(err, ErrTimeLimit)
+}
+
+func (v *VM) SetMaxInstructions(n int64) {
+	v.maxInstructions = n
+}
+
+func (v *VM) SetMaxDuration(d time.Duration) {
+	v.maxDuration = d
+}
+
+func (v *VM) Stats() RunStats {
+	return RunStats{
+		Instructions: v.usedInstructions() +
+			atomic.LoadInt64(&v.spawnedInstrs),
+		Allocs:     v.usedAllocs() + atomic.LoadInt64(&v.spawnedAllocs),
+		AllocBytes: v.allocBytes + atomic.LoadInt64(&v.spawnedAllocBytes),
+	}
+}
+
+func (v *VM) startDeadline() {
+	v.deadline = time.Time{}
+	if v.maxDuration > 0 {
+		v.deadline = time.Now().Add(v.maxDuration)
+	}
+}
+
+func (v *VM) armDeadline() func() {
+	if v.deadline.IsZero() {
+		return func() {}
+	}
+	budget := v.budget
+	fired := make(chan struct{})
+	timer := time.AfterFunc(time.Until(v.deadline), func() {
+		budget.fail(ErrTimeLimit)
+		close(fired)
+	})
+	return func() {
+		if !timer.Stop() {
+			<-fired
+		}
+	}
+}
+
+func (v *VM) usedInstructions() int64 {
+	return v.instructions + v.tickBase - v.ticks
+}
+
+func (v *VM) usedAllocs() int64 {
+	if !v.started {
+		return 0
+	}
+	return v.maxAllocs + 1 - v.allocs - v.share.otherAllocs
+}
+
+func (v *VM) sharesBudget() bool {
+	return v.budget != nil && atomic.LoadInt32(&v.budget.shared) != 0
+}
+
+func (v *VM) syncBudget() bool {
+	if !v.sharesBudget() {
+		return true
+	}
+	b, s := v.budget, &v.share
+	own := v.usedInstructions()
+	instructions := atomic.AddInt64(&b.instructions, own-s.instructions)
+	s.instructions = own
+	own = v.usedAllocs()
+	allocs := atomic.AddInt64(&b.allocs, own-s.allocs)
+	s.allocs = own
+	allocBytes := atomic.AddInt64(&b.allocBytes, v.allocBytes-s.allocBytes)
+	s.allocBytes = v.allocBytes
+
+	switch {
+	case v.maxInstructions >= 0 && instructions >= v.maxInstructions:
+		v.err = ErrInstructionLimit
+	case v.maxAllocs >= 0 && allocs > v.maxAllocs:
+		v.err = ErrObjectAllocLimit
+	case v.maxAllocBytes >= 0 && allocBytes > v.maxAllocBytes:
+		v.err = ErrAllocBytesLimit
+	default:
+		if v.maxAllocs >= 0 {
+			others := allocs - own
+			v.allocs -= others - s.otherAllocs
+			s.otherAllocs = others
+		}
+		return true
+	}
+	return false
+}
+
+func (v *VM) otherInstructions() int64 {
+	if !v.sharesBudget() {
+		return 0
+	}
+	return atomic.LoadInt64(&v.budget.instructions) - v.share.instructions
+}
+
+func (v *VM) otherAllocBytes() int64 {
+	if !v.sharesBudget() {
+		return 0
+	}
+	return atomic.LoadInt64(&v.budget.allocBytes) - v.share.allocBytes
+}
+
+func (v *VM) refill() {
+	n := int64(limitCheckInterval)
+	if v.maxInstructions >= 0 {
+		left := v.maxInstructions - v.instructions - v.otherInstructions()
+		if left < n {
+			n = left
+		}
+	}
+	if n < 0 {
+		n = 0
+	}
+	if (v.debugger != nil || v.coverage != nil) && n > 1 {
+		n = 1
+	}
+	v.ticks, v.tickBase = n, n
+}
+
+func (v *VM) checkLimits() bool {
+	v.instructions += v.tickBase
+	v.ticks, v.tickBase = 0, 0
+	if atomic.LoadInt64(&v.aborting) != 0 || !v.syncBudget() {
+		return false
+	}
+	if v.maxInstructions >= 0 && v.instructions >= v.maxInstructions {
+		v.err = ErrInstructionLimit
+		return false
+	}
+	if !v.deadline.IsZero() && time.Now().After(v.deadline) {
+		v.err = ErrTimeLimit
+		return false
+	}
+	if v.coverage != nil {
+		v.coverage.record(v)
+	}
+	if v.profiler != nil {
+		v.profiler.sample(v)
+	}
+	if v.debugger != nil {
+		v.debugger.step(v)
+	}
+	v.refill()
+	return true
+}
+
+func (v *VM) addSpawnStats(child *VM) {
+	stats := child.Stats()
+	atomic.AddInt64(&v.spawnedInstrs, stats.Instructions)
+	atomic.AddInt64(&v.spawnedAllocs, stats.Allocs)
+	atomic.AddInt64(&v.spawnedAllocBytes, stats.AllocBytes)
+}
+
+const (
+	elementSize  = 16
+	mapEntrySize = 48
+)
+
+// SetMaxAllocBytes, çalışma boyunca ayrılan yaklaşık bayt miktarını sınırlar.
+// Bu anlık yığın boyutu değil, ayırma hacmidir: bırakılan nesnelerin
+// belleği geri sayılmaz, yalnızca yeni ayrılan ve büyüyen değerler
+// sayılır. Ayrılan baytlar yalnızca bu limit, bir uzunluk limiti ya da
+// profil çıkarıcı etkinken sayılır; aksi halde RunStats.AllocBytes sıfırdır.
+func (v *VM) SetMaxAllocBytes(n int64) {
+	v.maxAllocBytes = n
+}
+
+func (v *VM) SetMaxStringLen(n int) {
+	v.maxStringLen = n
+}
+
+func (v *VM) SetMaxBytesLen(n int) {
+	v.maxBytesLen = n
+}
+
+func (v *VM) accountsAllocBytes() bool {
+	return v.maxAllocBytes >= 0 || v.maxStringLen > 0 || v.maxBytesLen > 0 ||
+		v.profiler != nil
+}
+
+func (v *VM) account(o Object, shared ...Object) bool {
+	if !v.checkLen(o) {
+		return false
+	}
+	size := sizeOf(o)
+	for _, s := range shared {
+		if sharesMemory(o, s) {
+			size -= sizeOf(s)
+			break
+		}
+	}
+	return size <= 0 || v.addAllocBytes(size)
+}
+
+func (v *VM) accountCall(o Object, args []Object, receiver Object) bool {
+	if !v.checkLen(o) {
+		return false
+	}
+	size := sizeOf(o)
+	if size == 0 {
+		return true
+	}
+	if receiver != nil && o == receiver {
+		size -= v.callSizes[len(args)]
+	} else {
+		for i, arg := range args {
+			if sharesMemory(o, arg) {
+				size -= v.callSizes[i]
+				break
+			}
+		}
+	}
+	return size <= 0 || v.addAllocBytes(size)
+}
+
+func (v *VM) checkLen(o Object) bool {
+	switch o := o.(type) {
+	case *String:
+		if v.maxStringLen > 0 && len(o.Value) > v.maxStringLen {
+			v.err = ErrStringLimit
+			return false
+		}
+	case *Bytes:
+		if v.maxBytesLen > 0 && len(o.Value) > v.maxBytesLen {
+			v.err = ErrBytesLimit
+			return false
+		}
+	}
+	return true
+}
+
+func (v *VM) addAllocBytes(n int64) bool {
+	v.allocBytes += n
+	if v.maxAllocBytes >= 0 && v.allocBytes+v.otherAllocBytes() > v.maxAllocBytes {
+		v.err = ErrAllocBytesLimit
+		return false
+	}
+	return true
+}
+
+func sizeOf(o Object) int64 {
+	switch o := o.(type) {
+	case *String:
+		return int64(len(o.Value))
+	case *Bytes:
+		return int64(len(o.Value))
+	case *Array:
+		return int64(len(o.Value)) * elementSize
+	case *ImmutableArray:
+		return int64(len(o.Value)) * elementSize
+	case *Map:
+		if o.Value != nil {
+			return int64(o.Value.Len()) * mapEntrySize
+		}
+	case *ImmutableMap:
+		return int64(o.Value.Len()) * mapEntrySize
+	case *Set:
+		return int64(o.Value.Len()) * mapEntrySize
+	case *ImmutableSet:
+		return int64(o.Value.Len()) * mapEntrySize
+	}
+	return 0
+}
+
+func sharesMemory(a, b Object) bool {
+	if a == b {
+		return true
+	}
+	x, ok := a.(*Array)
+	if !ok {
+		return false
+	}
+	var y []Object
+	switch b := b.(type) {
+	case *Array:
+		y = b.Value
+	case *ImmutableArray:
+		y = b.Value
+	}
+	return len(x.Value) > 0 && len(y) > 0 && &x.Value[0] == &y[0]
+}
@@ -0,0 +1,116 @@
+package lokum
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const spawnLoop = `
+görevler := []
+tekrarla i := 0; i < 50; i++ {
+	görevler = görevler + [başlat fn() {
+		x := []
+		tekrarla j := 0; j < 100; j++ { x = x + [j] }
+	}()]
+}
+tekrarla g in görevler { bekle(g) }
+`
+
+func TestSpawnSharesLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit func(s *Script)
+		err   error
+	}{
+		{"komut", func(s *Script) { s.SetMaxInstructions(5000) }, ErrInstructionLimit},
+		{"nesne", func(s *Script) { s.SetMaxAllocs(5000) }, ErrObjectAllocLimit},
+		{"bellek", func(s *Script) { s.SetMaxAllocBytes(100000) }, ErrAllocBytesLimit},
+	}
+	for _, tt := range tests {
+		s := NewScript([]byte(spawnLoop))
+		tt.limit(s)
+		c, err := s.Compile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.Run()
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("%s: hata %v, beklenen %v", tt.name, err, tt.err)
+		}
+		stats := c.Stats()
+		if tt.err == ErrInstructionLimit && stats.Instructions > 5000+50*limitCheckInterval {
+			t.Fatalf("%s: %d komut çalıştı", tt.name, stats.Instructions)
+		}
+	}
+}
+
+func TestSpawnWithinLimits(t *testing.T) {
+	s := NewScript([]byte(spawnLoop))
+	s.SetMaxInstructions(10000000)
+	s.SetMaxAllocs(1000000)
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryCountsGrowth(t *testing.T) {
+	s := NewScript([]byte(`
+k := küme()
+tekrarla i := 0; i < 5000; i++ { k.ekle(i) }
+`))
+	s.SetMaxAllocBytes(1000000)
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if m := c.Stats().AllocBytes; m != 5000*mapEntrySize {
+		t.Fatalf("bellek %d, beklenen %d", m, 5000*mapEntrySize)
+	}
+}
+
+func TestDurationInterruptsBlocked(t *testing.T) {
+	tests := []string{
+		`k := kanal(); k.al()`,
+		`k := kanal(); bekle(başlat fn() { k.al() }())`,
+		`seç(kanal(), kanal())`,
+	}
+	for _, src := range tests {
+		s := NewScript([]byte(src))
+		s.SetMaxDuration(200 * time.Millisecond)
+		c, err := s.Compile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		err = c.Run()
+		if !errors.Is(err, ErrTimeLimit) {
+			t.Fatalf("%s: hata %v, beklenen %v", src, err, ErrTimeLimit)
+		}
+		if d := time.Since(start); d > 2*time.Second {
+			t.Fatalf("%s: %v sürdü", src, d)
+		}
+	}
+}
+
+func TestDurationInterruptsBlockedCall(t *testing.T) {
+	s := NewScript([]byte(`bekleyen := fn() { k := kanal(); dön k.al() }`))
+	s.SetMaxDuration(200 * time.Millisecond)
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Call("bekleyen"); !errors.Is(err, ErrTimeLimit) {
+		t.Fatalf("hata %v, beklenen %v", err, ErrTimeLimit)
+	}
+}
@@ -1,9 +1,10 @@
-package stdlib
-
-import (
-	"github.com/onrirr/lokum"
-)
-
-var BuiltinModules = map[string]map[string]lokum.Object{
-	"io": fmtModule,
-}
+package stdlib
+
+import (
+	"github.com/onrirr/lokum"
+)
+
+var BuiltinModules = map[string]map[string]lokum.Object{
+	"io":   fmtModule,
+	"test": testModule,
+}
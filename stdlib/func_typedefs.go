@@ -1,961 +1,1235 @@
-//Autogenerated file by lokumc from stdlib\func_typedefs.go. Do not edit.
-
-package stdlib
-
-import (
-	"fmt"
-
-	"github.com/onrirr/lokum"
-)
-
-func FuncAR(fn func()) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		fn()
-		return lokum.UndefinedValue, nil
-	}
-}
-
-func FuncARI(fn func() int) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		return &lokum.Int{Value: int64(fn())}, nil
-	}
-}
-
-func FuncARI64(fn func() int64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		return &lokum.Int{Value: fn()}, nil
-	}
-}
-
-func FuncAI64RI64(fn func(int64) int64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-
-		i1, ok := lokum.ToInt64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		return &lokum.Int{Value: fn(i1)}, nil
-	}
-}
-
-func FuncAI64R(fn func(int64)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-
-		i1, ok := lokum.ToInt64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		fn(i1)
-		return lokum.UndefinedValue, nil
-	}
-}
-
-func FuncARB(fn func() bool) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		if fn() {
-			return lokum.TrueValue, nil
-		}
-		return lokum.FalseValue, nil
-	}
-}
-
-func FuncARE(fn func() error) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		return wrapError(fn()), nil
-	}
-}
-
-func FuncARS(fn func() string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s := fn()
-		if len(s) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: s}, nil
-	}
-}
-
-func FuncARSE(fn func() (string, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		res, err := fn()
-		if err != nil {
-			return wrapError(err), nil
-		}
-		if len(res) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: res}, nil
-	}
-}
-
-func FuncARYE(fn func() ([]byte, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		res, err := fn()
-		if err != nil {
-			return wrapError(err), nil
-		}
-		if len(res) > lokum.MaxBytesLen {
-			return nil, lokum.ErrBytesLimit
-		}
-		return &lokum.Bytes{Value: res}, nil
-	}
-}
-
-func FuncARF(fn func() float64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		return &lokum.Float{Value: fn()}, nil
-	}
-}
-
-func FuncARSs(fn func() []string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		arr := &lokum.Array{}
-		for _, elem := range fn() {
-			if len(elem) > lokum.MaxStringLen {
-				return nil, lokum.ErrStringLimit
-			}
-			arr.Value = append(arr.Value, &lokum.String{Value: elem})
-		}
-		return arr, nil
-	}
-}
-
-func FuncARIsE(fn func() ([]int, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 0 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		res, err := fn()
-		if err != nil {
-			return wrapError(err), nil
-		}
-		arr := &lokum.Array{}
-		for _, v := range res {
-			arr.Value = append(arr.Value, &lokum.Int{Value: int64(v)})
-		}
-		return arr, nil
-	}
-}
-
-func FuncAIRIs(fn func(int) []int) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		i1, ok := lokum.ToInt(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res := fn(i1)
-		arr := &lokum.Array{}
-		for _, v := range res {
-			arr.Value = append(arr.Value, &lokum.Int{Value: int64(v)})
-		}
-		return arr, nil
-	}
-}
-
-func FuncAFRF(fn func(float64) float64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		f1, ok := lokum.ToFloat64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "float(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		return &lokum.Float{Value: fn(f1)}, nil
-	}
-}
-
-func FuncAIR(fn func(int)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		i1, ok := lokum.ToInt(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		fn(i1)
-		return lokum.UndefinedValue, nil
-	}
-}
-
-func FuncAIRF(fn func(int) float64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		i1, ok := lokum.ToInt(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		return &lokum.Float{Value: fn(i1)}, nil
-	}
-}
-
-func FuncAFRI(fn func(float64) int) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		f1, ok := lokum.ToFloat64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "float(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		return &lokum.Int{Value: int64(fn(f1))}, nil
-	}
-}
-
-func FuncAFFRF(fn func(float64, float64) float64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		f1, ok := lokum.ToFloat64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "float(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		f2, ok := lokum.ToFloat64(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "float(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		return &lokum.Float{Value: fn(f1, f2)}, nil
-	}
-}
-
-func FuncAIFRF(fn func(int, float64) float64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		i1, ok := lokum.ToInt(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		f2, ok := lokum.ToFloat64(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "float(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		return &lokum.Float{Value: fn(i1, f2)}, nil
-	}
-}
-
-func FuncAFIRF(fn func(float64, int) float64) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		f1, ok := lokum.ToFloat64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "float(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		i2, ok := lokum.ToInt(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "sayı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		return &lokum.Float{Value: fn(f1, i2)}, nil
-	}
-}
-
-func FuncAFIRB(fn func(float64, int) bool) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		f1, ok := lokum.ToFloat64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "float(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		i2, ok := lokum.ToInt(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "sayı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		if fn(f1, i2) {
-			return lokum.TrueValue, nil
-		}
-		return lokum.FalseValue, nil
-	}
-}
-
-func FuncAFRB(fn func(float64) bool) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		f1, ok := lokum.ToFloat64(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "float(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		if fn(f1) {
-			return lokum.TrueValue, nil
-		}
-		return lokum.FalseValue, nil
-	}
-}
-
-func FuncASRS(fn func(string) string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s := fn(s1)
-		if len(s) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: s}, nil
-	}
-}
-
-func FuncASRSs(fn func(string) []string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res := fn(s1)
-		arr := &lokum.Array{}
-		for _, elem := range res {
-			if len(elem) > lokum.MaxStringLen {
-				return nil, lokum.ErrStringLimit
-			}
-			arr.Value = append(arr.Value, &lokum.String{Value: elem})
-		}
-		return arr, nil
-	}
-}
-
-func FuncASRSE(fn func(string) (string, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res, err := fn(s1)
-		if err != nil {
-			return wrapError(err), nil
-		}
-		if len(res) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: res}, nil
-	}
-}
-
-func FuncASRE(fn func(string) error) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		return wrapError(fn(s1)), nil
-	}
-}
-
-func FuncASSRE(fn func(string, string) error) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s2, ok := lokum.ToString(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "yazı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		return wrapError(fn(s1, s2)), nil
-	}
-}
-
-func FuncASSRSs(fn func(string, string) []string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s2, ok := lokum.ToString(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		arr := &lokum.Array{}
-		for _, res := range fn(s1, s2) {
-			if len(res) > lokum.MaxStringLen {
-				return nil, lokum.ErrStringLimit
-			}
-			arr.Value = append(arr.Value, &lokum.String{Value: res})
-		}
-		return arr, nil
-	}
-}
-
-func FuncASSIRSs(fn func(string, string, int) []string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 3 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s2, ok := lokum.ToString(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "yazı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		i3, ok := lokum.ToInt(args[2])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "third",
-				Expected: "sayı(geçerli)",
-				Found:    args[2].TypeName(),
-			}
-		}
-		arr := &lokum.Array{}
-		for _, res := range fn(s1, s2, i3) {
-			if len(res) > lokum.MaxStringLen {
-				return nil, lokum.ErrStringLimit
-			}
-			arr.Value = append(arr.Value, &lokum.String{Value: res})
-		}
-		return arr, nil
-	}
-}
-
-func FuncASSRI(fn func(string, string) int) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s2, ok := lokum.ToString(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		return &lokum.Int{Value: int64(fn(s1, s2))}, nil
-	}
-}
-
-func FuncASSRS(fn func(string, string) string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s2, ok := lokum.ToString(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "yazı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		s := fn(s1, s2)
-		if len(s) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: s}, nil
-	}
-}
-
-func FuncASSRB(fn func(string, string) bool) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s2, ok := lokum.ToString(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "yazı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		if fn(s1, s2) {
-			return lokum.TrueValue, nil
-		}
-		return lokum.FalseValue, nil
-	}
-}
-
-func FuncASsSRS(fn func([]string, string) string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (lokum.Object, error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		var ss1 []string
-		switch arg0 := args[0].(type) {
-		case *lokum.Array:
-			for idx, a := range arg0.Value {
-				as, ok := lokum.ToString(a)
-				if !ok {
-					return nil, lokum.ErrInvalidArgumentType{
-						Name:     fmt.Sprintf("first[%d]", idx),
-						Expected: "yazı(geçerli)",
-						Found:    a.TypeName(),
-					}
-				}
-				ss1 = append(ss1, as)
-			}
-		case *lokum.ImmutableArray:
-			for idx, a := range arg0.Value {
-				as, ok := lokum.ToString(a)
-				if !ok {
-					return nil, lokum.ErrInvalidArgumentType{
-						Name:     fmt.Sprintf("first[%d]", idx),
-						Expected: "yazı(geçerli)",
-						Found:    a.TypeName(),
-					}
-				}
-				ss1 = append(ss1, as)
-			}
-		default:
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "liste",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s2, ok := lokum.ToString(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "yazı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		s := fn(ss1, s2)
-		if len(s) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: s}, nil
-	}
-}
-
-func FuncASI64RE(fn func(string, int64) error) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		i2, ok := lokum.ToInt64(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "sayı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		return wrapError(fn(s1, i2)), nil
-	}
-}
-
-func FuncAIIRE(fn func(int, int) error) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		i1, ok := lokum.ToInt(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		i2, ok := lokum.ToInt(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "sayı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		return wrapError(fn(i1, i2)), nil
-	}
-}
-
-func FuncASIRS(fn func(string, int) string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 2 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		i2, ok := lokum.ToInt(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "sayı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		s := fn(s1, i2)
-		if len(s) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: s}, nil
-	}
-}
-
-func FuncASIIRE(fn func(string, int, int) error) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 3 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		i2, ok := lokum.ToInt(args[1])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "second",
-				Expected: "sayı(geçerli)",
-				Found:    args[1].TypeName(),
-			}
-		}
-		i3, ok := lokum.ToInt(args[2])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "third",
-				Expected: "sayı(geçerli)",
-				Found:    args[2].TypeName(),
-			}
-		}
-		return wrapError(fn(s1, i2, i3)), nil
-	}
-}
-
-func FuncAYRIE(fn func([]byte) (int, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		y1, ok := lokum.ToByteSlice(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "bytes(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res, err := fn(y1)
-		if err != nil {
-			return wrapError(err), nil
-		}
-		return &lokum.Int{Value: int64(res)}, nil
-	}
-}
-
-func FuncAYRS(fn func([]byte) string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		y1, ok := lokum.ToByteSlice(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "bytes(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res := fn(y1)
-		return &lokum.String{Value: res}, nil
-	}
-}
-
-func FuncASRIE(fn func(string) (int, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res, err := fn(s1)
-		if err != nil {
-			return wrapError(err), nil
-		}
-		return &lokum.Int{Value: int64(res)}, nil
-	}
-}
-
-func FuncASRYE(fn func(string) ([]byte, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		s1, ok := lokum.ToString(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "yazı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res, err := fn(s1)
-		if err != nil {
-			return wrapError(err), nil
-		}
-		if len(res) > lokum.MaxBytesLen {
-			return nil, lokum.ErrBytesLimit
-		}
-		return &lokum.Bytes{Value: res}, nil
-	}
-}
-
-func FuncAIRSsE(fn func(int) ([]string, error)) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		i1, ok := lokum.ToInt(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		res, err := fn(i1)
-		if err != nil {
-			return wrapError(err), nil
-		}
-		arr := &lokum.Array{}
-		for _, r := range res {
-			if len(r) > lokum.MaxStringLen {
-				return nil, lokum.ErrStringLimit
-			}
-			arr.Value = append(arr.Value, &lokum.String{Value: r})
-		}
-		return arr, nil
-	}
-}
-
-func FuncAIRS(fn func(int) string) lokum.CallableFunc {
-	return func(args ...lokum.Object) (ret lokum.Object, err error) {
-		if len(args) != 1 {
-			return nil, lokum.ErrWrongNumArguments
-		}
-		i1, ok := lokum.ToInt(args[0])
-		if !ok {
-			return nil, lokum.ErrInvalidArgumentType{
-				Name:     "first",
-				Expected: "sayı(geçerli)",
-				Found:    args[0].TypeName(),
-			}
-		}
-		s := fn(i1)
-		if len(s) > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		return &lokum.String{Value: s}, nil
-	}
-}
+// genfuncs.go ile oluşturuldu, değiştirmeyin.
+
+package stdlib
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onrirr/lokum"
+)
+
+func FuncAR(fn func()) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		fn()
+		return lokum.UndefinedValue, nil
+	}
+}
+
+func FuncARI(fn func() int) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res := fn()
+		return &lokum.Int{Value: int64(res)}, nil
+	}
+}
+
+func FuncARI64(fn func() int64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res := fn()
+		return &lokum.Int{Value: res}, nil
+	}
+}
+
+func FuncAI64RI64(fn func(int64) int64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(i1)
+		return &lokum.Int{Value: res}, nil
+	}
+}
+
+func FuncAI64R(fn func(int64)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		fn(i1)
+		return lokum.UndefinedValue, nil
+	}
+}
+
+func FuncARB(fn func() bool) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res := fn()
+		if res {
+			return lokum.TrueValue, nil
+		}
+		return lokum.FalseValue, nil
+	}
+}
+
+func FuncARE(fn func() error) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		return wrapError(fn()), nil
+	}
+}
+
+func FuncARS(fn func() string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res := fn()
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncARSE(fn func() (string, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res, err := fn()
+		if err != nil {
+			return wrapError(err), nil
+		}
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncARYE(fn func() ([]byte, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res, err := fn()
+		if err != nil {
+			return wrapError(err), nil
+		}
+		if len(res) > lokum.MaxBytesLen {
+			return nil, lokum.ErrBytesLimit
+		}
+		return &lokum.Bytes{Value: res}, nil
+	}
+}
+
+func FuncARF(fn func() float64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res := fn()
+		return &lokum.Float{Value: res}, nil
+	}
+}
+
+func FuncARSs(fn func() []string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res := fn()
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			if len(elem) > lokum.MaxStringLen {
+				return nil, lokum.ErrStringLimit
+			}
+			arr.Value = append(arr.Value, &lokum.String{Value: elem})
+		}
+		return arr, nil
+	}
+}
+
+func FuncARIsE(fn func() ([]int, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res, err := fn()
+		if err != nil {
+			return wrapError(err), nil
+		}
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			arr.Value = append(arr.Value, &lokum.Int{Value: int64(elem)})
+		}
+		return arr, nil
+	}
+}
+
+func FuncAIRIs(fn func(int) []int) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(i1)
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			arr.Value = append(arr.Value, &lokum.Int{Value: int64(elem)})
+		}
+		return arr, nil
+	}
+}
+
+func FuncAFRF(fn func(float64) float64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		f1, ok := lokum.ToFloat64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "float(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(f1)
+		return &lokum.Float{Value: res}, nil
+	}
+}
+
+func FuncAIR(fn func(int)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		fn(i1)
+		return lokum.UndefinedValue, nil
+	}
+}
+
+func FuncAIRF(fn func(int) float64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(i1)
+		return &lokum.Float{Value: res}, nil
+	}
+}
+
+func FuncAFRI(fn func(float64) int) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		f1, ok := lokum.ToFloat64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "float(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(f1)
+		return &lokum.Int{Value: int64(res)}, nil
+	}
+}
+
+func FuncAFFRF(fn func(float64, float64) float64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		f1, ok := lokum.ToFloat64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "float(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		f2, ok := lokum.ToFloat64(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "float(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(f1, f2)
+		return &lokum.Float{Value: res}, nil
+	}
+}
+
+func FuncAIFRF(fn func(int, float64) float64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		f2, ok := lokum.ToFloat64(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "float(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(i1, f2)
+		return &lokum.Float{Value: res}, nil
+	}
+}
+
+func FuncAFIRF(fn func(float64, int) float64) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		f1, ok := lokum.ToFloat64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "float(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		i2, ok := lokum.ToInt(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "sayı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(f1, i2)
+		return &lokum.Float{Value: res}, nil
+	}
+}
+
+func FuncAFIRB(fn func(float64, int) bool) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		f1, ok := lokum.ToFloat64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "float(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		i2, ok := lokum.ToInt(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "sayı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(f1, i2)
+		if res {
+			return lokum.TrueValue, nil
+		}
+		return lokum.FalseValue, nil
+	}
+}
+
+func FuncAFRB(fn func(float64) bool) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		f1, ok := lokum.ToFloat64(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "float(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(f1)
+		if res {
+			return lokum.TrueValue, nil
+		}
+		return lokum.FalseValue, nil
+	}
+}
+
+func FuncASRS(fn func(string) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(s1)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASRSs(fn func(string) []string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(s1)
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			if len(elem) > lokum.MaxStringLen {
+				return nil, lokum.ErrStringLimit
+			}
+			arr.Value = append(arr.Value, &lokum.String{Value: elem})
+		}
+		return arr, nil
+	}
+}
+
+func FuncASRSE(fn func(string) (string, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res, err := fn(s1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASRE(fn func(string) error) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		return wrapError(fn(s1)), nil
+	}
+}
+
+func FuncASSRE(fn func(string, string) error) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		s2, ok := lokum.ToString(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "yazı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		return wrapError(fn(s1, s2)), nil
+	}
+}
+
+func FuncASSRSs(fn func(string, string) []string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		s2, ok := lokum.ToString(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "yazı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(s1, s2)
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			if len(elem) > lokum.MaxStringLen {
+				return nil, lokum.ErrStringLimit
+			}
+			arr.Value = append(arr.Value, &lokum.String{Value: elem})
+		}
+		return arr, nil
+	}
+}
+
+func FuncASSIRSs(fn func(string, string, int) []string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 3 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		s2, ok := lokum.ToString(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "yazı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		i3, ok := lokum.ToInt(args[2])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "third",
+				Expected: "sayı(geçerli)",
+				Found:    args[2].TypeName(),
+			}
+		}
+		res := fn(s1, s2, i3)
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			if len(elem) > lokum.MaxStringLen {
+				return nil, lokum.ErrStringLimit
+			}
+			arr.Value = append(arr.Value, &lokum.String{Value: elem})
+		}
+		return arr, nil
+	}
+}
+
+func FuncASSRI(fn func(string, string) int) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		s2, ok := lokum.ToString(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "yazı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(s1, s2)
+		return &lokum.Int{Value: int64(res)}, nil
+	}
+}
+
+func FuncASSRS(fn func(string, string) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		s2, ok := lokum.ToString(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "yazı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(s1, s2)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASSRB(fn func(string, string) bool) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		s2, ok := lokum.ToString(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "yazı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(s1, s2)
+		if res {
+			return lokum.TrueValue, nil
+		}
+		return lokum.FalseValue, nil
+	}
+}
+
+func FuncASsSRS(fn func([]string, string) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		ss1, err := stringsArg("first", args[0])
+		if err != nil {
+			return nil, err
+		}
+		s2, ok := lokum.ToString(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "yazı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(ss1, s2)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASI64RE(fn func(string, int64) error) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		i2, ok := lokum.ToInt64(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "sayı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		return wrapError(fn(s1, i2)), nil
+	}
+}
+
+func FuncAIIRE(fn func(int, int) error) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		i2, ok := lokum.ToInt(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "sayı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		return wrapError(fn(i1, i2)), nil
+	}
+}
+
+func FuncASIRS(fn func(string, int) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 2 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		i2, ok := lokum.ToInt(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "sayı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		res := fn(s1, i2)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASIIRE(fn func(string, int, int) error) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 3 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		i2, ok := lokum.ToInt(args[1])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "sayı(geçerli)",
+				Found:    args[1].TypeName(),
+			}
+		}
+		i3, ok := lokum.ToInt(args[2])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "third",
+				Expected: "sayı(geçerli)",
+				Found:    args[2].TypeName(),
+			}
+		}
+		return wrapError(fn(s1, i2, i3)), nil
+	}
+}
+
+func FuncAYRIE(fn func([]byte) (int, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		y1, ok := lokum.ToByteSlice(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "bytes(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res, err := fn(y1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		return &lokum.Int{Value: int64(res)}, nil
+	}
+}
+
+func FuncAYRS(fn func([]byte) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		y1, ok := lokum.ToByteSlice(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "bytes(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(y1)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASRIE(fn func(string) (int, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res, err := fn(s1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		return &lokum.Int{Value: int64(res)}, nil
+	}
+}
+
+func FuncASRYE(fn func(string) ([]byte, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res, err := fn(s1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		if len(res) > lokum.MaxBytesLen {
+			return nil, lokum.ErrBytesLimit
+		}
+		return &lokum.Bytes{Value: res}, nil
+	}
+}
+
+func FuncAIRSsE(fn func(int) ([]string, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res, err := fn(i1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			if len(elem) > lokum.MaxStringLen {
+				return nil, lokum.ErrStringLimit
+			}
+			arr.Value = append(arr.Value, &lokum.String{Value: elem})
+		}
+		return arr, nil
+	}
+}
+
+func FuncAIRS(fn func(int) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		i1, ok := lokum.ToInt(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "sayı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(i1)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncAVSRS(fn func(...string) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		s1 := make([]string, len(args))
+		for i, a := range args {
+			var ok bool
+			s1[i], ok = lokum.ToString(a)
+			if !ok {
+				return nil, lokum.ErrInvalidArgumentType{
+					Name:     argName(i),
+					Expected: "yazı(geçerli)",
+					Found:    a.TypeName(),
+				}
+			}
+		}
+		res := fn(s1...)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncART(fn func() time.Time) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 0 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		res := fn()
+		return &lokum.Time{Value: res}, nil
+	}
+}
+
+func FuncATRS(fn func(time.Time) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		t1, ok := lokum.ToTime(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "zaman(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res := fn(t1)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASRTE(fn func(string) (time.Time, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res, err := fn(s1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		return &lokum.Time{Value: res}, nil
+	}
+}
+
+func FuncASRME(fn func(string) (map[string]interface{}, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		res, err := fn(s1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		return lokum.FromInterface(res)
+	}
+}
+
+func FuncAMRSE(fn func(map[string]interface{}) (string, error)) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		m1, err := mapArg("first", args[0])
+		if err != nil {
+			return nil, err
+		}
+		res, err := fn(m1)
+		if err != nil {
+			return wrapError(err), nil
+		}
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+func FuncASsRSs(fn func([]string) []string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) != 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		ss1, err := stringsArg("first", args[0])
+		if err != nil {
+			return nil, err
+		}
+		res := fn(ss1)
+		arr := &lokum.Array{}
+		for _, elem := range res {
+			if len(elem) > lokum.MaxStringLen {
+				return nil, lokum.ErrStringLimit
+			}
+			arr.Value = append(arr.Value, &lokum.String{Value: elem})
+		}
+		return arr, nil
+	}
+}
+
+func FuncAVIRI(fn func(...int) int) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		i1 := make([]int, len(args))
+		for i, a := range args {
+			var ok bool
+			i1[i], ok = lokum.ToInt(a)
+			if !ok {
+				return nil, lokum.ErrInvalidArgumentType{
+					Name:     argName(i),
+					Expected: "sayı(geçerli)",
+					Found:    a.TypeName(),
+				}
+			}
+		}
+		res := fn(i1...)
+		return &lokum.Int{Value: int64(res)}, nil
+	}
+}
+
+func FuncASVSRS(fn func(string, ...string) string) lokum.CallableFunc {
+	return func(args ...lokum.Object) (ret lokum.Object, err error) {
+		if len(args) < 1 {
+			return nil, lokum.ErrWrongNumArguments
+		}
+		s1, ok := lokum.ToString(args[0])
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "first",
+				Expected: "yazı(geçerli)",
+				Found:    args[0].TypeName(),
+			}
+		}
+		s2 := make([]string, len(args[1:]))
+		for i, a := range args[1:] {
+			var ok bool
+			s2[i], ok = lokum.ToString(a)
+			if !ok {
+				return nil, lokum.ErrInvalidArgumentType{
+					Name:     argName(1 + i),
+					Expected: "yazı(geçerli)",
+					Found:    a.TypeName(),
+				}
+			}
+		}
+		res := fn(s1, s2...)
+		if len(res) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: res}, nil
+	}
+}
+
+var argNames = []string{"first", "second", "third", "fourth", "fifth"}
+
+func argName(i int) string {
+	if i < len(argNames) {
+		return argNames[i]
+	}
+	return fmt.Sprint(i + 1)
+}
+
+func arrayArg(name string, o lokum.Object) ([]lokum.Object, error) {
+	switch o := o.(type) {
+	case *lokum.Array:
+		return o.Value, nil
+	case *lokum.ImmutableArray:
+		return o.Value, nil
+	}
+	return nil, lokum.ErrInvalidArgumentType{
+		Name:     name,
+		Expected: "liste",
+		Found:    o.TypeName(),
+	}
+}
+
+func stringsArg(name string, o lokum.Object) ([]string, error) {
+	arr, err := arrayArg(name, o)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for idx, a := range arr {
+		as, ok := lokum.ToString(a)
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     fmt.Sprintf("%s[%d]", name, idx),
+				Expected: "yazı(geçerli)",
+				Found:    a.TypeName(),
+			}
+		}
+		res = append(res, as)
+	}
+	return res, nil
+}
+
+func intsArg(name string, o lokum.Object) ([]int, error) {
+	arr, err := arrayArg(name, o)
+	if err != nil {
+		return nil, err
+	}
+	var res []int
+	for idx, a := range arr {
+		ai, ok := lokum.ToInt(a)
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     fmt.Sprintf("%s[%d]", name, idx),
+				Expected: "sayı(geçerli)",
+				Found:    a.TypeName(),
+			}
+		}
+		res = append(res, ai)
+	}
+	return res, nil
+}
+
+func mapArg(name string, o lokum.Object) (map[string]interface{}, error) {
+	var m *lokum.OrderedMap
+	switch o := o.(type) {
+	case *lokum.Map:
+		m = o.Value
+	case *lokum.ImmutableMap:
+		m = o.Value
+	default:
+		return nil, lokum.ErrInvalidArgumentType{
+			Name:     name,
+			Expected: "harita",
+			Found:    o.TypeName(),
+		}
+	}
+	res := make(map[string]interface{}, m.Len())
+	var err error
+	m.Range(func(key, value lokum.Object) bool {
+		k, ok := key.(*lokum.String)
+		if !ok {
+			err = lokum.ErrInvalidArgumentType{
+				Name:     fmt.Sprintf("%s[%s]", name, key),
+				Expected: "yazı",
+				Found:    key.TypeName(),
+			}
+			return false
+		}
+		res[k.Value] = lokum.ToInterface(value)
+		return true
+	})
+	return res, err
+}
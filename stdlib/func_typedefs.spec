@@ -0,0 +1,55 @@
+// genfuncs.go bu dosyadaki her fonksiyon tipi için bir adaptör üretir.
+
+func()
+func() int
+func() int64
+func(int64) int64
+func(int64)
+func() bool
+func() error
+func() string
+func() (string, error)
+func() ([]byte, error)
+func() float64
+func() []string
+func() ([]int, error)
+func(int) []int
+func(float64) float64
+func(int)
+func(int) float64
+func(float64) int
+func(float64, float64) float64
+func(int, float64) float64
+func(float64, int) float64
+func(float64, int) bool
+func(float64) bool
+func(string) string
+func(string) []string
+func(string) (string, error)
+func(string) error
+func(string, string) error
+func(string, string) []string
+func(string, string, int) []string
+func(string, string) int
+func(string, string) string
+func(string, string) bool
+func([]string, string) string
+func(string, int64) error
+func(int, int) error
+func(string, int) string
+func(string, int, int) error
+func([]byte) (int, error)
+func([]byte) string
+func(string) (int, error)
+func(string) ([]byte, error)
+func(int) ([]string, error)
+func(int) string
+func(...string) string
+func() time.Time
+func(time.Time) string
+func(string) (time.Time, error)
+func(string) (map[string]interface{}, error)
+func(map[string]interface{}) (string, error)
+func([]string) []string
+func(...int) int
+func(string, ...string) string
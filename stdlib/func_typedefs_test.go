@@ -0,0 +1,697 @@
+// genfuncs.go ile oluşturuldu, değiştirmeyin.
+
+package stdlib
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/onrirr/lokum"
+)
+
+func TestFuncAR(t *testing.T) {
+	fn := FuncAR(func() {
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, lokum.UndefinedValue)
+}
+
+func TestFuncARI(t *testing.T) {
+	fn := FuncARI(func() int {
+		return 5
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+}
+
+func TestFuncARI64(t *testing.T) {
+	fn := FuncARI64(func() int64 {
+		return int64(5)
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+}
+
+func TestFuncAI64RI64(t *testing.T) {
+	fn := FuncAI64RI64(func(p1 int64) int64 {
+		checkArgs(t, []interface{}{p1}, int64(3))
+		return int64(5)
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+}
+
+func TestFuncAI64R(t *testing.T) {
+	fn := FuncAI64R(func(p1 int64) {
+		checkArgs(t, []interface{}{p1}, int64(3))
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, lokum.UndefinedValue)
+}
+
+func TestFuncARB(t *testing.T) {
+	fn := FuncARB(func() bool {
+		return true
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, lokum.TrueValue)
+}
+
+func TestFuncARE(t *testing.T) {
+	fail := false
+	fn := FuncARE(func() error {
+		return testError(fail)
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, lokum.TrueValue)
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncARS(t *testing.T) {
+	fn := FuncARS(func() string {
+		return "b"
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncARSE(t *testing.T) {
+	fail := false
+	fn := FuncARSE(func() (string, error) {
+		return "b", testError(fail)
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncARYE(t *testing.T) {
+	fail := false
+	fn := FuncARYE(func() ([]byte, error) {
+		return []byte("b"), testError(fail)
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, &lokum.Bytes{Value: []byte("b")})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncARF(t *testing.T) {
+	fn := FuncARF(func() float64 {
+		return 2.5
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, &lokum.Float{Value: 2.5})
+}
+
+func TestFuncARSs(t *testing.T) {
+	fn := FuncARSs(func() []string {
+		return []string{"b"}
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, testArray(&lokum.String{Value: "b"}))
+}
+
+func TestFuncARIsE(t *testing.T) {
+	fail := false
+	fn := FuncARIsE(func() ([]int, error) {
+		return []int{2}, testError(fail)
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, testArray(&lokum.Int{Value: 2}))
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncAIRIs(t *testing.T) {
+	fn := FuncAIRIs(func(p1 int) []int {
+		checkArgs(t, []interface{}{p1}, 3)
+		return []int{2}
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, testArray(&lokum.Int{Value: 2}))
+}
+
+func TestFuncAFRF(t *testing.T) {
+	fn := FuncAFRF(func(p1 float64) float64 {
+		checkArgs(t, []interface{}{p1}, 1.5)
+		return 2.5
+	})
+	args := []lokum.Object{&lokum.Float{Value: 1.5}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Float{Value: 2.5})
+}
+
+func TestFuncAIR(t *testing.T) {
+	fn := FuncAIR(func(p1 int) {
+		checkArgs(t, []interface{}{p1}, 3)
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, lokum.UndefinedValue)
+}
+
+func TestFuncAIRF(t *testing.T) {
+	fn := FuncAIRF(func(p1 int) float64 {
+		checkArgs(t, []interface{}{p1}, 3)
+		return 2.5
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Float{Value: 2.5})
+}
+
+func TestFuncAFRI(t *testing.T) {
+	fn := FuncAFRI(func(p1 float64) int {
+		checkArgs(t, []interface{}{p1}, 1.5)
+		return 5
+	})
+	args := []lokum.Object{&lokum.Float{Value: 1.5}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+}
+
+func TestFuncAFFRF(t *testing.T) {
+	fn := FuncAFFRF(func(p1 float64, p2 float64) float64 {
+		checkArgs(t, []interface{}{p1, p2}, 1.5, 1.5)
+		return 2.5
+	})
+	args := []lokum.Object{&lokum.Float{Value: 1.5}, &lokum.Float{Value: 1.5}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, &lokum.Array{}, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Float{Value: 2.5})
+}
+
+func TestFuncAIFRF(t *testing.T) {
+	fn := FuncAIFRF(func(p1 int, p2 float64) float64 {
+		checkArgs(t, []interface{}{p1, p2}, 3, 1.5)
+		return 2.5
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}, &lokum.Float{Value: 1.5}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, &lokum.Array{}, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Float{Value: 2.5})
+}
+
+func TestFuncAFIRF(t *testing.T) {
+	fn := FuncAFIRF(func(p1 float64, p2 int) float64 {
+		checkArgs(t, []interface{}{p1, p2}, 1.5, 3)
+		return 2.5
+	})
+	args := []lokum.Object{&lokum.Float{Value: 1.5}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, &lokum.Array{}, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Float{Value: 2.5})
+}
+
+func TestFuncAFIRB(t *testing.T) {
+	fn := FuncAFIRB(func(p1 float64, p2 int) bool {
+		checkArgs(t, []interface{}{p1, p2}, 1.5, 3)
+		return true
+	})
+	args := []lokum.Object{&lokum.Float{Value: 1.5}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, &lokum.Array{}, &lokum.Array{})
+	checkResult(t, fn, args, lokum.TrueValue)
+}
+
+func TestFuncAFRB(t *testing.T) {
+	fn := FuncAFRB(func(p1 float64) bool {
+		checkArgs(t, []interface{}{p1}, 1.5)
+		return true
+	})
+	args := []lokum.Object{&lokum.Float{Value: 1.5}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, lokum.TrueValue)
+}
+
+func TestFuncASRS(t *testing.T) {
+	fn := FuncASRS(func(p1 string) string {
+		checkArgs(t, []interface{}{p1}, "a")
+		return "b"
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncASRSs(t *testing.T) {
+	fn := FuncASRSs(func(p1 string) []string {
+		checkArgs(t, []interface{}{p1}, "a")
+		return []string{"b"}
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, testArray(&lokum.String{Value: "b"}))
+}
+
+func TestFuncASRSE(t *testing.T) {
+	fail := false
+	fn := FuncASRSE(func(p1 string) (string, error) {
+		checkArgs(t, []interface{}{p1}, "a")
+		return "b", testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncASRE(t *testing.T) {
+	fail := false
+	fn := FuncASRE(func(p1 string) error {
+		checkArgs(t, []interface{}{p1}, "a")
+		return testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, lokum.TrueValue)
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncASSRE(t *testing.T) {
+	fail := false
+	fn := FuncASSRE(func(p1 string, p2 string) error {
+		checkArgs(t, []interface{}{p1, p2}, "a", "a")
+		return testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, lokum.UndefinedValue)
+	checkResult(t, fn, args, lokum.TrueValue)
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncASSRSs(t *testing.T) {
+	fn := FuncASSRSs(func(p1 string, p2 string) []string {
+		checkArgs(t, []interface{}{p1, p2}, "a", "a")
+		return []string{"b"}
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, lokum.UndefinedValue)
+	checkResult(t, fn, args, testArray(&lokum.String{Value: "b"}))
+}
+
+func TestFuncASSIRSs(t *testing.T) {
+	fn := FuncASSIRSs(func(p1 string, p2 string, p3 int) []string {
+		checkArgs(t, []interface{}{p1, p2, p3}, "a", "a", 3)
+		return []string{"b"}
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 3, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, lokum.UndefinedValue, &lokum.Array{})
+	checkResult(t, fn, args, testArray(&lokum.String{Value: "b"}))
+}
+
+func TestFuncASSRI(t *testing.T) {
+	fn := FuncASSRI(func(p1 string, p2 string) int {
+		checkArgs(t, []interface{}{p1, p2}, "a", "a")
+		return 5
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+}
+
+func TestFuncASSRS(t *testing.T) {
+	fn := FuncASSRS(func(p1 string, p2 string) string {
+		checkArgs(t, []interface{}{p1, p2}, "a", "a")
+		return "b"
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncASSRB(t *testing.T) {
+	fn := FuncASSRB(func(p1 string, p2 string) bool {
+		checkArgs(t, []interface{}{p1, p2}, "a", "a")
+		return true
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, lokum.UndefinedValue)
+	checkResult(t, fn, args, lokum.TrueValue)
+}
+
+func TestFuncASsSRS(t *testing.T) {
+	fn := FuncASsSRS(func(p1 []string, p2 string) string {
+		checkArgs(t, []interface{}{p1, p2}, []string{"a"}, "a")
+		return "b"
+	})
+	args := []lokum.Object{testArray(&lokum.String{Value: "a"}), &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, &lokum.Int{Value: 1}, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncASI64RE(t *testing.T) {
+	fail := false
+	fn := FuncASI64RE(func(p1 string, p2 int64) error {
+		checkArgs(t, []interface{}{p1, p2}, "a", int64(3))
+		return testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, &lokum.Array{})
+	checkResult(t, fn, args, lokum.TrueValue)
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncAIIRE(t *testing.T) {
+	fail := false
+	fn := FuncAIIRE(func(p1 int, p2 int) error {
+		checkArgs(t, []interface{}{p1, p2}, 3, 3)
+		return testError(fail)
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, &lokum.Array{}, &lokum.Array{})
+	checkResult(t, fn, args, lokum.TrueValue)
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncASIRS(t *testing.T) {
+	fn := FuncASIRS(func(p1 string, p2 int) string {
+		checkArgs(t, []interface{}{p1, p2}, "a", 3)
+		return "b"
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 2, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncASIIRE(t *testing.T) {
+	fail := false
+	fn := FuncASIIRE(func(p1 string, p2 int, p3 int) error {
+		checkArgs(t, []interface{}{p1, p2, p3}, "a", 3, 3)
+		return testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.Int{Value: 3}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 3, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, &lokum.Array{}, &lokum.Array{})
+	checkResult(t, fn, args, lokum.TrueValue)
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncAYRIE(t *testing.T) {
+	fail := false
+	fn := FuncAYRIE(func(p1 []byte) (int, error) {
+		checkArgs(t, []interface{}{p1}, []byte("a"))
+		return 5, testError(fail)
+	})
+	args := []lokum.Object{&lokum.Bytes{Value: []byte("a")}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Int{Value: 1})
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncAYRS(t *testing.T) {
+	fn := FuncAYRS(func(p1 []byte) string {
+		checkArgs(t, []interface{}{p1}, []byte("a"))
+		return "b"
+	})
+	args := []lokum.Object{&lokum.Bytes{Value: []byte("a")}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Int{Value: 1})
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncASRIE(t *testing.T) {
+	fail := false
+	fn := FuncASRIE(func(p1 string) (int, error) {
+		checkArgs(t, []interface{}{p1}, "a")
+		return 5, testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncASRYE(t *testing.T) {
+	fail := false
+	fn := FuncASRYE(func(p1 string) ([]byte, error) {
+		checkArgs(t, []interface{}{p1}, "a")
+		return []byte("b"), testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.Bytes{Value: []byte("b")})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncAIRSsE(t *testing.T) {
+	fail := false
+	fn := FuncAIRSsE(func(p1 int) ([]string, error) {
+		checkArgs(t, []interface{}{p1}, 3)
+		return []string{"b"}, testError(fail)
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, testArray(&lokum.String{Value: "b"}))
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncAIRS(t *testing.T) {
+	fn := FuncAIRS(func(p1 int) string {
+		checkArgs(t, []interface{}{p1}, 3)
+		return "b"
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncAVSRS(t *testing.T) {
+	fn := FuncAVSRS(func(p1 ...string) string {
+		checkArgs(t, []interface{}{p1}, []string{"a", "a"})
+		return "b"
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 0, true)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncART(t *testing.T) {
+	fn := FuncART(func() time.Time {
+		return testTime
+	})
+	args := []lokum.Object{}
+	checkArgCount(t, fn, args, 0, false)
+	checkResult(t, fn, args, &lokum.Time{Value: testTime})
+}
+
+func TestFuncATRS(t *testing.T) {
+	fn := FuncATRS(func(p1 time.Time) string {
+		checkArgs(t, []interface{}{p1}, testTime)
+		return "b"
+	})
+	args := []lokum.Object{&lokum.Time{Value: testTime}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.String{Value: "a"})
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+func TestFuncASRTE(t *testing.T) {
+	fail := false
+	fn := FuncASRTE(func(p1 string) (time.Time, error) {
+		checkArgs(t, []interface{}{p1}, "a")
+		return testTime, testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.Time{Value: testTime})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncASRME(t *testing.T) {
+	fail := false
+	fn := FuncASRME(func(p1 string) (map[string]interface{}, error) {
+		checkArgs(t, []interface{}{p1}, "a")
+		return map[string]interface{}{"a": int64(1)}, testError(fail)
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue)
+	checkResult(t, fn, args, testMap())
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncAMRSE(t *testing.T) {
+	fail := false
+	fn := FuncAMRSE(func(p1 map[string]interface{}) (string, error) {
+		checkArgs(t, []interface{}{p1}, map[string]interface{}{"a": int64(1)})
+		return "b", testError(fail)
+	})
+	args := []lokum.Object{testMap()}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Int{Value: 1})
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+	fail = true
+	checkResult(t, fn, args, testErrorResult)
+}
+
+func TestFuncASsRSs(t *testing.T) {
+	fn := FuncASsRSs(func(p1 []string) []string {
+		checkArgs(t, []interface{}{p1}, []string{"a"})
+		return []string{"b"}
+	})
+	args := []lokum.Object{testArray(&lokum.String{Value: "a"})}
+	checkArgCount(t, fn, args, 1, false)
+	checkArgTypes(t, fn, args, &lokum.Int{Value: 1})
+	checkResult(t, fn, args, testArray(&lokum.String{Value: "b"}))
+}
+
+func TestFuncAVIRI(t *testing.T) {
+	fn := FuncAVIRI(func(p1 ...int) int {
+		checkArgs(t, []interface{}{p1}, []int{3, 3})
+		return 5
+	})
+	args := []lokum.Object{&lokum.Int{Value: 3}, &lokum.Int{Value: 3}}
+	checkArgCount(t, fn, args, 0, true)
+	checkArgTypes(t, fn, args, &lokum.Array{})
+	checkResult(t, fn, args, &lokum.Int{Value: 5})
+}
+
+func TestFuncASVSRS(t *testing.T) {
+	fn := FuncASVSRS(func(p1 string, p2 ...string) string {
+		checkArgs(t, []interface{}{p1, p2}, "a", []string{"a", "a"})
+		return "b"
+	})
+	args := []lokum.Object{&lokum.String{Value: "a"}, &lokum.String{Value: "a"}, &lokum.String{Value: "a"}}
+	checkArgCount(t, fn, args, 1, true)
+	checkArgTypes(t, fn, args, lokum.UndefinedValue, lokum.UndefinedValue)
+	checkResult(t, fn, args, &lokum.String{Value: "b"})
+}
+
+var (
+	testTime        = time.Unix(1, 0).UTC()
+	testErrorResult = &lokum.Error{Value: &lokum.String{Value: "hata"}}
+)
+
+func testError(fail bool) error {
+	if fail {
+		return errors.New("hata")
+	}
+	return nil
+}
+
+func testArray(elems ...lokum.Object) *lokum.Array {
+	return &lokum.Array{Value: elems}
+}
+
+func testMap() lokum.Object {
+	m, _ := lokum.FromInterface(map[string]interface{}{"a": int64(1)})
+	return m
+}
+
+func checkArgs(t *testing.T, got []interface{}, want ...interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("argümanlar %#v, beklenen %#v", got, want)
+	}
+}
+
+func checkArgCount(t *testing.T, fn lokum.CallableFunc, args []lokum.Object, fixed int, variadic bool) {
+	t.Helper()
+	var counts []int
+	if fixed > 0 {
+		counts = append(counts, fixed-1)
+	}
+	if !variadic {
+		counts = append(counts, fixed+1)
+	}
+	for _, n := range counts {
+		in := make([]lokum.Object, n)
+		for i := range in {
+			in[i] = lokum.UndefinedValue
+			if i < len(args) {
+				in[i] = args[i]
+			}
+		}
+		if _, err := fn(in...); err != lokum.ErrWrongNumArguments {
+			t.Fatalf("%d argüman: hata %v, beklenen %v", n, err, lokum.ErrWrongNumArguments)
+		}
+	}
+}
+
+func checkArgTypes(t *testing.T, fn lokum.CallableFunc, args []lokum.Object, bad ...lokum.Object) {
+	t.Helper()
+	for i, b := range bad {
+		if b == nil {
+			continue
+		}
+		in := append([]lokum.Object{}, args...)
+		in[i] = b
+		_, err := fn(in...)
+		e, ok := err.(lokum.ErrInvalidArgumentType)
+		if !ok || e.Name != argName(i) || e.Found != b.TypeName() {
+			t.Fatalf("%s argüman: hata %v, beklenen %s için tip hatası", argName(i), err, argName(i))
+		}
+	}
+}
+
+func checkResult(t *testing.T, fn lokum.CallableFunc, args []lokum.Object, want lokum.Object) {
+	t.Helper()
+	res, err := fn(args...)
+	if err != nil {
+		t.Fatalf("beklenmeyen hata: %v", err)
+	}
+	if res.TypeName() != want.TypeName() || res.String() != want.String() {
+		t.Fatalf("sonuç %s(%s), beklenen %s(%s)", res.TypeName(), res, want.TypeName(), want)
+	}
+}
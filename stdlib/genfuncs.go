@@ -0,0 +1,667 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+type kind struct {
+	code     string
+	prefix   string
+	conv     string
+	helper   string
+	expected string
+	result   string
+
+	// func_typedefs_test.go için örnek değerler
+	arg   string
+	value string
+	bad   string
+	ret   string
+	want  string
+}
+
+var kinds = map[string]kind{
+	"int": {
+		code:     "I",
+		prefix:   "i",
+		conv:     "lokum.ToInt",
+		expected: "sayı(geçerli)",
+		result:   "return &lokum.Int{Value: int64(%[1]s)}, nil\n",
+		arg:      `&lokum.Int{Value: 3}`,
+		value:    `3`,
+		bad:      `&lokum.Array{}`,
+		ret:      `5`,
+		want:     `&lokum.Int{Value: 5}`,
+	},
+	"int64": {
+		code:     "I64",
+		prefix:   "i",
+		conv:     "lokum.ToInt64",
+		expected: "sayı(geçerli)",
+		result:   "return &lokum.Int{Value: %[1]s}, nil\n",
+		arg:      `&lokum.Int{Value: 3}`,
+		value:    `int64(3)`,
+		bad:      `&lokum.Array{}`,
+		ret:      `int64(5)`,
+		want:     `&lokum.Int{Value: 5}`,
+	},
+	"float64": {
+		code:     "F",
+		prefix:   "f",
+		conv:     "lokum.ToFloat64",
+		expected: "float(geçerli)",
+		result:   "return &lokum.Float{Value: %[1]s}, nil\n",
+		arg:      `&lokum.Float{Value: 1.5}`,
+		value:    `1.5`,
+		bad:      `&lokum.Array{}`,
+		ret:      `2.5`,
+		want:     `&lokum.Float{Value: 2.5}`,
+	},
+	"bool": {
+		code:     "B",
+		prefix:   "b",
+		conv:     "lokum.ToBool",
+		expected: "mantıksal(geçerli)",
+		result: `if %[1]s {
+			return lokum.TrueValue, nil
+		}
+		return lokum.FalseValue, nil
+		`,
+		arg:   `lokum.TrueValue`,
+		value: `true`,
+		ret:   `true`,
+		want:  `lokum.TrueValue`,
+	},
+	"string": {
+		code:     "S",
+		prefix:   "s",
+		conv:     "lokum.ToString",
+		expected: "yazı(geçerli)",
+		result: `if len(%[1]s) > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		return &lokum.String{Value: %[1]s}, nil
+		`,
+		arg:   `&lokum.String{Value: "a"}`,
+		value: `"a"`,
+		bad:   `lokum.UndefinedValue`,
+		ret:   `"b"`,
+		want:  `&lokum.String{Value: "b"}`,
+	},
+	"[]byte": {
+		code:     "Y",
+		prefix:   "y",
+		conv:     "lokum.ToByteSlice",
+		expected: "bytes(geçerli)",
+		result: `if len(%[1]s) > lokum.MaxBytesLen {
+			return nil, lokum.ErrBytesLimit
+		}
+		return &lokum.Bytes{Value: %[1]s}, nil
+		`,
+		arg:   `&lokum.Bytes{Value: []byte("a")}`,
+		value: `[]byte("a")`,
+		bad:   `&lokum.Int{Value: 1}`,
+		ret:   `[]byte("b")`,
+		want:  `&lokum.Bytes{Value: []byte("b")}`,
+	},
+	"time.Time": {
+		code:     "T",
+		prefix:   "t",
+		conv:     "lokum.ToTime",
+		expected: "zaman(geçerli)",
+		result:   "return &lokum.Time{Value: %[1]s}, nil\n",
+		arg:      `&lokum.Time{Value: testTime}`,
+		value:    `testTime`,
+		bad:      `&lokum.String{Value: "a"}`,
+		ret:      `testTime`,
+		want:     `&lokum.Time{Value: testTime}`,
+	},
+	"[]string": {
+		code:   "Ss",
+		prefix: "ss",
+		helper: "stringsArg",
+		result: `arr := &lokum.Array{}
+		for _, elem := range %[1]s {
+			if len(elem) > lokum.MaxStringLen {
+				return nil, lokum.ErrStringLimit
+			}
+			arr.Value = append(arr.Value, &lokum.String{Value: elem})
+		}
+		return arr, nil
+		`,
+		arg:   `testArray(&lokum.String{Value: "a"})`,
+		value: `[]string{"a"}`,
+		bad:   `&lokum.Int{Value: 1}`,
+		ret:   `[]string{"b"}`,
+		want:  `testArray(&lokum.String{Value: "b"})`,
+	},
+	"[]int": {
+		code:   "Is",
+		prefix: "is",
+		helper: "intsArg",
+		result: `arr := &lokum.Array{}
+		for _, elem := range %[1]s {
+			arr.Value = append(arr.Value, &lokum.Int{Value: int64(elem)})
+		}
+		return arr, nil
+		`,
+		arg:   `testArray(&lokum.Int{Value: 1})`,
+		value: `[]int{1}`,
+		bad:   `&lokum.Int{Value: 1}`,
+		ret:   `[]int{2}`,
+		want:  `testArray(&lokum.Int{Value: 2})`,
+	},
+	"map[string]interface{}": {
+		code:   "M",
+		prefix: "m",
+		helper: "mapArg",
+		result: "return lokum.FromInterface(%[1]s)\n",
+		arg:    `testMap()`,
+		value:  `map[string]interface{}{"a": int64(1)}`,
+		bad:    `&lokum.Int{Value: 1}`,
+		ret:    `map[string]interface{}{"a": int64(1)}`,
+		want:   `testMap()`,
+	},
+	"error": {
+		code: "E",
+	},
+}
+
+type adapter struct {
+	name     string
+	sig      string
+	params   []kind
+	variadic bool
+	result   *kind
+	err      bool
+}
+
+func parseAdapter(sig string) (*adapter, error) {
+	expr, err := parser.ParseExpr(sig)
+	if err != nil {
+		return nil, err
+	}
+	ft, ok := expr.(*ast.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("fonksiyon tipi değil: %s", sig)
+	}
+
+	a := &adapter{sig: types.ExprString(ft)}
+	name := "FuncA"
+	for _, field := range ft.Params.List {
+		typ := field.Type
+		if e, ok := typ.(*ast.Ellipsis); ok {
+			a.variadic = true
+			typ = e.Elt
+			name += "V"
+		}
+		k, ok := kinds[types.ExprString(typ)]
+		if !ok || k.prefix == "" {
+			return nil, fmt.Errorf("desteklenmeyen parametre tipi: %s",
+				types.ExprString(typ))
+		}
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			a.params = append(a.params, k)
+			name += k.code
+		}
+	}
+
+	name += "R"
+	var results []ast.Expr
+	if ft.Results != nil {
+		for _, field := range ft.Results.List {
+			results = append(results, field.Type)
+		}
+	}
+	for i, typ := range results {
+		k, ok := kinds[types.ExprString(typ)]
+		if !ok {
+			return nil, fmt.Errorf("desteklenmeyen dönüş tipi: %s",
+				types.ExprString(typ))
+		}
+		switch {
+		case k.code == "E" && i == len(results)-1:
+			a.err = true
+		case k.code != "E" && a.result == nil:
+			a.result = &k
+		default:
+			return nil, fmt.Errorf("desteklenmeyen dönüş listesi: %s", sig)
+		}
+		name += k.code
+	}
+	a.name = name
+	return a, nil
+}
+
+func (a *adapter) write(out *bytes.Buffer) {
+	fmt.Fprintf(out, "func %s(fn %s) lokum.CallableFunc {\n", a.name, a.sig)
+	out.WriteString("return func(args ...lokum.Object) (ret lokum.Object, err error) {\n")
+
+	fixed := len(a.params)
+	if a.variadic {
+		fixed--
+		if fixed > 0 {
+			fmt.Fprintf(out, "if len(args) < %d {\n", fixed)
+			out.WriteString("return nil, lokum.ErrWrongNumArguments\n}\n")
+		}
+	} else {
+		fmt.Fprintf(out, "if len(args) != %d {\n", fixed)
+		out.WriteString("return nil, lokum.ErrWrongNumArguments\n}\n")
+	}
+
+	var call []string
+	for i := 0; i < fixed; i++ {
+		k := a.params[i]
+		v := fmt.Sprintf("%s%d", k.prefix, i+1)
+		arg := fmt.Sprintf("args[%d]", i)
+		writeConversion(out, k, v, arg, fmt.Sprintf("%q", ordinal(i)), ":=")
+		call = append(call, v)
+	}
+	if a.variadic {
+		k := a.params[fixed]
+		v := fmt.Sprintf("%s%d", k.prefix, fixed+1)
+		rest, name := "args", "argName(i)"
+		if fixed > 0 {
+			rest = fmt.Sprintf("args[%d:]", fixed)
+			name = fmt.Sprintf("argName(%d + i)", fixed)
+		}
+		fmt.Fprintf(out, "%s := make([]%s, len(%s))\n", v, typeOf(k), rest)
+		fmt.Fprintf(out, "for i, a := range %s {\n", rest)
+		writeConversion(out, k, v+"[i]", "a", name, "=")
+		out.WriteString("}\n")
+		call = append(call, v+"...")
+	}
+
+	callExpr := fmt.Sprintf("fn(%s)", strings.Join(call, ", "))
+	switch {
+	case a.result == nil && !a.err:
+		out.WriteString(callExpr + "\nreturn lokum.UndefinedValue, nil\n")
+	case a.result == nil:
+		fmt.Fprintf(out, "return wrapError(%s), nil\n", callExpr)
+	case a.err:
+		fmt.Fprintf(out, "res, err := %s\n", callExpr)
+		out.WriteString("if err != nil {\nreturn wrapError(err), nil\n}\n")
+		fmt.Fprintf(out, a.result.result, "res")
+	default:
+		fmt.Fprintf(out, "res := %s\n", callExpr)
+		fmt.Fprintf(out, a.result.result, "res")
+	}
+	out.WriteString("}\n}\n\n")
+}
+
+func (a *adapter) writeTest(out *bytes.Buffer) {
+	fixed := len(a.params)
+	if a.variadic {
+		fixed--
+	}
+	var args, params, names, values, bad []string
+	for i, k := range a.params {
+		p := fmt.Sprintf("p%d", i+1)
+		names = append(names, p)
+		if a.variadic && i == fixed {
+			params = append(params, p+" ..."+typeOf(k))
+			args = append(args, k.arg, k.arg)
+			values = append(values, fmt.Sprintf("[]%s{%s, %s}", typeOf(k), k.value, k.value))
+		} else {
+			params = append(params, p+" "+typeOf(k))
+			args = append(args, k.arg)
+			values = append(values, k.value)
+		}
+		if k.bad == "" {
+			bad = append(bad, "nil")
+		} else {
+			bad = append(bad, k.bad)
+		}
+	}
+
+	var results []string
+	if a.result != nil {
+		results = append(results, typeOf(*a.result))
+	}
+	if a.err {
+		results = append(results, "error")
+	}
+	sig := strings.Join(results, ", ")
+	if len(results) > 1 {
+		sig = "(" + sig + ")"
+	}
+
+	fmt.Fprintf(out, "func Test%s(t *testing.T) {\n", a.name)
+	if a.err {
+		out.WriteString("fail := false\n")
+	}
+	fmt.Fprintf(out, "fn := %s(func(%s) %s {\n", a.name, strings.Join(params, ", "), sig)
+	if len(names) > 0 {
+		fmt.Fprintf(out, "checkArgs(t, []interface{}{%s}, %s)\n",
+			strings.Join(names, ", "), strings.Join(values, ", "))
+	}
+	var ret []string
+	if a.result != nil {
+		ret = append(ret, a.result.ret)
+	}
+	if a.err {
+		ret = append(ret, "testError(fail)")
+	}
+	if len(ret) > 0 {
+		fmt.Fprintf(out, "return %s\n", strings.Join(ret, ", "))
+	}
+	out.WriteString("})\n")
+
+	want := "lokum.UndefinedValue"
+	switch {
+	case a.result != nil:
+		want = a.result.want
+	case a.err:
+		want = "lokum.TrueValue"
+	}
+	fmt.Fprintf(out, "args := []lokum.Object{%s}\n", strings.Join(args, ", "))
+	fmt.Fprintf(out, "checkArgCount(t, fn, args, %d, %t)\n", fixed, a.variadic)
+	if len(bad) > 0 {
+		fmt.Fprintf(out, "checkArgTypes(t, fn, args, %s)\n", strings.Join(bad, ", "))
+	}
+	fmt.Fprintf(out, "checkResult(t, fn, args, %s)\n", want)
+	if a.err {
+		out.WriteString("fail = true\ncheckResult(t, fn, args, testErrorResult)\n")
+	}
+	out.WriteString("}\n\n")
+}
+
+func writeConversion(out *bytes.Buffer, k kind, v, arg, name, op string) {
+	if k.helper != "" {
+		if op == ":=" {
+			fmt.Fprintf(out, "%s, err := %s(%s, %s)\n", v, k.helper, name, arg)
+		} else {
+			fmt.Fprintf(out, "%s, err = %s(%s, %s)\n", v, k.helper, name, arg)
+		}
+		out.WriteString("if err != nil {\nreturn nil, err\n}\n")
+		return
+	}
+	if op == ":=" {
+		fmt.Fprintf(out, "%s, ok := %s(%s)\n", v, k.conv, arg)
+	} else {
+		fmt.Fprintf(out, "var ok bool\n%s, ok = %s(%s)\n", v, k.conv, arg)
+	}
+	fmt.Fprintf(out, `if !ok {
+		return nil, lokum.ErrInvalidArgumentType{
+			Name:     %s,
+			Expected: %q,
+			Found:    %s.TypeName(),
+		}
+	}
+	`, name, k.expected, arg)
+}
+
+func typeOf(k kind) string {
+	for name, c := range kinds {
+		if c.code == k.code {
+			return name
+		}
+	}
+	return ""
+}
+
+var ordinals = []string{"first", "second", "third", "fourth", "fifth"}
+
+func ordinal(i int) string {
+	if i < len(ordinals) {
+		return ordinals[i]
+	}
+	return fmt.Sprint(i + 1)
+}
+
+const helpers = `
+var argNames = []string{"first", "second", "third", "fourth", "fifth"}
+
+func argName(i int) string {
+	if i < len(argNames) {
+		return argNames[i]
+	}
+	return fmt.Sprint(i + 1)
+}
+
+func arrayArg(name string, o lokum.Object) ([]lokum.Object, error) {
+	switch o := o.(type) {
+	case *lokum.Array:
+		return o.Value, nil
+	case *lokum.ImmutableArray:
+		return o.Value, nil
+	}
+	return nil, lokum.ErrInvalidArgumentType{
+		Name:     name,
+		Expected: "liste",
+		Found:    o.TypeName(),
+	}
+}
+
+func stringsArg(name string, o lokum.Object) ([]string, error) {
+	arr, err := arrayArg(name, o)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for idx, a := range arr {
+		as, ok := lokum.ToString(a)
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     fmt.Sprintf("%s[%d]", name, idx),
+				Expected: "yazı(geçerli)",
+				Found:    a.TypeName(),
+			}
+		}
+		res = append(res, as)
+	}
+	return res, nil
+}
+
+func intsArg(name string, o lokum.Object) ([]int, error) {
+	arr, err := arrayArg(name, o)
+	if err != nil {
+		return nil, err
+	}
+	var res []int
+	for idx, a := range arr {
+		ai, ok := lokum.ToInt(a)
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     fmt.Sprintf("%s[%d]", name, idx),
+				Expected: "sayı(geçerli)",
+				Found:    a.TypeName(),
+			}
+		}
+		res = append(res, ai)
+	}
+	return res, nil
+}
+
+func mapArg(name string, o lokum.Object) (map[string]interface{}, error) {
+	var m *lokum.OrderedMap
+	switch o := o.(type) {
+	case *lokum.Map:
+		m = o.Value
+	case *lokum.ImmutableMap:
+		m = o.Value
+	default:
+		return nil, lokum.ErrInvalidArgumentType{
+			Name:     name,
+			Expected: "harita",
+			Found:    o.TypeName(),
+		}
+	}
+	res := make(map[string]interface{}, m.Len())
+	var err error
+	m.Range(func(key, value lokum.Object) bool {
+		k, ok := key.(*lokum.String)
+		if !ok {
+			err = lokum.ErrInvalidArgumentType{
+				Name:     fmt.Sprintf("%s[%s]", name, key),
+				Expected: "yazı",
+				Found:    key.TypeName(),
+			}
+			return false
+		}
+		res[k.Value] = lokum.ToInterface(value)
+		return true
+	})
+	return res, err
+}
+`
+
+const testHelpers = `
+var (
+	testTime        = time.Unix(1, 0).UTC()
+	testErrorResult = &lokum.Error{Value: &lokum.String{Value: "hata"}}
+)
+
+func testError(fail bool) error {
+	if fail {
+		return errors.New("hata")
+	}
+	return nil
+}
+
+func testArray(elems ...lokum.Object) *lokum.Array {
+	return &lokum.Array{Value: elems}
+}
+
+func testMap() lokum.Object {
+	m, _ := lokum.FromInterface(map[string]interface{}{"a": int64(1)})
+	return m
+}
+
+func checkArgs(t *testing.T, got []interface{}, want ...interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("argümanlar %#v, beklenen %#v", got, want)
+	}
+}
+
+func checkArgCount(t *testing.T, fn lokum.CallableFunc, args []lokum.Object, fixed int, variadic bool) {
+	t.Helper()
+	var counts []int
+	if fixed > 0 {
+		counts = append(counts, fixed-1)
+	}
+	if !variadic {
+		counts = append(counts, fixed+1)
+	}
+	for _, n := range counts {
+		in := make([]lokum.Object, n)
+		for i := range in {
+			in[i] = lokum.UndefinedValue
+			if i < len(args) {
+				in[i] = args[i]
+			}
+		}
+		if _, err := fn(in...); err != lokum.ErrWrongNumArguments {
+			t.Fatalf("%d argüman: hata %v, beklenen %v", n, err, lokum.ErrWrongNumArguments)
+		}
+	}
+}
+
+func checkArgTypes(t *testing.T, fn lokum.CallableFunc, args []lokum.Object, bad ...lokum.Object) {
+	t.Helper()
+	for i, b := range bad {
+		if b == nil {
+			continue
+		}
+		in := append([]lokum.Object{}, args...)
+		in[i] = b
+		_, err := fn(in...)
+		e, ok := err.(lokum.ErrInvalidArgumentType)
+		if !ok || e.Name != argName(i) || e.Found != b.TypeName() {
+			t.Fatalf("%s argüman: hata %v, beklenen %s için tip hatası", argName(i), err, argName(i))
+		}
+	}
+}
+
+func checkResult(t *testing.T, fn lokum.CallableFunc, args []lokum.Object, want lokum.Object) {
+	t.Helper()
+	res, err := fn(args...)
+	if err != nil {
+		t.Fatalf("beklenmeyen hata: %v", err)
+	}
+	if res.TypeName() != want.TypeName() || res.String() != want.String() {
+		t.Fatalf("sonuç %s(%s), beklenen %s(%s)", res.TypeName(), res, want.TypeName(), want)
+	}
+}
+`
+
+func writeSource(target string, out *bytes.Buffer) {
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	// depodaki Go dosyaları CRLF satır sonu kullanır
+	src = bytes.ReplaceAll(src, []byte("\n"), []byte("\r\n"))
+	if err := ioutil.WriteFile(target, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func main() {
+	const source = "func_typedefs.spec"
+	f, err := os.Open(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var adapters []*adapter
+	usesTime := false
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		sig := strings.TrimSpace(scanner.Text())
+		if sig == "" || strings.HasPrefix(sig, "//") {
+			continue
+		}
+		a, err := parseAdapter(sig)
+		if err != nil {
+			log.Fatalf("%s:%d: %s", source, line, err)
+		}
+		adapters = append(adapters, a)
+		usesTime = usesTime || strings.Contains(a.sig, "time.Time")
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// genfuncs.go ile oluşturuldu, değiştirmeyin.\n\n")
+	out.WriteString("package stdlib\n\nimport (\n\"fmt\"\n")
+	if usesTime {
+		out.WriteString("\"time\"\n")
+	}
+	out.WriteString("\n\"github.com/onrirr/lokum\"\n)\n\n")
+	for _, a := range adapters {
+		a.write(&out)
+	}
+	out.WriteString(helpers)
+	writeSource("func_typedefs.go", &out)
+
+	out.Reset()
+	out.WriteString("// genfuncs.go ile oluşturuldu, değiştirmeyin.\n\n")
+	out.WriteString("package stdlib\n\nimport (\n\"errors\"\n\"reflect\"\n\"testing\"\n")
+	out.WriteString("\"time\"\n\n\"github.com/onrirr/lokum\"\n)\n\n")
+	for _, a := range adapters {
+		a.writeTest(&out)
+	}
+	out.WriteString(testHelpers)
+	writeSource("func_typedefs_test.go", &out)
+}
@@ -1,101 +1,183 @@
-package stdlib
-
-import (
-	"fmt"
-
-	"github.com/onrirr/lokum"
-)
-
-var fmtModule = map[string]lokum.Object{
-	"yazdırnf": &lokum.UserFunction{Name: "yazdırnf", Value: fmtPrint},
-	"yazdırf":  &lokum.UserFunction{Name: "yazdırf", Value: fmtPrintf},
-	"yazdır":   &lokum.UserFunction{Name: "yazdır", Value: fmtPrintln},
-	"sprintf":  &lokum.UserFunction{Name: "sprintf", Value: fmtSprintf},
-}
-
-func fmtPrint(args ...lokum.Object) (ret lokum.Object, err error) {
-	printArgs, err := getPrintArgs(args...)
-	if err != nil {
-		return nil, err
-	}
-	_, _ = fmt.Print(printArgs...)
-	return nil, nil
-}
-
-func fmtPrintf(args ...lokum.Object) (ret lokum.Object, err error) {
-	numArgs := len(args)
-	if numArgs == 0 {
-		return nil, lokum.ErrWrongNumArguments
-	}
-
-	format, ok := args[0].(*lokum.String)
-	if !ok {
-		return nil, lokum.ErrInvalidArgumentType{
-			Name:     "format",
-			Expected: "string",
-			Found:    args[0].TypeName(),
-		}
-	}
-	if numArgs == 1 {
-		fmt.Print(format)
-		return nil, nil
-	}
-
-	s, err := lokum.Format(format.Value, args[1:]...)
-	if err != nil {
-		return nil, err
-	}
-	fmt.Print(s)
-	return nil, nil
-}
-
-func fmtPrintln(args ...lokum.Object) (ret lokum.Object, err error) {
-	printArgs, err := getPrintArgs(args...)
-	if err != nil {
-		return nil, err
-	}
-	printArgs = append(printArgs, "\n")
-	_, _ = fmt.Print(printArgs...)
-	return nil, nil
-}
-
-func fmtSprintf(args ...lokum.Object) (ret lokum.Object, err error) {
-	numArgs := len(args)
-	if numArgs == 0 {
-		return nil, lokum.ErrWrongNumArguments
-	}
-
-	format, ok := args[0].(*lokum.String)
-	if !ok {
-		return nil, lokum.ErrInvalidArgumentType{
-			Name:     "format",
-			Expected: "string",
-			Found:    args[0].TypeName(),
-		}
-	}
-	if numArgs == 1 {
-
-		return format, nil
-	}
-	s, err := lokum.Format(format.Value, args[1:]...)
-	if err != nil {
-		return nil, err
-	}
-	return &lokum.String{Value: s}, nil
-}
-
-func getPrintArgs(args ...lokum.Object) ([]interface{}, error) {
-	var printArgs []interface{}
-	l := 0
-	for _, arg := range args {
-		s, _ := lokum.ToString(arg)
-		slen := len(s)
-
-		if l+slen > lokum.MaxStringLen {
-			return nil, lokum.ErrStringLimit
-		}
-		l += slen
-		printArgs = append(printArgs, s)
-	}
-	return printArgs, nil
-}
+package stdlib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/onrirr/lokum"
+)
+
+var fmtModule = map[string]lokum.Object{
+	"yazdırnf":   printFunc("yazdırnf", fmtPrint),
+	"yazdırf":    printFunc("yazdırf", fmtPrintf),
+	"yazdır":     printFunc("yazdır", fmtPrintln),
+	"hata_yaz":   errorPrintFunc("hata_yaz", fmtPrintln),
+	"sprintf":    &lokum.UserFunction{Name: "sprintf", Value: fmtSprintf},
+	"satır_oku":  &lokum.UserFunction{Name: "satır_oku", Interop: ioReadLine},
+	"tümünü_oku": &lokum.UserFunction{Name: "tümünü_oku", Interop: ioReadAll},
+}
+
+func printFunc(
+	name string,
+	fn func(w io.Writer, args ...lokum.Object) (lokum.Object, error),
+) *lokum.UserFunction {
+	return writerFunc(name, fn, false)
+}
+
+func errorPrintFunc(
+	name string,
+	fn func(w io.Writer, args ...lokum.Object) (lokum.Object, error),
+) *lokum.UserFunction {
+	return writerFunc(name, fn, true)
+}
+
+func writerFunc(
+	name string,
+	fn func(w io.Writer, args ...lokum.Object) (lokum.Object, error),
+	stderr bool,
+) *lokum.UserFunction {
+	return &lokum.UserFunction{
+		Name: name,
+		Value: func(args ...lokum.Object) (lokum.Object, error) {
+			if stderr {
+				return fn(os.Stderr, args...)
+			}
+			return fn(os.Stdout, args...)
+		},
+		Interop: func(
+			vm lokum.Interop,
+			args ...lokum.Object,
+		) (lokum.Object, error) {
+			if stderr {
+				return fn(vm.Stderr(), args...)
+			}
+			return fn(vm.Stdout(), args...)
+		},
+	}
+}
+
+func fmtPrint(w io.Writer, args ...lokum.Object) (ret lokum.Object, err error) {
+	printArgs, err := getPrintArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprint(w, printArgs...)
+	return nil, nil
+}
+
+func fmtPrintf(w io.Writer, args ...lokum.Object) (ret lokum.Object, err error) {
+	numArgs := len(args)
+	if numArgs == 0 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+
+	format, ok := args[0].(*lokum.String)
+	if !ok {
+		return nil, lokum.ErrInvalidArgumentType{
+			Name:     "format",
+			Expected: "string",
+			Found:    args[0].TypeName(),
+		}
+	}
+	if numArgs == 1 {
+		_, _ = fmt.Fprint(w, format.Value)
+		return nil, nil
+	}
+
+	s, err := lokum.Format(format.Value, args[1:]...)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprint(w, s)
+	return nil, nil
+}
+
+func fmtPrintln(w io.Writer, args ...lokum.Object) (ret lokum.Object, err error) {
+	printArgs, err := getPrintArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+	printArgs = append(printArgs, "\n")
+	_, _ = fmt.Fprint(w, printArgs...)
+	return nil, nil
+}
+
+func ioReadLine(vm lokum.Interop, args ...lokum.Object) (lokum.Object, error) {
+	if len(args) != 0 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	r, ok := vm.Stdin().(*bufio.Reader)
+	if !ok {
+		r = bufio.NewReader(vm.Stdin())
+	}
+	line, err := r.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return lokum.UndefinedValue, nil
+	} else if err != nil && err != io.EOF {
+		return wrapError(err), nil
+	}
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	if len(line) > lokum.MaxStringLen {
+		return nil, lokum.ErrStringLimit
+	}
+	return &lokum.String{Value: line}, nil
+}
+
+func ioReadAll(vm lokum.Interop, args ...lokum.Object) (lokum.Object, error) {
+	if len(args) != 0 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	b, err := ioutil.ReadAll(vm.Stdin())
+	if err != nil {
+		return wrapError(err), nil
+	}
+	if len(b) > lokum.MaxStringLen {
+		return nil, lokum.ErrStringLimit
+	}
+	return &lokum.String{Value: string(b)}, nil
+}
+
+func fmtSprintf(args ...lokum.Object) (ret lokum.Object, err error) {
+	numArgs := len(args)
+	if numArgs == 0 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+
+	format, ok := args[0].(*lokum.String)
+	if !ok {
+		return nil, lokum.ErrInvalidArgumentType{
+			Name:     "format",
+			Expected: "string",
+			Found:    args[0].TypeName(),
+		}
+	}
+	if numArgs == 1 {
+
+		return format, nil
+	}
+	s, err := lokum.Format(format.Value, args[1:]...)
+	if err != nil {
+		return nil, err
+	}
+	return &lokum.String{Value: s}, nil
+}
+
+func getPrintArgs(args ...lokum.Object) ([]interface{}, error) {
+	var printArgs []interface{}
+	l := 0
+	for _, arg := range args {
+		s, _ := lokum.ToString(arg)
+		slen := len(s)
+
+		if l+slen > lokum.MaxStringLen {
+			return nil, lokum.ErrStringLimit
+		}
+		l += slen
+		printArgs = append(printArgs, s)
+	}
+	return printArgs, nil
+}
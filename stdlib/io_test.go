@@ -0,0 +1,77 @@
+package stdlib_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/stdlib"
+)
+
+func runIO(t *testing.T, src, in string) (stdout, stderr string, c *lokum.Compiled) {
+	var out, errOut bytes.Buffer
+	s := lokum.NewScript([]byte(src))
+	s.SetImports(stdlib.GetModuleMap("io"))
+	s.SetStdin(strings.NewReader(in))
+	s.SetStdout(&out)
+	s.SetStderr(&errOut)
+	c, err := s.Run()
+	if err != nil {
+		t.Fatalf("%s: %v", src, err)
+	}
+	return out.String(), errOut.String(), c
+}
+
+func TestIOWriters(t *testing.T) {
+	src := `io := kullan("io")
+io.yazdır("a", 1)
+io.yazdırnf("b")
+io.yazdırf("%d-%s\n", 2, "c")
+io.hata_yaz("hata", 3)
+yazdır("yerleşik")
+`
+	stdout, stderr, _ := runIO(t, src, "")
+	if want := "a1\nb2-c\n\"yerleşik\"\n"; stdout != want {
+		t.Fatalf("stdout %q, beklenen %q", stdout, want)
+	}
+	if want := "hata3\n"; stderr != want {
+		t.Fatalf("stderr %q, beklenen %q", stderr, want)
+	}
+}
+
+func TestIOReaders(t *testing.T) {
+	src := `io := kullan("io")
+a := io.satır_oku()
+b := io.satır_oku()
+kalan := io.tümünü_oku()
+son := io.satır_oku()
+`
+	_, _, c := runIO(t, src, "bir\r\niki\nüç\ndört")
+	for name, want := range map[string]string{
+		"a":     "bir",
+		"b":     "iki",
+		"kalan": "üç\ndört",
+	} {
+		if got := c.Get(name).String(); got != want {
+			t.Fatalf("%s = %q, beklenen %q", name, got, want)
+		}
+	}
+	if v := c.Get("son").Value(); v != nil {
+		t.Fatalf("girdi bitince satır_oku %v döndürdü", v)
+	}
+}
+
+func TestIOReadLineWithoutNewline(t *testing.T) {
+	src := `io := kullan("io")
+a := io.satır_oku()
+b := io.satır_oku()
+`
+	_, _, c := runIO(t, src, "tek")
+	if got := c.Get("a").String(); got != "tek" {
+		t.Fatalf("a = %q", got)
+	}
+	if v := c.Get("b").Value(); v != nil {
+		t.Fatalf("b = %v", v)
+	}
+}
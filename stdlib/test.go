@@ -0,0 +1,345 @@
+package stdlib
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/onrirr/lokum"
+	"github.com/onrirr/lokum/token"
+)
+
+var testModule = map[string]lokum.Object{
+	"eşit":       &lokum.UserFunction{Name: "eşit", Value: testEqual},
+	"eşit_değil": &lokum.UserFunction{Name: "eşit_değil", Value: testNotEqual},
+	"doğru_mu":   &lokum.UserFunction{Name: "doğru_mu", Value: testTrue},
+	"yanlış_mı":  &lokum.UserFunction{Name: "yanlış_mı", Value: testFalse},
+	"hata_bekle": &lokum.UserFunction{Name: "hata_bekle", Interop: testExpectError},
+	"tablo":      &lokum.UserFunction{Name: "tablo", Interop: testTable},
+	"başarısız":  &lokum.UserFunction{Name: "başarısız", Value: testFail},
+	"atla":       &lokum.UserFunction{Name: "atla", Value: testSkip},
+}
+
+type TestFailure struct {
+	Message  string
+	Case     string
+	Expected string
+	Actual   string
+	Compared bool
+}
+
+func (e *TestFailure) Error() string {
+	msg := e.Message
+	if e.Compared && !strings.Contains(e.Expected+e.Actual, "\n") {
+		msg += fmt.Sprintf(": beklenen %s, gerçek %s", e.Expected, e.Actual)
+	}
+	if e.Case != "" {
+		msg = "vaka " + e.Case + ": " + msg
+	}
+	return msg
+}
+
+type TestSkip struct {
+	Reason string
+}
+
+func (e *TestSkip) Error() string {
+	if e.Reason == "" {
+		return "test atlandı"
+	}
+	return "test atlandı: " + e.Reason
+}
+
+func testMessage(def string, args []lokum.Object) string {
+	if len(args) == 0 {
+		return def
+	}
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i], _ = lokum.ToString(arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+func testEqual(args ...lokum.Object) (lokum.Object, error) {
+	if len(args) < 2 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	if args[0].Equals(args[1]) {
+		return lokum.UndefinedValue, nil
+	}
+	return nil, &TestFailure{
+		Message:  testMessage("değerler eşit değil", args[2:]),
+		Expected: FormatValue(args[0]),
+		Actual:   FormatValue(args[1]),
+		Compared: true,
+	}
+}
+
+func testNotEqual(args ...lokum.Object) (lokum.Object, error) {
+	if len(args) < 2 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	if !args[0].Equals(args[1]) {
+		return lokum.UndefinedValue, nil
+	}
+	return nil, &TestFailure{
+		Message: testMessage(
+			"değerler eşit olmamalıydı: "+args[1].String(), args[2:]),
+	}
+}
+
+func testTrue(args ...lokum.Object) (lokum.Object, error) {
+	if len(args) < 1 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	if !args[0].IsFalsy() {
+		return lokum.UndefinedValue, nil
+	}
+	return nil, &TestFailure{
+		Message: testMessage("doğru bekleniyordu: "+args[0].String(), args[1:]),
+	}
+}
+
+func testFalse(args ...lokum.Object) (lokum.Object, error) {
+	if len(args) < 1 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	if args[0].IsFalsy() {
+		return lokum.UndefinedValue, nil
+	}
+	return nil, &TestFailure{
+		Message: testMessage("yanlış bekleniyordu: "+args[0].String(), args[1:]),
+	}
+}
+
+func testExpectError(vm lokum.Interop, args ...lokum.Object) (lokum.Object, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	var want string
+	if len(args) == 2 {
+		s, ok := args[1].(*lokum.String)
+		if !ok {
+			return nil, lokum.ErrInvalidArgumentType{
+				Name:     "second",
+				Expected: "string",
+				Found:    args[1].TypeName(),
+			}
+		}
+		want = s.Value
+	}
+
+	res, err := callRecover(vm, args[0])
+	var msg string
+	switch {
+	case err != nil:
+		var failure *TestFailure
+		var skip *TestSkip
+		if errors.As(err, &failure) || errors.As(err, &skip) {
+			return nil, err
+		}
+		msg = runtimeMessage(err)
+		res = &lokum.Error{Value: &lokum.String{Value: msg}}
+	case isError(res):
+		msg, _ = lokum.ToString(res.(*lokum.Error).Value)
+	default:
+		return nil, &TestFailure{
+			Message: "hata bekleniyordu, dönen değer: " + res.String(),
+		}
+	}
+	if !strings.Contains(msg, want) {
+		return nil, &TestFailure{
+			Message:  "hata mesajı beklenen metni içermiyor",
+			Expected: strconv.Quote(want),
+			Actual:   strconv.Quote(msg),
+			Compared: true,
+		}
+	}
+	return res, nil
+}
+
+func isError(o lokum.Object) bool {
+	_, ok := o.(*lokum.Error)
+	return ok
+}
+
+func callRecover(vm lokum.Interop, fn lokum.Object, args ...lokum.Object) (res lokum.Object, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panik: %v", r)
+		}
+	}()
+	return vm.Call(fn, args...)
+}
+
+func runtimeMessage(err error) string {
+	msg := err.Error()
+	if i := strings.Index(msg, "\n\tat "); i >= 0 {
+		msg = msg[:i]
+	}
+	for strings.HasPrefix(msg, "Çalışma Hatası: ") {
+		msg = strings.TrimPrefix(msg, "Çalışma Hatası: ")
+	}
+	return msg
+}
+
+func testTable(vm lokum.Interop, args ...lokum.Object) (lokum.Object, error) {
+	if len(args) != 2 {
+		return nil, lokum.ErrWrongNumArguments
+	}
+	if !args[1].CanCall() {
+		return nil, lokum.ErrInvalidArgumentType{
+			Name:     "second",
+			Expected: "callable",
+			Found:    args[1].TypeName(),
+		}
+	}
+
+	type tableCase struct {
+		name  string
+		value lokum.Object
+	}
+	var cases []tableCase
+	addArray := func(elems []lokum.Object) {
+		for i, elem := range elems {
+			name := strconv.Itoa(i)
+			if m, ok := elem.(*lokum.Map); ok {
+				if v, ok := m.Value.GetString("ad"); ok {
+					name, _ = lokum.ToString(v)
+				}
+			}
+			cases = append(cases, tableCase{name, elem})
+		}
+	}
+	addMap := func(m *lokum.OrderedMap) {
+		m.Range(func(key, value lokum.Object) bool {
+			name, _ := lokum.ToString(key)
+			cases = append(cases, tableCase{name, value})
+			return true
+		})
+	}
+	switch arg := args[0].(type) {
+	case *lokum.Array:
+		addArray(arg.Value)
+	case *lokum.ImmutableArray:
+		addArray(arg.Value)
+	case *lokum.Map:
+		addMap(arg.Value)
+	case *lokum.ImmutableMap:
+		addMap(arg.Value)
+	default:
+		return nil, lokum.ErrInvalidArgumentType{
+			Name:     "first",
+			Expected: "array/map",
+			Found:    args[0].TypeName(),
+		}
+	}
+
+	for _, c := range cases {
+		if _, err := callRecover(vm, args[1], c.value); err != nil {
+			var failure *TestFailure
+			if errors.As(err, &failure) {
+				if failure.Case == "" {
+					failure.Case = c.name
+				}
+				return nil, err
+			}
+			return nil, &caseError{name: c.name, err: err}
+		}
+	}
+	return lokum.UndefinedValue, nil
+}
+
+// caseError, tablo vakasındaki çalışma hatasını vaka adıyla bildirir. İç
+// hatanın mesajı tekrar sarılmaz; son konum tablo çağrısının kendisi
+// olduğundan dış hatada zaten yer alır ve atılır.
+type caseError struct {
+	name string
+	err  error
+}
+
+func (e *caseError) Error() string {
+	var trace string
+	msg := e.err.Error()
+	if i := strings.Index(msg, "\n\tat "); i >= 0 {
+		trace = msg[i:strings.LastIndex(msg, "\n\tat ")]
+	}
+	return "vaka " + e.name + ": " + runtimeMessage(e.err) + trace
+}
+
+func (e *caseError) Unwrap() error {
+	return e.err
+}
+
+func testFail(args ...lokum.Object) (lokum.Object, error) {
+	return nil, &TestFailure{Message: testMessage("test başarısız", args)}
+}
+
+func testSkip(args ...lokum.Object) (lokum.Object, error) {
+	return nil, &TestSkip{Reason: testMessage("", args)}
+}
+
+func FormatValue(o lokum.Object) string {
+	var sb strings.Builder
+	formatValue(&sb, o, "")
+	return sb.String()
+}
+
+func formatValue(sb *strings.Builder, o lokum.Object, indent string) {
+	var elems []lokum.Object
+	var m *lokum.OrderedMap
+	open, close := "[", "]"
+	switch o := o.(type) {
+	case *lokum.Array:
+		elems = o.Value
+	case *lokum.ImmutableArray:
+		elems = o.Value
+	case *lokum.Map:
+		m, open, close = o.Value, "{", "}"
+	case *lokum.ImmutableMap:
+		m, open, close = o.Value, "{", "}"
+	default:
+		sb.WriteString(o.String())
+		return
+	}
+	if len(elems) == 0 && (m == nil || m.Len() == 0) {
+		sb.WriteString(o.String())
+		return
+	}
+
+	inner := indent + "  "
+	sb.WriteString(open + "\n")
+	for _, elem := range elems {
+		sb.WriteString(inner)
+		formatValue(sb, elem, inner)
+		sb.WriteString(",\n")
+	}
+	if m != nil {
+		m.Range(func(key, value lokum.Object) bool {
+			sb.WriteString(inner + formatKey(key) + ": ")
+			formatValue(sb, value, inner)
+			sb.WriteString(",\n")
+			return true
+		})
+	}
+	sb.WriteString(indent + close)
+}
+
+func formatKey(key lokum.Object) string {
+	s, ok := key.(*lokum.String)
+	if !ok {
+		return key.String()
+	}
+	if s.Value == "" || token.Lookup(s.Value) != token.Ident {
+		return s.String()
+	}
+	for i, r := range s.Value {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return s.String()
+		}
+	}
+	return s.Value
+}
@@ -1,889 +1,1363 @@
-package lokum
-
-import (
-	"fmt"
-	"sync/atomic"
-
-	"github.com/onrirr/lokum/parser"
-	"github.com/onrirr/lokum/token"
-)
-
-type frame struct {
-	fn          *CompiledFunction
-	freeVars    []*ObjectPtr
-	ip          int
-	basePointer int
-}
-
-type VM struct {
-	constants   []Object
-	stack       [StackSize]Object
-	sp          int
-	globals     []Object
-	fileSet     *parser.SourceFileSet
-	frames      [MaxFrames]frame
-	framesIndex int
-	curFrame    *frame
-	curInsts    []byte
-	ip          int
-	aborting    int64
-	maxAllocs   int64
-	allocs      int64
-	err         error
-}
-
-func NewVM(
-	bytecode *Bytecode,
-	globals []Object,
-	maxAllocs int64,
-) *VM {
-	if globals == nil {
-		globals = make([]Object, GlobalsSize)
-	}
-	v := &VM{
-		constants:   bytecode.Constants,
-		sp:          0,
-		globals:     globals,
-		fileSet:     bytecode.FileSet,
-		framesIndex: 1,
-		ip:          -1,
-		maxAllocs:   maxAllocs,
-	}
-	v.frames[0].fn = bytecode.MainFunction
-	v.frames[0].ip = -1
-	v.curFrame = &v.frames[0]
-	v.curInsts = v.curFrame.fn.Instructions
-	return v
-}
-
-func (v *VM) Abort() {
-	atomic.StoreInt64(&v.aborting, 1)
-}
-
-func (v *VM) Run() (err error) {
-
-	v.sp = 0
-	v.curFrame = &(v.frames[0])
-	v.curInsts = v.curFrame.fn.Instructions
-	v.framesIndex = 1
-	v.ip = -1
-	v.allocs = v.maxAllocs + 1
-
-	v.run()
-	atomic.StoreInt64(&v.aborting, 0)
-	err = v.err
-	if err != nil {
-		filePos := v.fileSet.Position(
-			v.curFrame.fn.SourcePos(v.ip - 1))
-		err = fmt.Errorf("Çalışma Hatası: %w\n\tat %s",
-			err, filePos)
-		for v.framesIndex > 1 {
-			v.framesIndex--
-			v.curFrame = &v.frames[v.framesIndex-1]
-			filePos = v.fileSet.Position(
-				v.curFrame.fn.SourcePos(v.curFrame.ip - 1))
-			err = fmt.Errorf("%w\n\tat %s", err, filePos)
-		}
-		return err
-	}
-	return nil
-}
-
-func (v *VM) run() {
-	for atomic.LoadInt64(&v.aborting) == 0 {
-		v.ip++
-
-		switch v.curInsts[v.ip] {
-		case parser.OpConstant:
-			v.ip += 2
-			cidx := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-
-			v.stack[v.sp] = v.constants[cidx]
-			v.sp++
-		case parser.OpNull:
-			v.stack[v.sp] = UndefinedValue
-			v.sp++
-		case parser.OpBinaryOp:
-			v.ip++
-			right := v.stack[v.sp-1]
-			left := v.stack[v.sp-2]
-			tok := token.Token(v.curInsts[v.ip])
-			res, e := left.BinaryOp(tok, right)
-			if e != nil {
-				v.sp -= 2
-				if e == ErrInvalidOperator {
-					v.err = fmt.Errorf("geçersiz operasyon: %s %s %s",
-						left.TypeName(), tok.String(), right.TypeName())
-					return
-				}
-				v.err = e
-				return
-			}
-
-			v.allocs--
-			if v.allocs == 0 {
-				v.err = ErrObjectAllocLimit
-				return
-			}
-
-			v.stack[v.sp-2] = res
-			v.sp--
-		case parser.OpEqual:
-			right := v.stack[v.sp-1]
-			left := v.stack[v.sp-2]
-			v.sp -= 2
-			if left.Equals(right) {
-				v.stack[v.sp] = TrueValue
-			} else {
-				v.stack[v.sp] = FalseValue
-			}
-			v.sp++
-		case parser.OpNotEqual:
-			right := v.stack[v.sp-1]
-			left := v.stack[v.sp-2]
-			v.sp -= 2
-			if left.Equals(right) {
-				v.stack[v.sp] = FalseValue
-			} else {
-				v.stack[v.sp] = TrueValue
-			}
-			v.sp++
-		case parser.OpPop:
-			v.sp--
-		case parser.OpTrue:
-			v.stack[v.sp] = TrueValue
-			v.sp++
-		case parser.OpFalse:
-			v.stack[v.sp] = FalseValue
-			v.sp++
-		case parser.OpLNot:
-			operand := v.stack[v.sp-1]
-			v.sp--
-			if operand.IsFalsy() {
-				v.stack[v.sp] = TrueValue
-			} else {
-				v.stack[v.sp] = FalseValue
-			}
-			v.sp++
-		case parser.OpBComplement:
-			operand := v.stack[v.sp-1]
-			v.sp--
-
-			switch x := operand.(type) {
-			case *Int:
-				var res Object = &Int{Value: ^x.Value}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = res
-				v.sp++
-			default:
-				v.err = fmt.Errorf("geçersiz operasyon: ^%s",
-					operand.TypeName())
-				return
-			}
-		case parser.OpMinus:
-			operand := v.stack[v.sp-1]
-			v.sp--
-
-			switch x := operand.(type) {
-			case *Int:
-				var res Object = &Int{Value: -x.Value}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = res
-				v.sp++
-			case *Float:
-				var res Object = &Float{Value: -x.Value}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = res
-				v.sp++
-			default:
-				v.err = fmt.Errorf("geçersiz operasyon: -%s",
-					operand.TypeName())
-				return
-			}
-		case parser.OpJumpFalsy:
-			v.ip += 2
-			v.sp--
-			if v.stack[v.sp].IsFalsy() {
-				pos := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-				v.ip = pos - 1
-			}
-		case parser.OpAndJump:
-			v.ip += 2
-			if v.stack[v.sp-1].IsFalsy() {
-				pos := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-				v.ip = pos - 1
-			} else {
-				v.sp--
-			}
-		case parser.OpOrJump:
-			v.ip += 2
-			if v.stack[v.sp-1].IsFalsy() {
-				v.sp--
-			} else {
-				pos := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-				v.ip = pos - 1
-			}
-		case parser.OpJump:
-			pos := int(v.curInsts[v.ip+2]) | int(v.curInsts[v.ip+1])<<8
-			v.ip = pos - 1
-		case parser.OpSetGlobal:
-			v.ip += 2
-			v.sp--
-			globalIndex := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-			v.globals[globalIndex] = v.stack[v.sp]
-		case parser.OpSetSelGlobal:
-			v.ip += 3
-			globalIndex := int(v.curInsts[v.ip-1]) | int(v.curInsts[v.ip-2])<<8
-			numSelectors := int(v.curInsts[v.ip])
-
-			selectors := make([]Object, numSelectors)
-			for i := 0; i < numSelectors; i++ {
-				selectors[i] = v.stack[v.sp-numSelectors+i]
-			}
-			val := v.stack[v.sp-numSelectors-1]
-			v.sp -= numSelectors + 1
-			e := indexAssign(v.globals[globalIndex], val, selectors)
-			if e != nil {
-				v.err = e
-				return
-			}
-		case parser.OpGetGlobal:
-			v.ip += 2
-			globalIndex := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-			val := v.globals[globalIndex]
-			v.stack[v.sp] = val
-			v.sp++
-		case parser.OpArray:
-			v.ip += 2
-			numElements := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-
-			var elements []Object
-			for i := v.sp - numElements; i < v.sp; i++ {
-				elements = append(elements, v.stack[i])
-			}
-			v.sp -= numElements
-
-			var arr Object = &Array{Value: elements}
-			v.allocs--
-			if v.allocs == 0 {
-				v.err = ErrObjectAllocLimit
-				return
-			}
-
-			v.stack[v.sp] = arr
-			v.sp++
-		case parser.OpMap:
-			v.ip += 2
-			numElements := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
-			kv := make(map[string]Object, numElements)
-			for i := v.sp - numElements; i < v.sp; i += 2 {
-				key := v.stack[i]
-				value := v.stack[i+1]
-				kv[key.(*String).Value] = value
-			}
-			v.sp -= numElements
-
-			var m Object = &Map{Value: kv}
-			v.allocs--
-			if v.allocs == 0 {
-				v.err = ErrObjectAllocLimit
-				return
-			}
-			v.stack[v.sp] = m
-			v.sp++
-		case parser.OpError:
-			value := v.stack[v.sp-1]
-			var e Object = &Error{
-				Value: value,
-			}
-			v.allocs--
-			if v.allocs == 0 {
-				v.err = ErrObjectAllocLimit
-				return
-			}
-			v.stack[v.sp-1] = e
-		case parser.OpImmutable:
-			value := v.stack[v.sp-1]
-			switch value := value.(type) {
-			case *Array:
-				var immutableArray Object = &ImmutableArray{
-					Value: value.Value,
-				}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp-1] = immutableArray
-			case *Map:
-				var immutableMap Object = &ImmutableMap{
-					Value: value.Value,
-				}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp-1] = immutableMap
-			}
-		case parser.OpIndex:
-			index := v.stack[v.sp-1]
-			left := v.stack[v.sp-2]
-			v.sp -= 2
-
-			val, err := left.IndexGet(index)
-			if err != nil {
-				if err == ErrNotIndexable {
-					v.err = fmt.Errorf("index alınamaz: %s", index.TypeName())
-					return
-				}
-				if err == ErrInvalidIndexType {
-					v.err = fmt.Errorf("geçersiz index tipi: %s",
-						index.TypeName())
-					return
-				}
-				v.err = err
-				return
-			}
-			if val == nil {
-				val = UndefinedValue
-			}
-			v.stack[v.sp] = val
-			v.sp++
-		case parser.OpSliceIndex:
-			high := v.stack[v.sp-1]
-			low := v.stack[v.sp-2]
-			left := v.stack[v.sp-3]
-			v.sp -= 3
-
-			var lowIdx int64
-			if low != UndefinedValue {
-				if lowInt, ok := low.(*Int); ok {
-					lowIdx = lowInt.Value
-				} else {
-					v.err = fmt.Errorf("geçersiz slice index: %s",
-						low.TypeName())
-					return
-				}
-			}
-
-			switch left := left.(type) {
-			case *Array:
-				numElements := int64(len(left.Value))
-				var highIdx int64
-				if high == UndefinedValue {
-					highIdx = numElements
-				} else if highInt, ok := high.(*Int); ok {
-					highIdx = highInt.Value
-				} else {
-					v.err = fmt.Errorf("geçersiz slice index: %s",
-						high.TypeName())
-					return
-				}
-				if lowIdx > highIdx {
-					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
-						lowIdx, highIdx)
-					return
-				}
-				if lowIdx < 0 {
-					lowIdx = 0
-				} else if lowIdx > numElements {
-					lowIdx = numElements
-				}
-				if highIdx < 0 {
-					highIdx = 0
-				} else if highIdx > numElements {
-					highIdx = numElements
-				}
-				var val Object = &Array{
-					Value: left.Value[lowIdx:highIdx],
-				}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = val
-				v.sp++
-			case *ImmutableArray:
-				numElements := int64(len(left.Value))
-				var highIdx int64
-				if high == UndefinedValue {
-					highIdx = numElements
-				} else if highInt, ok := high.(*Int); ok {
-					highIdx = highInt.Value
-				} else {
-					v.err = fmt.Errorf("geçersiz slice index: %s",
-						high.TypeName())
-					return
-				}
-				if lowIdx > highIdx {
-					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
-						lowIdx, highIdx)
-					return
-				}
-				if lowIdx < 0 {
-					lowIdx = 0
-				} else if lowIdx > numElements {
-					lowIdx = numElements
-				}
-				if highIdx < 0 {
-					highIdx = 0
-				} else if highIdx > numElements {
-					highIdx = numElements
-				}
-				var val Object = &Array{
-					Value: left.Value[lowIdx:highIdx],
-				}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = val
-				v.sp++
-			case *String:
-				numElements := int64(len(left.Value))
-				var highIdx int64
-				if high == UndefinedValue {
-					highIdx = numElements
-				} else if highInt, ok := high.(*Int); ok {
-					highIdx = highInt.Value
-				} else {
-					v.err = fmt.Errorf("geçersiz slice index: %s",
-						high.TypeName())
-					return
-				}
-				if lowIdx > highIdx {
-					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
-						lowIdx, highIdx)
-					return
-				}
-				if lowIdx < 0 {
-					lowIdx = 0
-				} else if lowIdx > numElements {
-					lowIdx = numElements
-				}
-				if highIdx < 0 {
-					highIdx = 0
-				} else if highIdx > numElements {
-					highIdx = numElements
-				}
-				var val Object = &String{
-					Value: left.Value[lowIdx:highIdx],
-				}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = val
-				v.sp++
-			case *Bytes:
-				numElements := int64(len(left.Value))
-				var highIdx int64
-				if high == UndefinedValue {
-					highIdx = numElements
-				} else if highInt, ok := high.(*Int); ok {
-					highIdx = highInt.Value
-				} else {
-					v.err = fmt.Errorf("geçersiz slice index: %s",
-						high.TypeName())
-					return
-				}
-				if lowIdx > highIdx {
-					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
-						lowIdx, highIdx)
-					return
-				}
-				if lowIdx < 0 {
-					lowIdx = 0
-				} else if lowIdx > numElements {
-					lowIdx = numElements
-				}
-				if highIdx < 0 {
-					highIdx = 0
-				} else if highIdx > numElements {
-					highIdx = numElements
-				}
-				var val Object = &Bytes{
-					Value: left.Value[lowIdx:highIdx],
-				}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = val
-				v.sp++
-			}
-		case parser.OpCall:
-			numArgs := int(v.curInsts[v.ip+1])
-			spread := int(v.curInsts[v.ip+2])
-			v.ip += 2
-
-			value := v.stack[v.sp-1-numArgs]
-			if !value.CanCall() {
-				v.err = fmt.Errorf("çağrılamaz: %s", value.TypeName())
-				return
-			}
-
-			if spread == 1 {
-				v.sp--
-				switch arr := v.stack[v.sp].(type) {
-				case *Array:
-					for _, item := range arr.Value {
-						v.stack[v.sp] = item
-						v.sp++
-					}
-					numArgs += len(arr.Value) - 1
-				case *ImmutableArray:
-					for _, item := range arr.Value {
-						v.stack[v.sp] = item
-						v.sp++
-					}
-					numArgs += len(arr.Value) - 1
-				default:
-					v.err = fmt.Errorf("array değil: %s", arr.TypeName())
-					return
-				}
-			}
-
-			if callee, ok := value.(*CompiledFunction); ok {
-				if callee.VarArgs {
-
-					realArgs := callee.NumParameters - 1
-					varArgs := numArgs - realArgs
-					if varArgs >= 0 {
-						numArgs = realArgs + 1
-						args := make([]Object, varArgs)
-						spStart := v.sp - varArgs
-						for i := spStart; i < v.sp; i++ {
-							args[i-spStart] = v.stack[i]
-						}
-						v.stack[spStart] = &Array{Value: args}
-						v.sp = spStart + 1
-					}
-				}
-				if numArgs != callee.NumParameters {
-					if callee.VarArgs {
-						v.err = fmt.Errorf(
-							"yanlış argüman sayısı: want>=%d, got=%d",
-							callee.NumParameters-1, numArgs)
-					} else {
-						v.err = fmt.Errorf(
-							"yanlış argüman sayısı: want=%d, got=%d",
-							callee.NumParameters, numArgs)
-					}
-					return
-				}
-
-				if callee == v.curFrame.fn {
-					nextOp := v.curInsts[v.ip+1]
-					if nextOp == parser.OpReturn ||
-						(nextOp == parser.OpPop &&
-							parser.OpReturn == v.curInsts[v.ip+2]) {
-						for p := 0; p < numArgs; p++ {
-							v.stack[v.curFrame.basePointer+p] =
-								v.stack[v.sp-numArgs+p]
-						}
-						v.sp -= numArgs + 1
-						v.ip = -1
-						continue
-					}
-				}
-				if v.framesIndex >= MaxFrames {
-					v.err = ErrStackOverflow
-					return
-				}
-
-				v.curFrame.ip = v.ip
-				v.curFrame = &(v.frames[v.framesIndex])
-				v.curFrame.fn = callee
-				v.curFrame.freeVars = callee.Free
-				v.curFrame.basePointer = v.sp - numArgs
-				v.curInsts = callee.Instructions
-				v.ip = -1
-				v.framesIndex++
-				v.sp = v.sp - numArgs + callee.NumLocals
-			} else {
-				var args []Object
-				args = append(args, v.stack[v.sp-numArgs:v.sp]...)
-				ret, e := value.Call(args...)
-				v.sp -= numArgs + 1
-
-				if e != nil {
-					if e == ErrWrongNumArguments {
-						v.err = fmt.Errorf(
-							"yanlış argüman sayısı '%s'",
-							value.TypeName())
-						return
-					}
-					if e, ok := e.(ErrInvalidArgumentType); ok {
-						v.err = fmt.Errorf(
-							"geçersiz argüman tipi for '%s' in call to '%s': "+
-								"expected %s, found %s",
-							e.Name, value.TypeName(), e.Expected, e.Found)
-						return
-					}
-					v.err = e
-					return
-				}
-
-				if ret == nil {
-					ret = UndefinedValue
-				}
-				v.allocs--
-				if v.allocs == 0 {
-					v.err = ErrObjectAllocLimit
-					return
-				}
-				v.stack[v.sp] = ret
-				v.sp++
-			}
-		case parser.OpReturn:
-			v.ip++
-			var retVal Object
-			if int(v.curInsts[v.ip]) == 1 {
-				retVal = v.stack[v.sp-1]
-			} else {
-				retVal = UndefinedValue
-			}
-			//v.sp--
-			v.framesIndex--
-			v.curFrame = &v.frames[v.framesIndex-1]
-			v.curInsts = v.curFrame.fn.Instructions
-			v.ip = v.curFrame.ip
-			//v.sp = lastFrame.basePointer - 1
-			v.sp = v.frames[v.framesIndex].basePointer
-
-			v.stack[v.sp-1] = retVal
-			//v.sp++
-		case parser.OpDefineLocal:
-			v.ip++
-			localIndex := int(v.curInsts[v.ip])
-			sp := v.curFrame.basePointer + localIndex
-
-			val := v.stack[v.sp-1]
-			v.sp--
-			v.stack[sp] = val
-		case parser.OpSetLocal:
-			localIndex := int(v.curInsts[v.ip+1])
-			v.ip++
-			sp := v.curFrame.basePointer + localIndex
-
-			val := v.stack[v.sp-1]
-			v.sp--
-			if obj, ok := v.stack[sp].(*ObjectPtr); ok {
-				*obj.Value = val
-				val = obj
-			}
-			v.stack[sp] = val
-		case parser.OpSetSelLocal:
-			localIndex := int(v.curInsts[v.ip+1])
-			numSelectors := int(v.curInsts[v.ip+2])
-			v.ip += 2
-
-			selectors := make([]Object, numSelectors)
-			for i := 0; i < numSelectors; i++ {
-				selectors[i] = v.stack[v.sp-numSelectors+i]
-			}
-			val := v.stack[v.sp-numSelectors-1]
-			v.sp -= numSelectors + 1
-			dst := v.stack[v.curFrame.basePointer+localIndex]
-			if obj, ok := dst.(*ObjectPtr); ok {
-				dst = *obj.Value
-			}
-			if e := indexAssign(dst, val, selectors); e != nil {
-				v.err = e
-				return
-			}
-		case parser.OpGetLocal:
-			v.ip++
-			localIndex := int(v.curInsts[v.ip])
-			val := v.stack[v.curFrame.basePointer+localIndex]
-			if obj, ok := val.(*ObjectPtr); ok {
-				val = *obj.Value
-			}
-			v.stack[v.sp] = val
-			v.sp++
-		case parser.OpGetBuiltin:
-			v.ip++
-			builtinIndex := int(v.curInsts[v.ip])
-			v.stack[v.sp] = builtinFuncs[builtinIndex]
-			v.sp++
-		case parser.OpClosure:
-			v.ip += 3
-			constIndex := int(v.curInsts[v.ip-1]) | int(v.curInsts[v.ip-2])<<8
-			numFree := int(v.curInsts[v.ip])
-			fn, ok := v.constants[constIndex].(*CompiledFunction)
-			if !ok {
-				v.err = fmt.Errorf("fonksiyon değil: %s", fn.TypeName())
-				return
-			}
-			free := make([]*ObjectPtr, numFree)
-			for i := 0; i < numFree; i++ {
-				switch freeVar := (v.stack[v.sp-numFree+i]).(type) {
-				case *ObjectPtr:
-					free[i] = freeVar
-				default:
-					free[i] = &ObjectPtr{
-						Value: &v.stack[v.sp-numFree+i],
-					}
-				}
-			}
-			v.sp -= numFree
-			cl := &CompiledFunction{
-				Instructions:  fn.Instructions,
-				NumLocals:     fn.NumLocals,
-				NumParameters: fn.NumParameters,
-				VarArgs:       fn.VarArgs,
-				SourceMap:     fn.SourceMap,
-				Free:          free,
-			}
-			v.allocs--
-			if v.allocs == 0 {
-				v.err = ErrObjectAllocLimit
-				return
-			}
-			v.stack[v.sp] = cl
-			v.sp++
-		case parser.OpGetFreePtr:
-			v.ip++
-			freeIndex := int(v.curInsts[v.ip])
-			val := v.curFrame.freeVars[freeIndex]
-			v.stack[v.sp] = val
-			v.sp++
-		case parser.OpGetFree:
-			v.ip++
-			freeIndex := int(v.curInsts[v.ip])
-			val := *v.curFrame.freeVars[freeIndex].Value
-			v.stack[v.sp] = val
-			v.sp++
-		case parser.OpSetFree:
-			v.ip++
-			freeIndex := int(v.curInsts[v.ip])
-			*v.curFrame.freeVars[freeIndex].Value = v.stack[v.sp-1]
-			v.sp--
-		case parser.OpGetLocalPtr:
-			v.ip++
-			localIndex := int(v.curInsts[v.ip])
-			sp := v.curFrame.basePointer + localIndex
-			val := v.stack[sp]
-			var freeVar *ObjectPtr
-			if obj, ok := val.(*ObjectPtr); ok {
-				freeVar = obj
-			} else {
-				freeVar = &ObjectPtr{Value: &val}
-				v.stack[sp] = freeVar
-			}
-			v.stack[v.sp] = freeVar
-			v.sp++
-		case parser.OpSetSelFree:
-			v.ip += 2
-			freeIndex := int(v.curInsts[v.ip-1])
-			numSelectors := int(v.curInsts[v.ip])
-
-			selectors := make([]Object, numSelectors)
-			for i := 0; i < numSelectors; i++ {
-				selectors[i] = v.stack[v.sp-numSelectors+i]
-			}
-			val := v.stack[v.sp-numSelectors-1]
-			v.sp -= numSelectors + 1
-			e := indexAssign(*v.curFrame.freeVars[freeIndex].Value,
-				val, selectors)
-			if e != nil {
-				v.err = e
-				return
-			}
-		case parser.OpIteratorInit:
-			var iterator Object
-			dst := v.stack[v.sp-1]
-			v.sp--
-			if !dst.CanIterate() {
-				v.err = fmt.Errorf("sayılamaz: %s", dst.TypeName())
-				return
-			}
-			iterator = dst.Iterate()
-			v.allocs--
-			if v.allocs == 0 {
-				v.err = ErrObjectAllocLimit
-				return
-			}
-			v.stack[v.sp] = iterator
-			v.sp++
-		case parser.OpIteratorNext:
-			iterator := v.stack[v.sp-1]
-			v.sp--
-			hasMore := iterator.(Iterator).Next()
-			if hasMore {
-				v.stack[v.sp] = TrueValue
-			} else {
-				v.stack[v.sp] = FalseValue
-			}
-			v.sp++
-		case parser.OpIteratorKey:
-			iterator := v.stack[v.sp-1]
-			v.sp--
-			val := iterator.(Iterator).Key()
-			v.stack[v.sp] = val
-			v.sp++
-		case parser.OpIteratorValue:
-			iterator := v.stack[v.sp-1]
-			v.sp--
-			val := iterator.(Iterator).Value()
-			v.stack[v.sp] = val
-			v.sp++
-		case parser.OpSuspend:
-			return
-		default:
-			v.err = fmt.Errorf("bilinmeyen opcode: %d", v.curInsts[v.ip])
-			return
-		}
-	}
-}
-
-func (v *VM) IsStackEmpty() bool {
-	return v.sp == 0
-}
-
-func indexAssign(dst, src Object, selectors []Object) error {
-	numSel := len(selectors)
-	for sidx := numSel - 1; sidx > 0; sidx-- {
-		next, err := dst.IndexGet(selectors[sidx])
-		if err != nil {
-			if err == ErrNotIndexable {
-				return fmt.Errorf("index alınamaz: %s", dst.TypeName())
-			}
-			if err == ErrInvalidIndexType {
-				return fmt.Errorf("geçersiz index tipi: %s",
-					selectors[sidx].TypeName())
-			}
-			return err
-		}
-		dst = next
-	}
-
-	if err := dst.IndexSet(selectors[0], src); err != nil {
-		if err == ErrNotIndexAssignable {
-			return fmt.Errorf("geçersiz: %s", dst.TypeName())
-		}
-		if err == ErrInvalidIndexValueType {
-			return fmt.Errorf("geçersiz: %s", src.TypeName())
-		}
-		return err
-	}
-	return nil
-}
+package lokum
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/onrirr/lokum/parser"
+	"github.com/onrirr/lokum/token"
+)
+
+type frame struct {
+	fn          *CompiledFunction
+	freeVars    []*ObjectPtr
+	ip          int
+	basePointer int
+}
+
+type VM struct {
+	constants   []Object
+	stack       [StackSize]Object
+	sp          int
+	globals     []Object
+	fileSet     *parser.SourceFileSet
+	frames      [MaxFrames]frame
+	framesIndex int
+	curFrame    *frame
+	curInsts    []byte
+	ip          int
+	aborting    int64
+	abortLock   sync.Mutex
+	abortCh     chan struct{}
+	maxAllocs   int64
+	allocs      int64
+	err         error
+	childLock   sync.Mutex
+	children    map[*VM]struct{}
+	root        *VM
+	tasks       sync.WaitGroup
+	budget      *runBudget
+	share       budgetShare
+	callSizes   []int64
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+
+	maxInstructions   int64
+	maxDuration       time.Duration
+	deadline          time.Time
+	instructions      int64
+	ticks             int64
+	started           bool
+	tickBase          int64
+	spawnedInstrs     int64
+	spawnedAllocs     int64
+	spawnedAllocBytes int64
+	maxAllocBytes     int64
+	allocBytes        int64
+	accounting        bool
+	maxStringLen      int
+	maxBytesLen       int
+	injected          *injectedGlobals
+	hooks             Hooks
+	debugger          *Debugger
+	profiler          *Profiler
+	profAllocs        int64
+	profAllocBytes    int64
+	profLast          time.Time
+	coverage          *Coverage
+	coverCode         *byte
+	coverHits         []int64
+	child             bool
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func NewVM(
+	bytecode *Bytecode,
+	globals []Object,
+	maxAllocs int64,
+) *VM {
+	if globals == nil {
+		globals = make([]Object, GlobalsSize)
+	}
+	v := &VM{
+		constants:   bytecode.Constants,
+		sp:          0,
+		globals:     globals,
+		fileSet:     bytecode.FileSet,
+		framesIndex: 1,
+		ip:          -1,
+		maxAllocs:   maxAllocs,
+
+		maxInstructions: -1,
+		maxAllocBytes:   -1,
+	}
+	v.frames[0].fn = bytecode.MainFunction
+	v.frames[0].ip = -1
+	v.curFrame = &v.frames[0]
+	v.curInsts = v.curFrame.fn.Instructions
+	return v
+}
+
+func (v *VM) SetStdin(r io.Reader) {
+	if r != nil {
+		if _, ok := r.(*bufio.Reader); !ok {
+			r = bufio.NewReader(r)
+		}
+	}
+	v.stdin = r
+}
+
+func (v *VM) SetStdout(w io.Writer) {
+	v.stdout = w
+}
+
+func (v *VM) SetStderr(w io.Writer) {
+	v.stderr = w
+}
+
+func (v *VM) Stdin() io.Reader {
+	if v.stdin == nil {
+		return stdin
+	}
+	return v.stdin
+}
+
+func (v *VM) Stdout() io.Writer {
+	if v.stdout == nil {
+		return os.Stdout
+	}
+	return v.stdout
+}
+
+func (v *VM) Stderr() io.Writer {
+	if v.stderr == nil {
+		return os.Stderr
+	}
+	return v.stderr
+}
+
+func (v *VM) Abort() {
+	atomic.StoreInt64(&v.aborting, 1)
+	v.abortLock.Lock()
+	if v.abortCh == nil {
+		v.abortCh = make(chan struct{})
+	}
+	select {
+	case <-v.abortCh:
+	default:
+		close(v.abortCh)
+	}
+	v.abortLock.Unlock()
+	v.childLock.Lock()
+	for child := range v.children {
+		child.Abort()
+	}
+	v.childLock.Unlock()
+}
+
+func (v *VM) aborted() <-chan struct{} {
+	v.abortLock.Lock()
+	defer v.abortLock.Unlock()
+	if v.abortCh == nil {
+		v.abortCh = make(chan struct{})
+	}
+	return v.abortCh
+}
+
+func (v *VM) resetAbort() {
+	v.abortLock.Lock()
+	if v.abortCh != nil {
+		select {
+		case <-v.abortCh:
+			v.abortCh = nil
+		default:
+		}
+	}
+	atomic.StoreInt64(&v.aborting, 0)
+	v.abortLock.Unlock()
+}
+
+func (v *VM) stopTasks() {
+	v.childLock.Lock()
+	for child := range v.children {
+		child.Abort()
+	}
+	v.childLock.Unlock()
+	v.tasks.Wait()
+}
+
+func (v *VM) Run() (err error) {
+	v.startDeadline()
+	return v.runFrom(0)
+}
+
+func (v *VM) runFrom(sp int) (err error) {
+	v.sp = sp
+	v.curFrame = &(v.frames[0])
+	v.curInsts = v.curFrame.fn.Instructions
+	v.framesIndex = 1
+	v.ip = -1
+	v.err = nil
+	v.allocs = v.maxAllocs + 1
+	v.instructions = 0
+	v.allocBytes = 0
+	v.accounting = v.accountsAllocBytes()
+	v.share = budgetShare{}
+	if v.root == nil {
+		v.budget = &runBudget{root: v}
+	}
+	atomic.StoreInt64(&v.spawnedInstrs, 0)
+	atomic.StoreInt64(&v.spawnedAllocs, 0)
+	atomic.StoreInt64(&v.spawnedAllocBytes, 0)
+	v.refill()
+	v.started = true
+	if v.profiler != nil {
+		v.markProfile()
+	}
+	if v.coverage != nil || v.root != nil {
+		v.ticks, v.tickBase = 0, 0
+	}
+	if v.debugger != nil {
+		v.debugger.enter(v, false)
+		defer v.debugger.leave()
+	}
+
+	if v.root == nil {
+		stop := v.armDeadline()
+		v.run()
+		stop()
+	} else {
+		v.run()
+	}
+	if v.root == nil {
+		v.stopTasks()
+		if err := v.budget.failure(); err != nil &&
+			(v.err == nil || errors.Is(v.err, ErrVMAborted)) {
+			v.err = err
+		}
+	} else {
+		if v.err == nil {
+			v.syncBudget()
+		}
+		if isBudgetError(v.err) {
+			v.budget.fail(v.err)
+		}
+	}
+	v.resetAbort()
+	if v.err != nil {
+		err = v.trace(v.err)
+		if v.hooks != nil {
+			v.hooks.Error(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (v *VM) trace(err error) error {
+	filePos := v.fileSet.Position(
+		v.curFrame.fn.SourcePos(v.ip - 1))
+	err = fmt.Errorf("Çalışma Hatası: %w\n\tat %s",
+		err, filePos)
+	for v.framesIndex > 1 {
+		if v.hooks != nil {
+			v.hooks.Exit(v.curFrame.fn, filePos)
+		}
+		v.framesIndex--
+		v.curFrame = &v.frames[v.framesIndex-1]
+		filePos = v.fileSet.Position(
+			v.curFrame.fn.SourcePos(v.curFrame.ip - 1))
+		err = fmt.Errorf("%w\n\tat %s", err, filePos)
+	}
+	return err
+}
+
+func (v *VM) global(idx int) Object {
+	if in := v.injected; in != nil && in.lazy[idx] {
+		in.lazy[idx] = false
+		v.globals[idx] = isolate(v.globals[idx], in.copies)
+	}
+	return v.globals[idx]
+}
+
+func (v *VM) Call(fn Object, args ...Object) (Object, error) {
+	if atomic.LoadInt64(&v.aborting) != 0 {
+		return nil, ErrVMAborted
+	}
+	if !fn.CanCall() {
+		return nil, fmt.Errorf("çağrılamaz: %s", fn.TypeName())
+	}
+	cfn, ok := fn.(*CompiledFunction)
+	if !ok {
+		return v.callNative(fn, args)
+	}
+
+	child := v.newChild(v.globals)
+	child.injected = v.injected
+	v.addChild(child)
+	defer v.removeChild(child)
+	child.stack[0] = cfn
+	child.stack[1] = &Array{Value: append([]Object{}, args...)}
+	child.sp = 2
+	arm := !v.started
+	if arm {
+		v.startDeadline()
+		v.started = true
+		v.allocs = v.maxAllocs + 1
+		v.instructions = 0
+		v.allocBytes = 0
+		v.accounting = v.accountsAllocBytes()
+		v.share = budgetShare{}
+		v.budget = &runBudget{root: v}
+		v.refill()
+	}
+	child.allocs = v.allocs
+	child.share = v.share
+	child.allocBytes = v.allocBytes
+	child.deadline = v.deadline
+	child.instructions = v.usedInstructions()
+	child.refill()
+	if child.profiler != nil {
+		child.markProfile()
+	}
+	if v.debugger != nil {
+		child.debugger = v.debugger
+		v.debugger.enter(child, true)
+		defer v.debugger.leave()
+	}
+	if arm {
+		stop := v.armDeadline()
+		child.run()
+		stop()
+		if err := v.budget.failure(); err != nil &&
+			(child.err == nil || errors.Is(child.err, ErrVMAborted)) {
+			child.err = err
+		}
+	} else {
+		child.run()
+	}
+	v.allocs = child.allocs
+	v.share = child.share
+	v.allocBytes = child.allocBytes
+	v.instructions = child.usedInstructions()
+	v.refill()
+	if v.profiler != nil {
+		v.markProfile()
+	}
+	if v.coverage != nil {
+		v.ticks, v.tickBase = 0, 0
+	}
+	atomic.AddInt64(&v.spawnedInstrs, atomic.LoadInt64(&child.spawnedInstrs))
+	atomic.AddInt64(&v.spawnedAllocs, atomic.LoadInt64(&child.spawnedAllocs))
+	atomic.AddInt64(&v.spawnedAllocBytes, atomic.LoadInt64(&child.spawnedAllocBytes))
+	if child.err != nil {
+		err := child.trace(child.err)
+		if v.hooks != nil {
+			v.hooks.Error(err)
+		}
+		return nil, err
+	}
+	if atomic.LoadInt64(&child.aborting) != 0 {
+		return nil, ErrVMAborted
+	}
+	return child.stack[child.sp-1], nil
+}
+
+func (v *VM) callNative(fn Object, args []Object) (Object, error) {
+	switch fn := fn.(type) {
+	case *BuiltinFunction:
+		if fn.Interop != nil {
+			return fn.Interop(v, args...)
+		}
+	case *UserFunction:
+		if fn.Interop != nil {
+			return fn.Interop(v, args...)
+		}
+	}
+	return fn.Call(args...)
+}
+
+func (v *VM) newChild(globals []Object) *VM {
+	child := &VM{
+		constants:       v.constants,
+		globals:         globals,
+		fileSet:         v.fileSet,
+		framesIndex:     1,
+		ip:              -1,
+		maxAllocs:       v.maxAllocs,
+		stdin:           v.stdin,
+		maxInstructions: v.maxInstructions,
+		maxDuration:     v.maxDuration,
+		deadline:        v.deadline,
+		maxAllocBytes:   v.maxAllocBytes,
+		maxStringLen:    v.maxStringLen,
+		maxBytesLen:     v.maxBytesLen,
+		accounting:      v.accounting,
+		stdout:          v.stdout,
+		stderr:          v.stderr,
+		hooks:           v.hooks,
+		profiler:        v.profiler,
+		coverage:        v.coverage,
+		child:           true,
+		root:            v.taskRoot(),
+		budget:          v.budget,
+	}
+	child.frames[0].fn = &CompiledFunction{
+		Instructions: append(
+			MakeInstruction(parser.OpCall, 1, 1), parser.OpSuspend),
+		SourceMap: map[int]parser.Pos{
+			0: v.curFrame.fn.SourcePos(v.ip),
+		},
+	}
+	child.frames[0].ip = -1
+	child.curFrame = &child.frames[0]
+	child.curInsts = child.curFrame.fn.Instructions
+	return child
+}
+
+func (v *VM) taskRoot() *VM {
+	if v.root != nil {
+		return v.root
+	}
+	return v
+}
+
+func (v *VM) addChild(child *VM) {
+	v.childLock.Lock()
+	if v.children == nil {
+		v.children = make(map[*VM]struct{})
+	}
+	v.children[child] = struct{}{}
+	v.childLock.Unlock()
+}
+
+func (v *VM) removeChild(child *VM) {
+	v.childLock.Lock()
+	delete(v.children, child)
+	v.childLock.Unlock()
+}
+
+func (v *VM) run() {
+	if atomic.LoadInt64(&v.aborting) != 0 {
+		return
+	}
+	for {
+		if v.ticks == 0 && !v.checkLimits() {
+			return
+		}
+		v.ticks--
+		v.ip++
+
+		switch v.curInsts[v.ip] {
+		case parser.OpConstant:
+			v.ip += 2
+			cidx := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+
+			v.stack[v.sp] = v.constants[cidx]
+			v.sp++
+		case parser.OpImport:
+			v.ip += 2
+			if v.hooks != nil {
+				cidx := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+				v.hooks.Import(v.constants[cidx].(*String).Value)
+			}
+		case parser.OpNull:
+			v.stack[v.sp] = UndefinedValue
+			v.sp++
+		case parser.OpBinaryOp:
+			v.ip++
+			right := v.stack[v.sp-1]
+			left := v.stack[v.sp-2]
+			tok := token.Token(v.curInsts[v.ip])
+			res, e := left.BinaryOp(tok, right)
+			if e != nil {
+				v.sp -= 2
+				if e == ErrInvalidOperator {
+					v.err = fmt.Errorf("geçersiz operasyon: %s %s %s",
+						left.TypeName(), tok.String(), right.TypeName())
+					return
+				}
+				v.err = e
+				return
+			}
+
+			v.allocs--
+			if v.allocs == 0 {
+				v.err = ErrObjectAllocLimit
+				return
+			}
+			if v.accounting && !v.account(res, left, right) {
+				return
+			}
+
+			v.stack[v.sp-2] = res
+			v.sp--
+		case parser.OpEqual:
+			right := v.stack[v.sp-1]
+			left := v.stack[v.sp-2]
+			v.sp -= 2
+			if left.Equals(right) {
+				v.stack[v.sp] = TrueValue
+			} else {
+				v.stack[v.sp] = FalseValue
+			}
+			v.sp++
+		case parser.OpNotEqual:
+			right := v.stack[v.sp-1]
+			left := v.stack[v.sp-2]
+			v.sp -= 2
+			if left.Equals(right) {
+				v.stack[v.sp] = FalseValue
+			} else {
+				v.stack[v.sp] = TrueValue
+			}
+			v.sp++
+		case parser.OpPop:
+			v.sp--
+		case parser.OpTrue:
+			v.stack[v.sp] = TrueValue
+			v.sp++
+		case parser.OpFalse:
+			v.stack[v.sp] = FalseValue
+			v.sp++
+		case parser.OpLNot:
+			operand := v.stack[v.sp-1]
+			v.sp--
+			if operand.IsFalsy() {
+				v.stack[v.sp] = TrueValue
+			} else {
+				v.stack[v.sp] = FalseValue
+			}
+			v.sp++
+		case parser.OpBComplement:
+			operand := v.stack[v.sp-1]
+			v.sp--
+
+			switch x := operand.(type) {
+			case *Int:
+				var res Object = &Int{Value: ^x.Value}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp] = res
+				v.sp++
+			default:
+				v.err = fmt.Errorf("geçersiz operasyon: ^%s",
+					operand.TypeName())
+				return
+			}
+		case parser.OpMinus:
+			operand := v.stack[v.sp-1]
+			v.sp--
+
+			switch x := operand.(type) {
+			case *Int:
+				var res Object = &Int{Value: -x.Value}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp] = res
+				v.sp++
+			case *Float:
+				var res Object = &Float{Value: -x.Value}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp] = res
+				v.sp++
+			default:
+				v.err = fmt.Errorf("geçersiz operasyon: -%s",
+					operand.TypeName())
+				return
+			}
+		case parser.OpJumpFalsy:
+			v.ip += 2
+			v.sp--
+			if v.stack[v.sp].IsFalsy() {
+				pos := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+				v.ip = pos - 1
+			}
+		case parser.OpAndJump:
+			v.ip += 2
+			if v.stack[v.sp-1].IsFalsy() {
+				pos := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+				v.ip = pos - 1
+			} else {
+				v.sp--
+			}
+		case parser.OpOrJump:
+			v.ip += 2
+			if v.stack[v.sp-1].IsFalsy() {
+				v.sp--
+			} else {
+				pos := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+				v.ip = pos - 1
+			}
+		case parser.OpJump:
+			pos := int(v.curInsts[v.ip+2]) | int(v.curInsts[v.ip+1])<<8
+			v.ip = pos - 1
+		case parser.OpSetGlobal:
+			v.ip += 2
+			v.sp--
+			globalIndex := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+			v.globals[globalIndex] = v.stack[v.sp]
+			if v.injected != nil {
+				v.injected.lazy[globalIndex] = false
+			}
+		case parser.OpSetSelGlobal:
+			v.ip += 3
+			globalIndex := int(v.curInsts[v.ip-1]) | int(v.curInsts[v.ip-2])<<8
+			numSelectors := int(v.curInsts[v.ip])
+
+			selectors := make([]Object, numSelectors)
+			for i := 0; i < numSelectors; i++ {
+				selectors[i] = v.stack[v.sp-numSelectors+i]
+			}
+			val := v.stack[v.sp-numSelectors-1]
+			v.sp -= numSelectors + 1
+			e := v.indexAssign(v.global(globalIndex), val, selectors)
+			if e != nil {
+				v.err = e
+				return
+			}
+		case parser.OpGetGlobal:
+			v.ip += 2
+			globalIndex := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+			v.stack[v.sp] = v.global(globalIndex)
+			v.sp++
+		case parser.OpArray:
+			v.ip += 2
+			numElements := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+
+			var elements []Object
+			for i := v.sp - numElements; i < v.sp; i++ {
+				elements = append(elements, v.stack[i])
+			}
+			v.sp -= numElements
+
+			var arr Object = &Array{Value: elements}
+			v.allocs--
+			if v.allocs == 0 {
+				v.err = ErrObjectAllocLimit
+				return
+			}
+			if v.accounting && !v.account(arr) {
+				return
+			}
+
+			v.stack[v.sp] = arr
+			v.sp++
+		case parser.OpMap:
+			v.ip += 2
+			numElements := int(v.curInsts[v.ip]) | int(v.curInsts[v.ip-1])<<8
+			kv := NewOrderedMap(numElements / 2)
+			for i := v.sp - numElements; i < v.sp; i += 2 {
+				key := v.stack[i]
+				value := v.stack[i+1]
+				if err := kv.Set(key, value); err != nil {
+					v.err = err
+					return
+				}
+			}
+			v.sp -= numElements
+
+			var m Object = &Map{Value: kv}
+			v.allocs--
+			if v.allocs == 0 {
+				v.err = ErrObjectAllocLimit
+				return
+			}
+			if v.accounting && !v.account(m) {
+				return
+			}
+			v.stack[v.sp] = m
+			v.sp++
+		case parser.OpError:
+			value := v.stack[v.sp-1]
+			var e Object = &Error{
+				Value: value,
+			}
+			v.allocs--
+			if v.allocs == 0 {
+				v.err = ErrObjectAllocLimit
+				return
+			}
+			v.stack[v.sp-1] = e
+		case parser.OpImmutable:
+			value := v.stack[v.sp-1]
+			switch value := value.(type) {
+			case *Array:
+				var immutableArray Object = &ImmutableArray{
+					Value: value.Value,
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp-1] = immutableArray
+			case *Map:
+				var immutableMap Object = &ImmutableMap{
+					Value: value.Value,
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp-1] = immutableMap
+			case *Set:
+				var immutableSet Object = &ImmutableSet{
+					Value: value.Value,
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp-1] = immutableSet
+			}
+		case parser.OpIndex:
+			index := v.stack[v.sp-1]
+			left := v.stack[v.sp-2]
+			v.sp -= 2
+
+			val, err := left.IndexGet(index)
+			if err != nil {
+				if err == ErrNotIndexable {
+					v.err = fmt.Errorf("index alınamaz: %s", index.TypeName())
+					return
+				}
+				if err == ErrInvalidIndexType {
+					v.err = fmt.Errorf("geçersiz index tipi: %s",
+						index.TypeName())
+					return
+				}
+				v.err = err
+				return
+			}
+			if val == nil {
+				val = UndefinedValue
+			}
+			v.stack[v.sp] = val
+			v.sp++
+		case parser.OpSliceIndex:
+			high := v.stack[v.sp-1]
+			low := v.stack[v.sp-2]
+			left := v.stack[v.sp-3]
+			v.sp -= 3
+
+			var lowIdx int64
+			if low != UndefinedValue {
+				if lowInt, ok := low.(*Int); ok {
+					lowIdx = lowInt.Value
+				} else {
+					v.err = fmt.Errorf("geçersiz slice index: %s",
+						low.TypeName())
+					return
+				}
+			}
+
+			switch left := left.(type) {
+			case *Array:
+				numElements := int64(len(left.Value))
+				var highIdx int64
+				if high == UndefinedValue {
+					highIdx = numElements
+				} else if highInt, ok := high.(*Int); ok {
+					highIdx = highInt.Value
+				} else {
+					v.err = fmt.Errorf("geçersiz slice index: %s",
+						high.TypeName())
+					return
+				}
+				if lowIdx > highIdx {
+					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
+						lowIdx, highIdx)
+					return
+				}
+				if lowIdx < 0 {
+					lowIdx = 0
+				} else if lowIdx > numElements {
+					lowIdx = numElements
+				}
+				if highIdx < 0 {
+					highIdx = 0
+				} else if highIdx > numElements {
+					highIdx = numElements
+				}
+				var val Object = &Array{
+					Value: left.Value[lowIdx:highIdx],
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp] = val
+				v.sp++
+			case *ImmutableArray:
+				numElements := int64(len(left.Value))
+				var highIdx int64
+				if high == UndefinedValue {
+					highIdx = numElements
+				} else if highInt, ok := high.(*Int); ok {
+					highIdx = highInt.Value
+				} else {
+					v.err = fmt.Errorf("geçersiz slice index: %s",
+						high.TypeName())
+					return
+				}
+				if lowIdx > highIdx {
+					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
+						lowIdx, highIdx)
+					return
+				}
+				if lowIdx < 0 {
+					lowIdx = 0
+				} else if lowIdx > numElements {
+					lowIdx = numElements
+				}
+				if highIdx < 0 {
+					highIdx = 0
+				} else if highIdx > numElements {
+					highIdx = numElements
+				}
+				var val Object = &Array{
+					Value: left.Value[lowIdx:highIdx],
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp] = val
+				v.sp++
+			case *String:
+				numElements := int64(len(left.Value))
+				var highIdx int64
+				if high == UndefinedValue {
+					highIdx = numElements
+				} else if highInt, ok := high.(*Int); ok {
+					highIdx = highInt.Value
+				} else {
+					v.err = fmt.Errorf("geçersiz slice index: %s",
+						high.TypeName())
+					return
+				}
+				if lowIdx > highIdx {
+					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
+						lowIdx, highIdx)
+					return
+				}
+				if lowIdx < 0 {
+					lowIdx = 0
+				} else if lowIdx > numElements {
+					lowIdx = numElements
+				}
+				if highIdx < 0 {
+					highIdx = 0
+				} else if highIdx > numElements {
+					highIdx = numElements
+				}
+				var val Object = &String{
+					Value: left.Value[lowIdx:highIdx],
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp] = val
+				v.sp++
+			case *Bytes:
+				numElements := int64(len(left.Value))
+				var highIdx int64
+				if high == UndefinedValue {
+					highIdx = numElements
+				} else if highInt, ok := high.(*Int); ok {
+					highIdx = highInt.Value
+				} else {
+					v.err = fmt.Errorf("geçersiz slice index: %s",
+						high.TypeName())
+					return
+				}
+				if lowIdx > highIdx {
+					v.err = fmt.Errorf("geçersiz slice index: %d > %d",
+						lowIdx, highIdx)
+					return
+				}
+				if lowIdx < 0 {
+					lowIdx = 0
+				} else if lowIdx > numElements {
+					lowIdx = numElements
+				}
+				if highIdx < 0 {
+					highIdx = 0
+				} else if highIdx > numElements {
+					highIdx = numElements
+				}
+				var val Object = &Bytes{
+					Value: left.Value[lowIdx:highIdx],
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				v.stack[v.sp] = val
+				v.sp++
+			}
+		case parser.OpCall:
+			numArgs := int(v.curInsts[v.ip+1])
+			spread := int(v.curInsts[v.ip+2])
+			v.ip += 2
+
+			value := v.stack[v.sp-1-numArgs]
+			if !value.CanCall() {
+				v.err = fmt.Errorf("çağrılamaz: %s", value.TypeName())
+				return
+			}
+
+			if spread == 1 {
+				v.sp--
+				switch arr := v.stack[v.sp].(type) {
+				case *Array:
+					for _, item := range arr.Value {
+						v.stack[v.sp] = item
+						v.sp++
+					}
+					numArgs += len(arr.Value) - 1
+				case *ImmutableArray:
+					for _, item := range arr.Value {
+						v.stack[v.sp] = item
+						v.sp++
+					}
+					numArgs += len(arr.Value) - 1
+				default:
+					v.err = fmt.Errorf("array değil: %s", arr.TypeName())
+					return
+				}
+			}
+
+			if callee, ok := value.(*CompiledFunction); ok {
+				if callee.VarArgs {
+
+					realArgs := callee.NumParameters - 1
+					varArgs := numArgs - realArgs
+					if varArgs >= 0 {
+						numArgs = realArgs + 1
+						args := make([]Object, varArgs)
+						spStart := v.sp - varArgs
+						for i := spStart; i < v.sp; i++ {
+							args[i-spStart] = v.stack[i]
+						}
+						v.stack[spStart] = &Array{Value: args}
+						v.sp = spStart + 1
+					}
+				}
+				if numArgs != callee.NumParameters {
+					if callee.VarArgs {
+						v.err = fmt.Errorf(
+							"yanlış argüman sayısı: want>=%d, got=%d",
+							callee.NumParameters-1, numArgs)
+					} else {
+						v.err = fmt.Errorf(
+							"yanlış argüman sayısı: want=%d, got=%d",
+							callee.NumParameters, numArgs)
+					}
+					return
+				}
+
+				if callee == v.curFrame.fn {
+					nextOp := v.curInsts[v.ip+1]
+					if nextOp == parser.OpReturn ||
+						(nextOp == parser.OpPop &&
+							parser.OpReturn == v.curInsts[v.ip+2]) {
+						if v.hooks != nil {
+							pos := v.pos()
+							v.hooks.Exit(callee, pos)
+							v.hooks.Enter(callee, pos)
+						}
+						for p := 0; p < numArgs; p++ {
+							v.stack[v.curFrame.basePointer+p] =
+								v.stack[v.sp-numArgs+p]
+						}
+						v.sp -= numArgs + 1
+						v.ip = -1
+						continue
+					}
+				}
+				if v.framesIndex >= MaxFrames {
+					v.err = ErrStackOverflow
+					return
+				}
+				if v.hooks != nil {
+					v.hooks.Enter(callee, v.pos())
+				}
+
+				v.curFrame.ip = v.ip
+				v.curFrame = &(v.frames[v.framesIndex])
+				v.curFrame.fn = callee
+				v.curFrame.freeVars = callee.Free
+				v.curFrame.basePointer = v.sp - numArgs
+				v.curInsts = callee.Instructions
+				v.ip = -1
+				v.framesIndex++
+				v.sp = v.sp - numArgs + callee.NumLocals
+			} else {
+				var args []Object
+				args = append(args, v.stack[v.sp-numArgs:v.sp]...)
+				if v.hooks != nil {
+					v.hooks.Call(value, args, v.pos())
+				}
+				var receiver Object
+				if fn, ok := value.(*BuiltinFunction); ok {
+					receiver = fn.receiver
+				}
+				if v.accounting {
+					v.callSizes = v.callSizes[:0]
+					for _, arg := range args {
+						v.callSizes = append(v.callSizes, sizeOf(arg))
+					}
+					if receiver != nil {
+						v.callSizes = append(v.callSizes, sizeOf(receiver))
+					}
+				}
+				ret, e := v.callNative(value, args)
+				v.sp -= numArgs + 1
+				if v.profiler != nil {
+					v.profiler.sample(v)
+				}
+
+				if e != nil {
+					if e == ErrWrongNumArguments {
+						v.err = fmt.Errorf(
+							"yanlış argüman sayısı '%s'",
+							value.TypeName())
+						return
+					}
+					if e, ok := e.(ErrInvalidArgumentType); ok {
+						v.err = fmt.Errorf(
+							"geçersiz argüman tipi for '%s' in call to '%s': "+
+								"expected %s, found %s",
+							e.Name, value.TypeName(), e.Expected, e.Found)
+						return
+					}
+					v.err = e
+					return
+				}
+
+				if ret == nil {
+					ret = UndefinedValue
+				}
+				v.allocs--
+				if v.allocs == 0 {
+					v.err = ErrObjectAllocLimit
+					return
+				}
+				if v.accounting && !v.accountCall(ret, args, receiver) {
+					return
+				}
+				v.stack[v.sp] = ret
+				v.sp++
+			}
+		case parser.OpReturn:
+			v.ip++
+			var retVal Object
+			if int(v.curInsts[v.ip]) == 1 {
+				retVal = v.stack[v.sp-1]
+			} else {
+				retVal = UndefinedValue
+			}
+			if v.hooks != nil {
+				v.hooks.Exit(v.curFrame.fn, v.pos())
+			}
+			//v.sp--
+			v.framesIndex--
+			v.curFrame = &v.frames[v.framesIndex-1]
+			v.curInsts = v.curFrame.fn.Instructions
+			v.ip = v.curFrame.ip
+			//v.sp = lastFrame.basePointer - 1
+			v.sp = v.frames[v.framesIndex].basePointer
+
+			v.stack[v.sp-1] = retVal
+			//v.sp++
+		case parser.OpDefineLocal:
+			v.ip++
+			localIndex := int(v.curInsts[v.ip])
+			sp := v.curFrame.basePointer + localIndex
+
+			val := v.stack[v.sp-1]
+			v.sp--
+			v.stack[sp] = val
+		case parser.OpSetLocal:
+			localIndex := int(v.curInsts[v.ip+1])
+			v.ip++
+			sp := v.curFrame.basePointer + localIndex
+
+			val := v.stack[v.sp-1]
+			v.sp--
+			if obj, ok := v.stack[sp].(*ObjectPtr); ok {
+				*obj.Value = val
+				val = obj
+			}
+			v.stack[sp] = val
+		case parser.OpSetSelLocal:
+			localIndex := int(v.curInsts[v.ip+1])
+			numSelectors := int(v.curInsts[v.ip+2])
+			v.ip += 2
+
+			selectors := make([]Object, numSelectors)
+			for i := 0; i < numSelectors; i++ {
+				selectors[i] = v.stack[v.sp-numSelectors+i]
+			}
+			val := v.stack[v.sp-numSelectors-1]
+			v.sp -= numSelectors + 1
+			dst := v.stack[v.curFrame.basePointer+localIndex]
+			if obj, ok := dst.(*ObjectPtr); ok {
+				dst = *obj.Value
+			}
+			if e := v.indexAssign(dst, val, selectors); e != nil {
+				v.err = e
+				return
+			}
+		case parser.OpGetLocal:
+			v.ip++
+			localIndex := int(v.curInsts[v.ip])
+			val := v.stack[v.curFrame.basePointer+localIndex]
+			if obj, ok := val.(*ObjectPtr); ok {
+				val = *obj.Value
+			}
+			v.stack[v.sp] = val
+			v.sp++
+		case parser.OpGetBuiltin:
+			v.ip++
+			builtinIndex := int(v.curInsts[v.ip])
+			v.stack[v.sp] = builtinFuncs[builtinIndex]
+			v.sp++
+		case parser.OpClosure:
+			v.ip += 3
+			constIndex := int(v.curInsts[v.ip-1]) | int(v.curInsts[v.ip-2])<<8
+			numFree := int(v.curInsts[v.ip])
+			fn, ok := v.constants[constIndex].(*CompiledFunction)
+			if !ok {
+				v.err = fmt.Errorf("fonksiyon değil: %s", fn.TypeName())
+				return
+			}
+			free := make([]*ObjectPtr, numFree)
+			for i := 0; i < numFree; i++ {
+				switch freeVar := (v.stack[v.sp-numFree+i]).(type) {
+				case *ObjectPtr:
+					free[i] = freeVar
+				default:
+					free[i] = &ObjectPtr{
+						Value: &v.stack[v.sp-numFree+i],
+					}
+				}
+			}
+			v.sp -= numFree
+			cl := &CompiledFunction{
+				Instructions:  fn.Instructions,
+				NumLocals:     fn.NumLocals,
+				NumParameters: fn.NumParameters,
+				VarArgs:       fn.VarArgs,
+				SourceMap:     fn.SourceMap,
+				Free:          free,
+				Debug:         fn.Debug,
+			}
+			v.allocs--
+			if v.allocs == 0 {
+				v.err = ErrObjectAllocLimit
+				return
+			}
+			v.stack[v.sp] = cl
+			v.sp++
+		case parser.OpGetFreePtr:
+			v.ip++
+			freeIndex := int(v.curInsts[v.ip])
+			val := v.curFrame.freeVars[freeIndex]
+			v.stack[v.sp] = val
+			v.sp++
+		case parser.OpGetFree:
+			v.ip++
+			freeIndex := int(v.curInsts[v.ip])
+			val := *v.curFrame.freeVars[freeIndex].Value
+			v.stack[v.sp] = val
+			v.sp++
+		case parser.OpSetFree:
+			v.ip++
+			freeIndex := int(v.curInsts[v.ip])
+			*v.curFrame.freeVars[freeIndex].Value = v.stack[v.sp-1]
+			v.sp--
+		case parser.OpGetLocalPtr:
+			v.ip++
+			localIndex := int(v.curInsts[v.ip])
+			sp := v.curFrame.basePointer + localIndex
+			val := v.stack[sp]
+			var freeVar *ObjectPtr
+			if obj, ok := val.(*ObjectPtr); ok {
+				freeVar = obj
+			} else {
+				freeVar = &ObjectPtr{Value: &val}
+				v.stack[sp] = freeVar
+			}
+			v.stack[v.sp] = freeVar
+			v.sp++
+		case parser.OpSetSelFree:
+			v.ip += 2
+			freeIndex := int(v.curInsts[v.ip-1])
+			numSelectors := int(v.curInsts[v.ip])
+
+			selectors := make([]Object, numSelectors)
+			for i := 0; i < numSelectors; i++ {
+				selectors[i] = v.stack[v.sp-numSelectors+i]
+			}
+			val := v.stack[v.sp-numSelectors-1]
+			v.sp -= numSelectors + 1
+			e := v.indexAssign(*v.curFrame.freeVars[freeIndex].Value,
+				val, selectors)
+			if e != nil {
+				v.err = e
+				return
+			}
+		case parser.OpIteratorInit:
+			var iterator Object
+			dst := v.stack[v.sp-1]
+			v.sp--
+			if !dst.CanIterate() {
+				v.err = fmt.Errorf("sayılamaz: %s", dst.TypeName())
+				return
+			}
+			iterator = dst.Iterate()
+			if it, ok := iterator.(*ChannelIterator); ok {
+				it.done = v.aborted()
+			}
+			v.allocs--
+			if v.allocs == 0 {
+				v.err = ErrObjectAllocLimit
+				return
+			}
+			v.stack[v.sp] = iterator
+			v.sp++
+		case parser.OpIteratorNext:
+			iterator := v.stack[v.sp-1]
+			v.sp--
+			hasMore := iterator.(Iterator).Next()
+			if hasMore {
+				v.stack[v.sp] = TrueValue
+			} else {
+				v.stack[v.sp] = FalseValue
+			}
+			v.sp++
+		case parser.OpIteratorKey:
+			iterator := v.stack[v.sp-1]
+			v.sp--
+			val := iterator.(Iterator).Key()
+			v.stack[v.sp] = val
+			v.sp++
+		case parser.OpIteratorValue:
+			iterator := v.stack[v.sp-1]
+			v.sp--
+			val := iterator.(Iterator).Value()
+			v.stack[v.sp] = val
+			v.sp++
+		case parser.OpSpawn:
+			numArgs := int(v.curInsts[v.ip+1])
+			spread := int(v.curInsts[v.ip+2])
+			v.ip += 2
+
+			value := v.stack[v.sp-1-numArgs]
+			if !value.CanCall() {
+				v.err = fmt.Errorf("çağrılamaz: %s", value.TypeName())
+				return
+			}
+			args := append([]Object{}, v.stack[v.sp-numArgs:v.sp]...)
+			v.sp -= numArgs + 1
+
+			if spread == 1 {
+				last := args[len(args)-1]
+				args = args[:len(args)-1]
+				switch arr := last.(type) {
+				case *Array:
+					args = append(args, arr.Value...)
+				case *ImmutableArray:
+					args = append(args, arr.Value...)
+				default:
+					v.err = fmt.Errorf("array değil: %s", arr.TypeName())
+					return
+				}
+			}
+
+			var task Object = v.spawn(value, args)
+			v.allocs--
+			if v.allocs == 0 {
+				v.err = ErrObjectAllocLimit
+				return
+			}
+			v.stack[v.sp] = task
+			v.sp++
+		case parser.OpSuspend:
+			return
+		default:
+			v.err = fmt.Errorf("bilinmeyen opcode: %d", v.curInsts[v.ip])
+			return
+		}
+	}
+}
+
+func (v *VM) IsStackEmpty() bool {
+	return v.sp == 0
+}
+
+func (v *VM) indexAssign(dst, src Object, selectors []Object) error {
+	numSel := len(selectors)
+	for sidx := numSel - 1; sidx > 0; sidx-- {
+		next, err := dst.IndexGet(selectors[sidx])
+		if err != nil {
+			if err == ErrNotIndexable {
+				return fmt.Errorf("index alınamaz: %s", dst.TypeName())
+			}
+			if err == ErrInvalidIndexType {
+				return fmt.Errorf("geçersiz index tipi: %s",
+					selectors[sidx].TypeName())
+			}
+			return err
+		}
+		dst = next
+	}
+
+	var size int64
+	if v.accounting {
+		size = sizeOf(dst)
+	}
+	if err := dst.IndexSet(selectors[0], src); err != nil {
+		if err == ErrNotIndexAssignable {
+			return fmt.Errorf("geçersiz: %s", dst.TypeName())
+		}
+		if err == ErrInvalidIndexValueType {
+			return fmt.Errorf("geçersiz: %s", src.TypeName())
+		}
+		return err
+	}
+	if v.accounting {
+		if grown := sizeOf(dst) - size; grown > 0 && !v.addAllocBytes(grown) {
+			return v.err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,475 @@
+package lokum
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	objectType = reflect.TypeOf((*Object)(nil)).Elem()
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+	timeType   = reflect.TypeOf(time.Time{})
+	bytesType  = reflect.TypeOf([]byte(nil))
+)
+
+type structInfo struct {
+	names []string
+	index map[string][]int
+}
+
+var structCache sync.Map
+
+func structFields(t reflect.Type) *structInfo {
+	if info, ok := structCache.Load(t); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{index: make(map[string][]int)}
+	collectFields(t, nil, info)
+	structCache.Store(t, info)
+	return info
+}
+
+func collectFields(t reflect.Type, parent []int, info *structInfo) {
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("lokum"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag != "" {
+			name = tag
+		}
+		if _, ok := info.index[name]; ok {
+			continue
+		}
+		info.index[name] = append(append([]int{}, parent...), i)
+		info.names = append(info.names, name)
+	}
+	for _, f := range embedded {
+		collectFields(f.Type, append(append([]int{}, parent...), f.Index...), info)
+	}
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) &&
+		!v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func fromValue(v reflect.Value) (Object, error) {
+	if !v.IsValid() {
+		return UndefinedValue, nil
+	}
+	if v.CanInterface() {
+		if o, ok := v.Interface().(Object); ok {
+			return o, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return TrueValue, nil
+		}
+		return FalseValue, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return &Int{Value: v.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return &Int{Value: int64(v.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Float{Value: v.Float()}, nil
+	case reflect.String:
+		return FromInterface(v.String())
+	case reflect.Interface:
+		if v.IsNil() {
+			return UndefinedValue, nil
+		}
+		if v.Type() == errorType {
+			return FromInterface(v.Interface())
+		}
+		return fromValue(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return UndefinedValue, nil
+		}
+		if v.Elem().Kind() == reflect.Struct && v.Type().Elem() != timeType {
+			return &GoValue{Value: v}, nil
+		}
+		return fromValue(v.Elem())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return FromInterface(v.Convert(bytesType).Interface())
+		}
+		return &GoValue{Value: v}, nil
+	case reflect.Map:
+		return &GoValue{Value: v}, nil
+	case reflect.Struct, reflect.Array:
+		if v.Type() == timeType {
+			return &Time{Value: v.Interface().(time.Time)}, nil
+		}
+		if !v.CanAddr() {
+			c := reflect.New(v.Type()).Elem()
+			c.Set(v)
+			v = c
+		}
+		return &GoValue{Value: v}, nil
+	case reflect.Func:
+		if v.IsNil() {
+			return UndefinedValue, nil
+		}
+		return reflectFunc("", v), nil
+	}
+	return nil, fmt.Errorf("cannot convert to object: %s", v.Type())
+}
+
+func toValue(o Object, t reflect.Type) (reflect.Value, bool) {
+	if g, ok := o.(*GoValue); ok {
+		v := g.Value
+		switch {
+		case v.Type().AssignableTo(t):
+			return v, true
+		case v.Kind() == reflect.Ptr && v.Type().Elem().AssignableTo(t):
+			return v.Elem(), true
+		case v.CanAddr() && v.Addr().Type().AssignableTo(t):
+			return v.Addr(), true
+		}
+		return reflect.Value{}, false
+	}
+	if t == objectType {
+		return reflect.ValueOf(&o).Elem(), true
+	}
+
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() > 0 && reflect.TypeOf(o).Implements(t) {
+			v.Set(reflect.ValueOf(o))
+			return v, true
+		}
+		i := ToInterface(o)
+		if i == nil {
+			return v, true
+		}
+		if reflect.TypeOf(i).AssignableTo(t) {
+			v.Set(reflect.ValueOf(i))
+			return v, true
+		}
+	case reflect.Bool:
+		b, ok := ToBool(o)
+		v.SetBool(b)
+		return v, ok
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		n, ok := ToInt64(o)
+		if ok && !v.OverflowInt(n) {
+			v.SetInt(n)
+			return v, true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		n, ok := ToInt64(o)
+		if ok && n >= 0 && !v.OverflowUint(uint64(n)) {
+			v.SetUint(uint64(n))
+			return v, true
+		}
+	case reflect.Float32, reflect.Float64:
+		f, ok := ToFloat64(o)
+		v.SetFloat(f)
+		return v, ok
+	case reflect.String:
+		s, ok := ToString(o)
+		v.SetString(s)
+		return v, ok
+	case reflect.Slice:
+		if o == UndefinedValue {
+			return v, true
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			b, ok := ToByteSlice(o)
+			if ok {
+				v.Set(reflect.ValueOf(b).Convert(t))
+			}
+			return v, ok
+		}
+		arr, ok := arrayValues(o)
+		if !ok {
+			break
+		}
+		v.Set(reflect.MakeSlice(t, len(arr), len(arr)))
+		for i, e := range arr {
+			ev, ok := toValue(e, t.Elem())
+			if !ok {
+				return reflect.Value{}, false
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, true
+	case reflect.Array:
+		arr, ok := arrayValues(o)
+		if !ok || len(arr) != t.Len() {
+			break
+		}
+		for i, e := range arr {
+			ev, ok := toValue(e, t.Elem())
+			if !ok {
+				return reflect.Value{}, false
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, true
+	case reflect.Map:
+		if o == UndefinedValue {
+			return v, true
+		}
+		m, ok := mapValues(o)
+		if !ok {
+			break
+		}
+		v.Set(reflect.MakeMapWithSize(t, m.Len()))
+		m.Range(func(key, value Object) bool {
+			var kv, ev reflect.Value
+			if kv, ok = toValue(key, t.Key()); !ok {
+				return false
+			}
+			if ev, ok = toValue(value, t.Elem()); !ok {
+				return false
+			}
+			v.SetMapIndex(kv, ev)
+			return true
+		})
+		return v, ok
+	case reflect.Struct:
+		if t == timeType {
+			tm, ok := ToTime(o)
+			if ok {
+				v.Set(reflect.ValueOf(tm))
+			}
+			return v, ok
+		}
+		m, ok := mapValues(o)
+		if !ok {
+			break
+		}
+		info := structFields(t)
+		for _, name := range info.names {
+			e, found := m.GetString(name)
+			if !found {
+				continue
+			}
+			f := v.FieldByIndex(info.index[name])
+			ev, ok := toValue(e, f.Type())
+			if !ok {
+				return reflect.Value{}, false
+			}
+			f.Set(ev)
+		}
+		return v, true
+	case reflect.Ptr:
+		if o == UndefinedValue {
+			return v, true
+		}
+		ev, ok := toValue(o, t.Elem())
+		if !ok {
+			break
+		}
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(ev)
+		return v, true
+	case reflect.Func:
+		return funcValue(nil, o, t)
+	}
+	return reflect.Value{}, false
+}
+
+func arrayValues(o Object) ([]Object, bool) {
+	switch o := o.(type) {
+	case *Array:
+		return o.Value, true
+	case *ImmutableArray:
+		return o.Value, true
+	}
+	return nil, false
+}
+
+func mapValues(o Object) (*OrderedMap, bool) {
+	switch o := o.(type) {
+	case *Map:
+		return o.Value, o.Value != nil
+	case *ImmutableMap:
+		return o.Value, o.Value != nil
+	}
+	return nil, false
+}
+
+// funcValue, betik fonksiyonunu t tipinde bir Go fonksiyonuna sarar. t'nin
+// son sonucu error ise betik hataları orada döner; değilse sarılmış
+// fonksiyon hatayla panikler. Fonksiyon saklanıp betik bittikten sonra
+// çağrılırsa bu panik çağıranın goroutine'inde olur.
+func funcValue(vm Interop, o Object, t reflect.Type) (reflect.Value, bool) {
+	if o == UndefinedValue {
+		return reflect.Zero(t), true
+	}
+	if !o.CanCall() {
+		return reflect.Value{}, false
+	}
+	call := o.Call
+	if vm != nil {
+		call = func(args ...Object) (Object, error) {
+			return vm.Call(o, args...)
+		}
+	}
+	return reflect.MakeFunc(t, func(in []reflect.Value) []reflect.Value {
+		args := make([]Object, len(in))
+		for i, a := range in {
+			arg, err := fromValue(a)
+			if err != nil {
+				return funcResults(t, nil, err)
+			}
+			args[i] = arg
+		}
+		ret, err := call(args...)
+		return funcResults(t, ret, err)
+	}), true
+}
+
+func funcResults(t reflect.Type, ret Object, err error) []reflect.Value {
+	out := make([]reflect.Value, t.NumOut())
+	for i := range out {
+		out[i] = reflect.Zero(t.Out(i))
+	}
+	n := len(out)
+	if n > 0 && t.Out(n-1) == errorType {
+		n--
+		if e, ok := ret.(*Error); ok && err == nil {
+			err, ret = errors.New(e.String()), UndefinedValue
+		}
+		if err != nil {
+			out[n] = reflect.ValueOf(&err).Elem()
+			return out
+		}
+	}
+	if err != nil {
+		panic(err)
+	}
+	values := []Object{ret}
+	if n > 1 {
+		values, _ = arrayValues(ret)
+	}
+	for i := 0; i < n && i < len(values); i++ {
+		if v, ok := toValue(values[i], t.Out(i)); ok {
+			out[i] = v
+		}
+	}
+	return out
+}
+
+func reflectFunc(name string, fn reflect.Value) *UserFunction {
+	return &UserFunction{
+		Name: name,
+		Value: func(args ...Object) (Object, error) {
+			return callReflect(nil, fn, args)
+		},
+		Interop: func(vm Interop, args ...Object) (Object, error) {
+			return callReflect(vm, fn, args)
+		},
+	}
+}
+
+func callReflect(
+	vm Interop,
+	fn reflect.Value,
+	args []Object,
+) (ret Object, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+				return
+			}
+			err = fmt.Errorf("panik: %v", r)
+		}
+	}()
+
+	t := fn.Type()
+	numIn := t.NumIn()
+	if t.IsVariadic() {
+		if len(args) < numIn-1 {
+			return nil, ErrWrongNumArguments
+		}
+	} else if len(args) != numIn {
+		return nil, ErrWrongNumArguments
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		var pt reflect.Type
+		if t.IsVariadic() && i >= numIn-1 {
+			pt = t.In(numIn - 1).Elem()
+		} else {
+			pt = t.In(i)
+		}
+		var v reflect.Value
+		var ok bool
+		if pt.Kind() == reflect.Func {
+			v, ok = funcValue(vm, arg, pt)
+		} else {
+			v, ok = toValue(arg, pt)
+		}
+		if !ok {
+			return nil, ErrInvalidArgumentType{
+				Name:     argName(i),
+				Expected: pt.String(),
+				Found:    arg.TypeName(),
+			}
+		}
+		in[i] = v
+	}
+
+	out := fn.Call(in)
+	if n := len(out); n > 0 && t.Out(n-1) == errorType {
+		if e := out[n-1]; !e.IsNil() {
+			return FromInterface(e.Interface())
+		}
+		out = out[:n-1]
+	}
+	switch len(out) {
+	case 0:
+		return UndefinedValue, nil
+	case 1:
+		return fromValue(out[0])
+	}
+	arr := make([]Object, len(out))
+	for i, v := range out {
+		if arr[i], err = fromValue(v); err != nil {
+			return nil, err
+		}
+	}
+	return &Array{Value: arr}, nil
+}
+
+var argNames = []string{"first", "second", "third", "fourth", "fifth"}
+
+func argName(i int) string {
+	if i < len(argNames) {
+		return argNames[i]
+	}
+	return strconv.Itoa(i + 1)
+}
@@ -0,0 +1,93 @@
+package lokum
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToValueEmptyInterface(t *testing.T) {
+	var got []interface{}
+	s := NewScript([]byte(`
+al(1, "a", [2, 3.5], {x: doğru})
+al_liste([4, "b"])
+al_harita({y: 5})
+`))
+	add := func(name string, fn interface{}) {
+		if err := s.Add(name, fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("al", func(xs ...interface{}) { got = append(got, xs...) })
+	add("al_liste", func(xs []interface{}) { got = append(got, xs) })
+	add("al_harita", func(m map[string]interface{}) { got = append(got, m) })
+	c, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Run(); err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{
+		int64(1), "a",
+		[]interface{}{int64(2), 3.5},
+		map[string]interface{}{"x": true},
+		[]interface{}{int64(4), "b"},
+		map[string]interface{}{"y": int64(5)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%#v, beklenen %#v", got, want)
+	}
+}
+
+func TestToValueObjectInterface(t *testing.T) {
+	v, ok := toValue(&Int{Value: 3}, reflect.TypeOf((*Object)(nil)).Elem())
+	if !ok || v.Interface().(*Int).Value != 3 {
+		t.Fatalf("%v %v", v, ok)
+	}
+	v, ok = toValue(&Int{Value: 3}, reflect.TypeOf((*interface {
+		TypeName() string
+	})(nil)).Elem())
+	if !ok {
+		t.Fatal("arayüz dönüştürülemedi")
+	}
+	if _, isInt := v.Interface().(*Int); !isInt {
+		t.Fatalf("%T", v.Interface())
+	}
+}
+
+func TestFuncValueStoredCallback(t *testing.T) {
+	var double, bad func(int) (int, error)
+	var badNoErr func(int) int
+	var badArg func(chan int) error
+	s := NewScript([]byte(`
+hatalı := fn(x) { dön x + "a" }
+kaydet(fn(x) { dön x * 2 }, hatalı, hatalı, fn(k) {})
+`))
+	err := s.Add("kaydet", func(a, b func(int) (int, error), c func(int) int,
+		d func(chan int) error) {
+		double, bad, badNoErr, badArg = a, b, c, d
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := double(3); err != nil || n != 6 {
+		t.Fatalf("%d %v, beklenen 6", n, err)
+	}
+	if n, err := bad(3); err == nil || n != 0 {
+		t.Fatalf("%d %v, beklenen hata", n, err)
+	}
+	if err := badArg(make(chan int)); err == nil {
+		t.Fatal("dönüştürülemeyen argüman hatası dönmedi")
+	}
+
+	defer func() {
+		if _, ok := recover().(error); !ok {
+			t.Fatal("hata dönüşü olmayan fonksiyon hatayla paniklemedi")
+		}
+	}()
+	badNoErr(3)
+}
@@ -0,0 +1,107 @@
+package lokum
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const ImportPathEnv = "LOKUMPATH"
+
+func (c *Compiler) SetImportFS(fsys fs.FS) {
+	c.importFS = fsys
+}
+
+func (c *Compiler) SetImportPaths(paths ...string) {
+	c.importPaths = paths
+}
+
+func (c *Compiler) searchDirs(moduleName string) []string {
+	dirs := []string{c.importDir}
+	if strings.HasPrefix(moduleName, "./") ||
+		strings.HasPrefix(moduleName, "../") {
+		return dirs
+	}
+	dirs = append(dirs, c.importPaths...)
+	if c.importFS == nil {
+		for _, dir := range filepath.SplitList(os.Getenv(ImportPathEnv)) {
+			if dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+func (c *Compiler) getPathModule(moduleName string) (string, error) {
+	var tried []string
+	for _, dir := range c.searchDirs(moduleName) {
+		for _, ext := range c.importFileExt {
+			nameFile := moduleName
+			if !strings.HasSuffix(nameFile, ext) {
+				nameFile += ext
+			}
+
+			pathFile, err := c.joinPath(dir, nameFile)
+			if err != nil {
+				continue
+			}
+			tried = append(tried, pathFile)
+			if c.moduleExists(pathFile) {
+				return pathFile, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("modül '%s' bulunamadı, aranan yerler: %s",
+		moduleName, strings.Join(tried, ", "))
+}
+
+func (c *Compiler) joinPath(dir, name string) (string, error) {
+	if c.importFS != nil {
+		p := path.Join(dir, name)
+		if !fs.ValidPath(p) {
+			return "", fs.ErrInvalid
+		}
+		return p, nil
+	}
+	return filepath.Abs(filepath.Join(dir, filepath.FromSlash(name)))
+}
+
+func (c *Compiler) moduleExists(p string) bool {
+	var err error
+	if c.importFS != nil {
+		_, err = fs.Stat(c.importFS, p)
+	} else {
+		_, err = os.Stat(p)
+	}
+	return !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fs.ErrInvalid)
+}
+
+func (c *Compiler) readModule(p string) ([]byte, error) {
+	if c.importFS != nil {
+		return fs.ReadFile(c.importFS, p)
+	}
+	return os.ReadFile(p)
+}
+
+func (c *Compiler) moduleDir(p string) string {
+	if c.importFS != nil {
+		return path.Dir(p)
+	}
+	return filepath.Dir(p)
+}
+
+func (c *Compiler) importChain() []string {
+	var chain []string
+	if c.parent != nil {
+		chain = c.parent.importChain()
+	}
+	if c.modulePath == "" {
+		return append(chain, c.file.Name)
+	}
+	return append(chain, c.modulePath)
+}
@@ -0,0 +1,165 @@
+package lokum
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func importScript(src string, fsys fs.FS, paths ...string) *Script {
+	s := NewScript([]byte(src))
+	s.EnableFileImport(true)
+	s.SetImportPaths(paths...)
+	if fsys != nil {
+		s.SetImportFS(fsys)
+	}
+	return s
+}
+
+func moduleFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, src := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(src)}
+	}
+	return fsys
+}
+
+func TestImportSearchOrder(t *testing.T) {
+	fsys := moduleFS(map[string]string{
+		"yerel.lokum":       `paylaş "kök"`,
+		"lib1/a.lokum":      `paylaş "lib1"`,
+		"lib1/yerel.lokum":  `paylaş "lib1"`,
+		"lib2/a.lokum":      `paylaş "lib2"`,
+		"lib2/b.lokum":      `paylaş kullan("./alt/c")`,
+		"lib2/alt/c.lokum":  `paylaş "lib2/alt"`,
+		"lib2/göreli.lokum": `paylaş "lib2"`,
+	})
+	s := importScript(`
+a := kullan("a")
+b := kullan("b")
+yerel := kullan("yerel")
+`, fsys, "lib1", "lib2")
+	c, err := s.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"a":     "lib1",
+		"b":     "lib2/alt",
+		"yerel": "kök",
+	} {
+		if got := c.Get(name).String(); got != want {
+			t.Fatalf("%s = %q, beklenen %q", name, got, want)
+		}
+	}
+
+	s = importScript(`x := kullan("./göreli")`, fsys, "lib2")
+	_, err = s.Compile()
+	if err == nil || !strings.Contains(err.Error(),
+		"modül './göreli' bulunamadı, aranan yerler: göreli.lokum") {
+		t.Fatalf("göreli import arama yollarına bakmamalı: %v", err)
+	}
+}
+
+func TestImportNotFound(t *testing.T) {
+	s := importScript(`x := kullan("yok")`, fstest.MapFS{}, "lib1", "lib2")
+	_, err := s.Compile()
+	want := "modül 'yok' bulunamadı, aranan yerler: " +
+		"yok.lokum, lib1/yok.lokum, lib2/yok.lokum"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("%v, beklenen %q", err, want)
+	}
+}
+
+func TestImportPathEnv(t *testing.T) {
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	write := func(dir, name, src string) {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(dir1, "a.lokum", `paylaş "dir1"`)
+	write(dir2, "a.lokum", `paylaş "dir2"`)
+	write(dir2, "b.lokum", `paylaş "dir2"`)
+
+	old, had := os.LookupEnv(ImportPathEnv)
+	defer func() {
+		if had {
+			os.Setenv(ImportPathEnv, old)
+		} else {
+			os.Unsetenv(ImportPathEnv)
+		}
+	}()
+	os.Setenv(ImportPathEnv, dir1+string(filepath.ListSeparator)+dir2)
+
+	src := `a := kullan("a"); b := kullan("b")`
+	s := importScript(src, nil)
+	if err := s.SetImportDir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := c.Get("a").String(), c.Get("b").String(); a != "dir1" || b != "dir2" {
+		t.Fatalf("a = %q, b = %q", a, b)
+	}
+
+	s = importScript(src, moduleFS(map[string]string{
+		"lib/a.lokum": `paylaş "fs"`,
+		"lib/b.lokum": `paylaş "fs"`,
+	}), "lib")
+	if c, err = s.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if a := c.Get("a").String(); a != "fs" {
+		t.Fatalf("fs.FS ile %s kullanılmamalı: a = %q", ImportPathEnv, a)
+	}
+}
+
+func TestImportCycle(t *testing.T) {
+	tests := []struct {
+		files map[string]string
+		want  string
+	}{
+		{map[string]string{
+			"a.lokum": `paylaş kullan("b")`,
+			"b.lokum": `paylaş kullan("a")`,
+		}, "döngüsel modül importu: a.lokum -> b.lokum -> a.lokum"},
+		{map[string]string{
+			"a.lokum":     `paylaş kullan("./alt/b")`,
+			"alt/b.lokum": `paylaş kullan("../c")`,
+			"c.lokum":     `paylaş kullan("a")`,
+		}, "döngüsel modül importu: a.lokum -> alt/b.lokum -> c.lokum -> a.lokum"},
+		{map[string]string{
+			"a.lokum": `paylaş kullan("a")`,
+		}, "döngüsel modül importu: a.lokum -> a.lokum"},
+	}
+	for _, tt := range tests {
+		s := importScript(`x := kullan("a")`, moduleFS(tt.files))
+		_, err := s.Compile()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%v, beklenen %q", err, tt.want)
+		}
+	}
+
+	s := importScript(`
+x := kullan("a")
+y := kullan("b")
+`, moduleFS(map[string]string{
+		"a.lokum":     `paylaş kullan("ortak") + 1`,
+		"b.lokum":     `paylaş kullan("ortak") + 2`,
+		"ortak.lokum": `paylaş 10`,
+	}))
+	c, err := s.Run()
+	if err != nil {
+		t.Fatalf("ortak bağımlılık döngü sayıldı: %v", err)
+	}
+	if x, y := c.Get("x").Int(), c.Get("y").Int(); x != 11 || y != 12 {
+		t.Fatalf("x = %d, y = %d", x, y)
+	}
+}